@@ -0,0 +1,33 @@
+package api
+
+import (
+	"context"
+	"product-service/blob"
+	"product-service/configs"
+	"product-service/gateway"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// PaymentEventHandler applies verified payment webhook events.
+type PaymentEventHandler interface {
+	HandlePaymentEvent(c context.Context, event *gateway.Event) (string, error)
+}
+
+// NewRouter builds the HTTP server that runs next to the gRPC server. It
+// serves the payment provider's webhook and, with the local blob store, the
+// uploaded files.
+func NewRouter(config *configs.Config, provider string, handler PaymentEventHandler, blobs blob.Store, log *logrus.Logger) *gin.Engine {
+	gin.SetMode(gin.ReleaseMode)
+	router := gin.New()
+	router.Use(gin.Recovery())
+
+	webhook := newWebhookHandler(config, provider, handler, log)
+	router.POST("/webhooks/payments/:provider", webhook.handle)
+
+	if local, ok := blobs.(*blob.LocalStore); ok && local.BaseURL() != "" {
+		router.Static(local.BaseURL(), local.Dir())
+	}
+	return router
+}
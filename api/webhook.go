@@ -0,0 +1,75 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"product-service/configs"
+	"product-service/gateway"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// maxWebhookBody caps the size of an accepted webhook payload.
+const maxWebhookBody = 1 << 20
+
+type webhookHandler struct {
+	provider  string
+	secret    string
+	tolerance time.Duration
+	handler   PaymentEventHandler
+	log       *logrus.Logger
+	// now is replaced in tests to check signatures against fixed timestamps.
+	now func() time.Time
+}
+
+func newWebhookHandler(config *configs.Config, provider string, handler PaymentEventHandler, log *logrus.Logger) *webhookHandler {
+	return &webhookHandler{
+		provider:  provider,
+		secret:    config.PaymentConfig.WebhookSecret,
+		tolerance: config.PaymentConfig.WebhookTolerance,
+		handler:   handler,
+		log:       log,
+		now:       time.Now,
+	}
+}
+
+// handle verifies and applies a payment event. The provider retries anything
+// but a 2xx, so only failures worth retrying answer with a 5xx.
+func (h *webhookHandler) handle(ctx *gin.Context) {
+	if ctx.Param("provider") != h.provider {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "unknown payment provider"})
+		return
+	}
+	if h.secret == "" {
+		h.log.Errorf("payment webhook secret is not configured")
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": "webhook is not configured"})
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(ctx.Request.Body, maxWebhookBody))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "cannot read body"})
+		return
+	}
+
+	if err := gateway.VerifySignature(h.secret, ctx.GetHeader(gateway.SignatureHeader), body, h.now(), h.tolerance); err != nil {
+		h.log.Errorf("rejected payment webhook: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid signature"})
+		return
+	}
+
+	event, err := gateway.ParseEvent(body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	outcome, err := h.handler.HandlePaymentEvent(ctx.Request.Context(), event)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "event could not be processed"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"event_id": event.Id, "status": outcome})
+}
@@ -0,0 +1,157 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"product-service/blob"
+	"product-service/configs"
+	"product-service/gateway"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSecret = "whsec_test"
+
+// fakeEventHandler processes each event id once, like the repository does.
+type fakeEventHandler struct {
+	seen   map[string]bool
+	events []*gateway.Event
+}
+
+func (h *fakeEventHandler) HandlePaymentEvent(c context.Context, event *gateway.Event) (string, error) {
+	if h.seen[event.Id] {
+		return "duplicate", nil
+	}
+	h.seen[event.Id] = true
+	h.events = append(h.events, event)
+	return "processed", nil
+}
+
+var testConfig = &configs.Config{PaymentConfig: configs.PaymentConfig{WebhookSecret: testSecret, WebhookTolerance: 5 * time.Minute}}
+
+func testLogger() *logrus.Logger {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	return log
+}
+
+// newTestRouter serves the webhook with a clock stopped at now, so the signed
+// fixtures keep verifying.
+func newTestRouter(now time.Time) (http.Handler, *fakeEventHandler) {
+	handler := &fakeEventHandler{seen: make(map[string]bool)}
+	webhook := newWebhookHandler(testConfig, "fake", handler, testLogger())
+	webhook.now = func() time.Time { return now }
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/webhooks/payments/:provider", webhook.handle)
+	return router, handler
+}
+
+func fixture(t *testing.T, name string) []byte {
+	body, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return body
+}
+
+func post(router http.Handler, path string, body []byte, signature string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+	req.Header.Set(gateway.SignatureHeader, signature)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func status(t *testing.T, rec *httptest.ResponseRecorder) string {
+	var res map[string]string
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	return res["status"]
+}
+
+func TestWebhookSignedFixtures(t *testing.T) {
+	now := time.Unix(1721900000, 0)
+	router, handler := newTestRouter(now)
+	path := "/webhooks/payments/fake"
+
+	captured := fixture(t, "payment_captured.json")
+	rec := post(router, path, captured, gateway.Sign(testSecret, now, captured))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "processed", status(t, rec))
+
+	// The provider retries the same event: it is acknowledged, not re-applied.
+	rec = post(router, path, captured, gateway.Sign(testSecret, now.Add(time.Minute), captured))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "duplicate", status(t, rec))
+
+	failed := fixture(t, "payment_failed.json")
+	rec = post(router, path, failed, gateway.Sign(testSecret, now, failed))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	assert.Equal(t, 2, len(handler.events))
+	assert.Equal(t, "fake_000001", handler.events[0].Data.TransactionId)
+	assert.Equal(t, "insufficient_funds", handler.events[1].Data.DeclineCode)
+}
+
+func TestWebhookRejectsBadSignatures(t *testing.T) {
+	now := time.Unix(1721900000, 0)
+	router, handler := newTestRouter(now)
+	path := "/webhooks/payments/fake"
+	captured := fixture(t, "payment_captured.json")
+
+	rec := post(router, path, captured, gateway.Sign("wrong-secret", now, captured))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	tampered := bytes.Replace(captured, []byte("50.00"), []byte("5000.00"), 1)
+	rec = post(router, path, tampered, gateway.Sign(testSecret, now, captured))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// A correctly signed request replayed after the tolerance is refused.
+	rec = post(router, path, captured, gateway.Sign(testSecret, now.Add(-10*time.Minute), captured))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = post(router, path, captured, "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = post(router, "/webhooks/payments/other", captured, gateway.Sign(testSecret, now, captured))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	assert.Equal(t, 0, len(handler.events))
+}
+
+func TestNewRouter(t *testing.T) {
+	handler := &fakeEventHandler{seen: make(map[string]bool)}
+	router := NewRouter(testConfig, "fake", handler, nil, testLogger())
+
+	captured := fixture(t, "payment_captured.json")
+	rec := post(router, "/webhooks/payments/fake", captured, gateway.Sign(testSecret, time.Now(), captured))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 1, len(handler.events))
+}
+
+func TestNewRouterServesLocalBlobs(t *testing.T) {
+	store, err := blob.NewLocalStore(t.TempDir(), "/uploads")
+	assert.NoError(t, err)
+	assert.NoError(t, store.Put(context.Background(), "ratings/r-1/p-1.png", []byte("png")))
+	router := NewRouter(testConfig, "fake", &fakeEventHandler{seen: make(map[string]bool)}, store, testLogger())
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/uploads/ratings/r-1/p-1.png", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "png", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/uploads/ratings/r-1/missing.png", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,47 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrNotFound is returned for a key the store holds nothing under.
+var ErrNotFound = errors.New("blob not found")
+
+// validKey allows slash-separated keys of letters, digits, dots, dashes and
+// underscores, so a key can never name something outside the store.
+var validKey = regexp.MustCompile(`^[A-Za-z0-9_-][A-Za-z0-9._-]*(/[A-Za-z0-9_-][A-Za-z0-9._-]*)*$`)
+
+// Store is implemented by every backend uploaded files can be kept in. Keys
+// are chosen by the caller and must pass ValidateKey.
+type Store interface {
+	Name() string
+	Put(c context.Context, key string, data []byte) error
+	Get(c context.Context, key string) ([]byte, error)
+	// Delete removes the blob under key. Deleting a missing blob is not an
+	// error.
+	Delete(c context.Context, key string) error
+	// URL is where clients can download the blob from.
+	URL(key string) string
+}
+
+// ValidateKey checks that a key is safe to store a blob under.
+func ValidateKey(key string) error {
+	if !validKey.MatchString(key) || strings.Contains(key, "..") {
+		return fmt.Errorf("invalid blob key %q", key)
+	}
+	return nil
+}
+
+// NewStore returns the blob store registered under name.
+func NewStore(name, dir, baseURL string) (Store, error) {
+	switch name {
+	case "local", "":
+		return NewLocalStore(dir, baseURL)
+	default:
+		return nil, fmt.Errorf("unknown blob store: %s", name)
+	}
+}
@@ -0,0 +1,44 @@
+package blob
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewLocalStore(dir, "/uploads/")
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	assert.NoError(t, store.Put(ctx, "ratings/r-1/p-1.jpg", []byte("jpeg")))
+	data, err := store.Get(ctx, "ratings/r-1/p-1.jpg")
+	assert.NoError(t, err)
+	assert.Equal(t, "jpeg", string(data))
+	assert.FileExists(t, filepath.Join(dir, "ratings", "r-1", "p-1.jpg"))
+	assert.Equal(t, "/uploads/ratings/r-1/p-1.jpg", store.URL("ratings/r-1/p-1.jpg"))
+
+	entries, err := os.ReadDir(filepath.Join(dir, "ratings", "r-1"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(entries))
+
+	assert.NoError(t, store.Delete(ctx, "ratings/r-1/p-1.jpg"))
+	_, err = store.Get(ctx, "ratings/r-1/p-1.jpg")
+	assert.Equal(t, ErrNotFound, err)
+	assert.NoError(t, store.Delete(ctx, "ratings/r-1/p-1.jpg"))
+}
+
+func TestValidateKey(t *testing.T) {
+	assert.NoError(t, ValidateKey("ratings/r-1/p-1.webp"))
+	for _, key := range []string{"", "../etc/passwd", "ratings/../../x", "/abs", "a//b", "a/", ".hidden", `a\b`} {
+		assert.Error(t, ValidateKey(key), key)
+	}
+
+	store, err := NewLocalStore(t.TempDir(), "/uploads")
+	assert.NoError(t, err)
+	assert.Error(t, store.Put(context.Background(), "../escape", []byte("x")))
+}
@@ -0,0 +1,110 @@
+package carrier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-service/models"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrUnknownShipment is returned for a tracking number the carrier did not
+// issue.
+var ErrUnknownShipment = errors.New("unknown shipment")
+
+// Carrier is implemented by every shipping carrier the service can book
+// parcels with. Tracking is pulled: callers poll Track and record the events
+// they have not seen yet.
+type Carrier interface {
+	// Name is the carrier as stored in the carrier column of shipments.
+	Name() string
+	CreateShipment(c context.Context, request *ShipmentRequest) (*Shipment, error)
+	GetLabel(c context.Context, trackingNumber string) (*Label, error)
+	// Track returns every event of the parcel so far, oldest first.
+	Track(c context.Context, trackingNumber string) ([]*models.TrackingEvent, error)
+}
+
+type ShipmentRequest struct {
+	// Reference is the order the parcel belongs to.
+	Reference   string
+	Method      string
+	Address     *models.ShippingAddress
+	WeightGrams int64
+}
+
+type Shipment struct {
+	TrackingNumber    string
+	EstimatedDelivery *time.Time
+}
+
+// Label is the printable shipping label of a parcel.
+type Label struct {
+	ContentType string
+	Data        []byte
+}
+
+// Registry holds the carriers the service is integrated with, keyed by
+// name regardless of case.
+type Registry struct {
+	carriers map[string]Carrier
+}
+
+func NewRegistry(carriers ...Carrier) *Registry {
+	r := &Registry{carriers: make(map[string]Carrier)}
+	for _, carrier := range carriers {
+		r.Register(carrier)
+	}
+	return r
+}
+
+func (r *Registry) Register(carrier Carrier) {
+	r.carriers[strings.ToLower(carrier.Name())] = carrier
+}
+
+// Get returns the carrier registered under name.
+func (r *Registry) Get(name string) (Carrier, error) {
+	carrier, ok := r.carriers[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return nil, fmt.Errorf("carrier %s is not integrated", name)
+	}
+	return carrier, nil
+}
+
+// Names lists the registered carriers in alphabetical order.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.carriers))
+	for name := range r.carriers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewCarrier returns the carrier registered under name.
+func NewCarrier(name string) (Carrier, error) {
+	switch name {
+	case "mock":
+		return NewMockCarrier(), nil
+	default:
+		return nil, fmt.Errorf("unknown carrier: %s", name)
+	}
+}
+
+// NewRegistryFromNames builds a registry of the named carriers.
+func NewRegistryFromNames(names []string) (*Registry, error) {
+	r := NewRegistry()
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		carrier, err := NewCarrier(name)
+		if err != nil {
+			return nil, err
+		}
+		r.Register(carrier)
+	}
+	return r, nil
+}
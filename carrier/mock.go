@@ -0,0 +1,116 @@
+package carrier
+
+import (
+	"context"
+	"fmt"
+	"product-service/models"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MockUndeliverableZipCode makes the mock carrier fail the delivery attempt
+// with an exception instead of delivering.
+const MockUndeliverableZipCode = "00000"
+
+type mockParcel struct {
+	request   ShipmentRequest
+	createdAt time.Time
+}
+
+// MockCarrier is an in-process carrier for tests and local development. It
+// forgets its parcels when the process stops, so it is not enabled by
+// default; its tracking numbers are random so they never repeat. Every
+// parcel follows the same timeline from the moment it was booked, so Track
+// only depends on the clock: in transit after 12 hours, out for delivery
+// after 48 and delivered after 52.
+type MockCarrier struct {
+	// Now is the mock carrier's clock.
+	Now func() time.Time
+
+	mu      sync.Mutex
+	parcels map[string]*mockParcel
+}
+
+func NewMockCarrier() *MockCarrier {
+	return &MockCarrier{Now: time.Now, parcels: make(map[string]*mockParcel)}
+}
+
+func (m *MockCarrier) Name() string {
+	return "mock"
+}
+
+func (m *MockCarrier) CreateShipment(c context.Context, request *ShipmentRequest) (*Shipment, error) {
+	if request.Address == nil || request.Address.Country == "" || request.Address.ZipCode == "" {
+		return nil, fmt.Errorf("mock carrier: destination country and zip code are required")
+	}
+	if request.WeightGrams < 0 {
+		return nil, fmt.Errorf("mock carrier: invalid weight")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	trackingNumber := "MOCK" + strings.ToUpper(strings.ReplaceAll(uuid.NewString(), "-", "")[:16])
+	createdAt := m.Now().UTC().Truncate(time.Second)
+	m.parcels[trackingNumber] = &mockParcel{request: *request, createdAt: createdAt}
+
+	eta := createdAt.Add(52 * time.Hour).Truncate(24 * time.Hour)
+	return &Shipment{TrackingNumber: trackingNumber, EstimatedDelivery: &eta}, nil
+}
+
+func (m *MockCarrier) GetLabel(c context.Context, trackingNumber string) (*Label, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	parcel, ok := m.parcels[trackingNumber]
+	if !ok {
+		return nil, ErrUnknownShipment
+	}
+	address := parcel.request.Address
+	data := fmt.Sprintf("MOCK CARRIER\nTracking: %s\nOrder: %s\nMethod: %s\nWeight: %dg\nShip to: %s\n%s\n%s %s\n%s\n",
+		trackingNumber, parcel.request.Reference, parcel.request.Method, parcel.request.WeightGrams,
+		address.RecipientName, address.Street, address.ZipCode, address.City, address.Country)
+	return &Label{ContentType: "text/plain", Data: []byte(data)}, nil
+}
+
+func (m *MockCarrier) Track(c context.Context, trackingNumber string) ([]*models.TrackingEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	parcel, ok := m.parcels[trackingNumber]
+	if !ok {
+		return nil, ErrUnknownShipment
+	}
+
+	last := models.TrackingEvent{Status: models.TrackingDelivered, Location: parcel.request.Address.City, Description: "Delivered"}
+	if parcel.request.Address.ZipCode == MockUndeliverableZipCode {
+		last = models.TrackingEvent{Status: models.TrackingException, Location: parcel.request.Address.City, Description: "Address not found"}
+	}
+	timeline := []struct {
+		after time.Duration
+		event models.TrackingEvent
+	}{
+		{0, models.TrackingEvent{Status: models.TrackingLabelCreated, Description: "Label created"}},
+		{12 * time.Hour, models.TrackingEvent{Status: models.TrackingInTransit, Location: "Mock hub", Description: "Departed sorting center"}},
+		{48 * time.Hour, models.TrackingEvent{Status: models.TrackingOutForDelivery, Location: parcel.request.Address.City, Description: "Out for delivery"}},
+		{52 * time.Hour, last},
+	}
+
+	now := m.Now()
+	var events []*models.TrackingEvent
+	for _, step := range timeline {
+		occurredAt := parcel.createdAt.Add(step.after)
+		if occurredAt.After(now) {
+			break
+		}
+		event := step.event
+		event.Carrier = m.Name()
+		event.TrackingNumber = trackingNumber
+		event.OccurredAt = occurredAt
+		events = append(events, &event)
+	}
+	return events, nil
+}
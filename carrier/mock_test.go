@@ -0,0 +1,82 @@
+package carrier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"product-service/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMockCarrierTimeline(t *testing.T) {
+	booked := time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC)
+	now := booked
+	mock := NewMockCarrier()
+	mock.Now = func() time.Time { return now }
+	ctx := context.Background()
+
+	shipment, err := mock.CreateShipment(ctx, &ShipmentRequest{
+		Reference:   "order-1",
+		Method:      "standard",
+		Address:     &models.ShippingAddress{Street: "Main 1", City: "Berlin", Country: "DE", ZipCode: "10115"},
+		WeightGrams: 1200,
+	})
+	assert.NoError(t, err)
+	assert.Regexp(t, "^MOCK[0-9A-F]{16}$", shipment.TrackingNumber)
+	assert.Equal(t, time.Date(2024, 7, 3, 0, 0, 0, 0, time.UTC), *shipment.EstimatedDelivery)
+
+	events, err := mock.Track(ctx, shipment.TrackingNumber)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(events))
+	assert.Equal(t, models.TrackingLabelCreated, events[0].Status)
+	assert.Equal(t, "mock", events[0].Carrier)
+
+	now = booked.Add(60 * time.Hour)
+	events, err = mock.Track(ctx, shipment.TrackingNumber)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, len(events))
+	assert.Equal(t, models.TrackingDelivered, events[3].Status)
+	assert.Equal(t, booked.Add(52*time.Hour), events[3].OccurredAt)
+
+	label, err := mock.GetLabel(ctx, shipment.TrackingNumber)
+	assert.NoError(t, err)
+	assert.Contains(t, string(label.Data), shipment.TrackingNumber)
+
+	_, err = mock.Track(ctx, "MOCK9999999999")
+	assert.Equal(t, ErrUnknownShipment, err)
+}
+
+func TestMockCarrierUndeliverable(t *testing.T) {
+	booked := time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC)
+	mock := NewMockCarrier()
+	mock.Now = func() time.Time { return booked }
+	ctx := context.Background()
+
+	shipment, err := mock.CreateShipment(ctx, &ShipmentRequest{
+		Address: &models.ShippingAddress{City: "Nowhere", Country: "DE", ZipCode: MockUndeliverableZipCode},
+	})
+	assert.NoError(t, err)
+
+	mock.Now = func() time.Time { return booked.Add(72 * time.Hour) }
+	events, err := mock.Track(ctx, shipment.TrackingNumber)
+	assert.NoError(t, err)
+	assert.Equal(t, models.TrackingException, events[len(events)-1].Status)
+}
+
+func TestRegistry(t *testing.T) {
+	registry, err := NewRegistryFromNames([]string{"mock", " "})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"mock"}, registry.Names())
+
+	carrier, err := registry.Get("MOCK")
+	assert.NoError(t, err)
+	assert.Equal(t, "mock", carrier.Name())
+
+	_, err = registry.Get("dhl")
+	assert.EqualError(t, err, "carrier dhl is not integrated")
+
+	_, err = NewRegistryFromNames([]string{"pigeon"})
+	assert.EqualError(t, err, "unknown carrier: pigeon")
+}
@@ -2,11 +2,18 @@ package main
 
 import (
 	"net"
+	"product-service/api"
+	"product-service/blob"
+	"product-service/carrier"
 	"product-service/configs"
+	"product-service/currency"
+	"product-service/gateway"
 	"product-service/genproto/authentication_service"
 	"product-service/genproto/product_service"
 	"product-service/logger"
+	"product-service/notify"
 	"product-service/services"
+	"product-service/shipping"
 	"product-service/storage/postgres"
 
 	"github.com/sirupsen/logrus"
@@ -56,10 +63,49 @@ func main() {
 
 	log.Infof("Initializing product service")
 
-	productService := services.NewProductService(authClient, productRepo, log)
+	paymentProvider, err := gateway.NewProvider(config.PaymentConfig.Provider)
+	if err != nil {
+		log.Fatalf("Error initializing payment provider: %v", err)
+	}
+
+	rateSource, err := currency.NewRateSource(config.CurrencyConfig.ExchangeRateSource, config.CurrencyConfig.ExchangeRatesFile)
+	if err != nil {
+		log.Fatalf("Error initializing exchange rate source: %v", err)
+	}
+
+	shippingRates, err := shipping.LoadTable(config.OrderConfig.ShippingRatesFile)
+	if err != nil {
+		log.Fatalf("Error loading shipping rates: %v", err)
+	}
+
+	carriers, err := carrier.NewRegistryFromNames(config.OrderConfig.Carriers)
+	if err != nil {
+		log.Fatalf("Error initializing carriers: %v", err)
+	}
+
+	notifier, err := notify.NewNotifier(config.NotifyConfig.Notifier, log)
+	if err != nil {
+		log.Fatalf("Error initializing notifier: %v", err)
+	}
+
+	blobs, err := blob.NewStore(config.BlobConfig.Store, config.BlobConfig.Dir, config.BlobConfig.BaseURL)
+	if err != nil {
+		log.Fatalf("Error initializing blob store: %v", err)
+	}
+
+	productService := services.NewProductService(authClient, productRepo, log, config, paymentProvider, rateSource, shippingRates, carriers, notifier, blobs)
 
 	product_service.RegisterProductServiceServer(grpcServer, productService.(product_service.ProductServiceServer))
 
+	// serving payment webhooks and uploaded files
+	router := api.NewRouter(config, paymentProvider.Name(), productService, blobs, log)
+	go func() {
+		log.Infof("HTTP server started on %s", config.Host+":"+config.GinServerPort)
+		if err := router.Run(config.Host + ":" + config.GinServerPort); err != nil {
+			log.Fatalf("Error starting HTTP server: %v", err)
+		}
+	}()
+
 	if err := grpcServer.Serve(listener); err != nil {
 		log.Fatalf("Error starting gRPC server: %v", err)
 	}
@@ -2,6 +2,7 @@ package configs
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -12,6 +13,12 @@ type Config struct {
 	GinServerPort  string         `mapstructure:"GIN_SERVER_PORT"`
 	GrpcServerPort string         `mapstructure:"GRPC_SERVER_PORT"`
 	DatabaseConfig DatabaseConfig `mapstructure:",squash"`
+	OrderConfig    OrderConfig    `mapstructure:",squash"`
+	PaymentConfig  PaymentConfig  `mapstructure:",squash"`
+	CurrencyConfig CurrencyConfig `mapstructure:",squash"`
+	NotifyConfig   NotifyConfig   `mapstructure:",squash"`
+	ReviewConfig   ReviewConfig   `mapstructure:",squash"`
+	BlobConfig     BlobConfig     `mapstructure:",squash"`
 }
 
 type DatabaseConfig struct {
@@ -22,6 +29,67 @@ type DatabaseConfig struct {
 	Name     string `mapstructure:"DB_NAME"`
 }
 
+type OrderConfig struct {
+	// IdempotencyWindow is how long a stored idempotency key is replayed for.
+	IdempotencyWindow time.Duration `mapstructure:"IDEMPOTENCY_WINDOW"`
+	// ReturnWindow is how long after delivery buyers can request a return.
+	ReturnWindow time.Duration `mapstructure:"RETURN_WINDOW"`
+	// ShippingRatesFile holds the shipping methods and rate tables, see
+	// shipping.LoadTable.
+	ShippingRatesFile string `mapstructure:"SHIPPING_RATES_FILE"`
+	// Carriers lists the carriers labels can be booked with, see
+	// carrier.NewCarrier. None are enabled by default; "mock" is only meant
+	// for local development.
+	Carriers []string `mapstructure:"CARRIERS"`
+}
+
+type PaymentConfig struct {
+	// Provider selects the payment gateway, see gateway.NewProvider.
+	Provider string `mapstructure:"PAYMENT_PROVIDER"`
+	// WebhookSecret signs the events the provider posts to the webhook.
+	WebhookSecret string `mapstructure:"PAYMENT_WEBHOOK_SECRET"`
+	// WebhookTolerance is how old a signed webhook event may be.
+	WebhookTolerance time.Duration `mapstructure:"PAYMENT_WEBHOOK_TOLERANCE"`
+	// PendingTimeout is how long a payment may wait for the provider before
+	// a new payment of the order fails it.
+	PendingTimeout time.Duration `mapstructure:"PAYMENT_PENDING_TIMEOUT"`
+}
+
+type CurrencyConfig struct {
+	// DefaultCurrency prices products and orders that do not name a currency.
+	DefaultCurrency string `mapstructure:"DEFAULT_CURRENCY"`
+	// ExchangeRateSource selects the rate feed, see currency.NewRateSource.
+	ExchangeRateSource string `mapstructure:"EXCHANGE_RATE_SOURCE"`
+	// ExchangeRatesFile is read by the static rate source.
+	ExchangeRatesFile string `mapstructure:"EXCHANGE_RATES_FILE"`
+}
+
+type NotifyConfig struct {
+	// Notifier selects how buyer notifications are delivered, see
+	// notify.NewNotifier.
+	Notifier string `mapstructure:"NOTIFIER"`
+}
+
+type ReviewConfig struct {
+	// BannedWords holds reviews whose comment contains any of these words or
+	// phrases back for moderation.
+	BannedWords []string `mapstructure:"REVIEW_BANNED_WORDS"`
+	// MaxPhotoBytes caps the size of a photo attached to a review. It must
+	// stay below the gRPC message limit of 4MB.
+	MaxPhotoBytes int64 `mapstructure:"REVIEW_MAX_PHOTO_BYTES"`
+	// MaxPhotos caps the number of photos attached to one review.
+	MaxPhotos int `mapstructure:"REVIEW_MAX_PHOTOS"`
+}
+
+type BlobConfig struct {
+	// Store selects where uploaded files are kept, see blob.NewStore.
+	Store string `mapstructure:"BLOB_STORE"`
+	// Dir is the directory the local store keeps files in.
+	Dir string `mapstructure:"BLOB_DIR"`
+	// BaseURL is the path the HTTP server serves the local store under.
+	BaseURL string `mapstructure:"BLOB_BASE_URL"`
+}
+
 func InitConfig(path string) (*Config, error) {
 	var config Config
 	if err := LoadConfig(path, &config); err != nil {
@@ -48,6 +116,23 @@ func LoadConfig(path string, config *Config) error {
 	viper.SetDefault("DB_USER", "postgres")
 	viper.SetDefault("DB_PASSWORD", "1702")
 	viper.SetDefault("DB_NAME", "authentication")
+	viper.SetDefault("IDEMPOTENCY_WINDOW", "24h")
+	viper.SetDefault("RETURN_WINDOW", "720h")
+	viper.SetDefault("SHIPPING_RATES_FILE", "shipping_rates.json")
+	viper.SetDefault("CARRIERS", "")
+	viper.SetDefault("PAYMENT_PROVIDER", "fake")
+	viper.SetDefault("PAYMENT_WEBHOOK_TOLERANCE", "5m")
+	viper.SetDefault("PAYMENT_PENDING_TIMEOUT", "15m")
+	viper.SetDefault("DEFAULT_CURRENCY", "USD")
+	viper.SetDefault("EXCHANGE_RATE_SOURCE", "static")
+	viper.SetDefault("EXCHANGE_RATES_FILE", "exchange_rates.json")
+	viper.SetDefault("NOTIFIER", "log")
+	viper.SetDefault("REVIEW_BANNED_WORDS", "")
+	viper.SetDefault("REVIEW_MAX_PHOTO_BYTES", 3<<20)
+	viper.SetDefault("REVIEW_MAX_PHOTOS", 5)
+	viper.SetDefault("BLOB_STORE", "local")
+	viper.SetDefault("BLOB_DIR", "uploads")
+	viper.SetDefault("BLOB_BASE_URL", "/uploads")
 
 	err = viper.Unmarshal(config)
 	if err != nil {
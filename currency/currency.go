@@ -0,0 +1,44 @@
+package currency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-service/models"
+	"strings"
+)
+
+// ErrUnsupported is returned for a currency the rate source has no rate for.
+var ErrUnsupported = errors.New("unsupported currency")
+
+// RateSource is implemented by every exchange-rate feed the service can
+// price orders with.
+type RateSource interface {
+	Name() string
+	// Rate returns the rate that converts an amount in base into quote.
+	Rate(c context.Context, base, quote string) (*models.ExchangeRate, error)
+}
+
+// Normalize validates an ISO 4217 currency code and returns it upper-cased.
+func Normalize(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 3 {
+		return "", fmt.Errorf("invalid currency code %q", code)
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return "", fmt.Errorf("invalid currency code %q", code)
+		}
+	}
+	return code, nil
+}
+
+// NewRateSource returns the exchange-rate source registered under name.
+func NewRateSource(name, path string) (RateSource, error) {
+	switch name {
+	case "static", "":
+		return LoadStaticRates(path)
+	default:
+		return nil, fmt.Errorf("unknown exchange rate source: %s", name)
+	}
+}
@@ -0,0 +1,97 @@
+package currency
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"product-service/models"
+	"strings"
+	"time"
+)
+
+// rateDecimals is the precision cross rates are rounded to, matching the
+// scale of order_exchange_rates.rate.
+const rateDecimals = 10
+
+// StaticRates serves rates from a file that lists, for one base currency, how
+// much of every other currency one unit of the base buys:
+//
+//	{"base": "USD", "as_of": "2024-07-01T00:00:00Z", "rates": {"EUR": "0.92"}}
+//
+// Rates between two non-base currencies are crossed through the base.
+type StaticRates struct {
+	base  string
+	asOf  time.Time
+	rates map[string]*big.Rat
+}
+
+type staticRatesFile struct {
+	Base  string            `json:"base"`
+	AsOf  time.Time         `json:"as_of"`
+	Rates map[string]string `json:"rates"`
+}
+
+// LoadStaticRates reads a rates file.
+func LoadStaticRates(path string) (*StaticRates, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading exchange rates: %v", err)
+	}
+	return ParseStaticRates(data)
+}
+
+// ParseStaticRates decodes the contents of a rates file.
+func ParseStaticRates(data []byte) (*StaticRates, error) {
+	var file staticRatesFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid exchange rates: %v", err)
+	}
+	base, err := Normalize(file.Base)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &StaticRates{
+		base:  base,
+		asOf:  file.AsOf,
+		rates: map[string]*big.Rat{base: big.NewRat(1, 1)},
+	}
+	for code, value := range file.Rates {
+		quote, err := Normalize(code)
+		if err != nil {
+			return nil, err
+		}
+		rate, ok := new(big.Rat).SetString(strings.TrimSpace(value))
+		if !ok || rate.Sign() <= 0 {
+			return nil, fmt.Errorf("invalid exchange rate for %s: %q", quote, value)
+		}
+		s.rates[quote] = rate
+	}
+	return s, nil
+}
+
+func (s *StaticRates) Name() string {
+	return "static"
+}
+
+func (s *StaticRates) Rate(_ context.Context, base, quote string) (*models.ExchangeRate, error) {
+	from, ok := s.rates[base]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupported, base)
+	}
+	to, ok := s.rates[quote]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupported, quote)
+	}
+
+	rate := new(big.Rat).Quo(to, from)
+	return &models.ExchangeRate{
+		Base:   base,
+		Quote:  quote,
+		Rate:   strings.TrimRight(strings.TrimRight(rate.FloatString(rateDecimals), "0"), "."),
+		Source: s.Name(),
+		AsOf:   s.asOf,
+	}, nil
+}
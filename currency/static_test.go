@@ -0,0 +1,70 @@
+package currency
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"product-service/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStaticRates(t *testing.T) {
+	source, err := NewRateSource("static", "testdata/rates.json")
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	rate, err := source.Rate(ctx, "USD", "EUR")
+	assert.NoError(t, err)
+	assert.Equal(t, "0.8", rate.Rate)
+	assert.Equal(t, "static", rate.Source)
+	assert.Equal(t, 2024, rate.AsOf.Year())
+
+	// EUR -> UZS is crossed through USD: 12600 / 0.8.
+	rate, err = source.Rate(ctx, "EUR", "UZS")
+	assert.NoError(t, err)
+	assert.Equal(t, "15750", rate.Rate)
+
+	rate, err = source.Rate(ctx, "GBP", "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.2658227848", rate.Rate)
+
+	_, err = source.Rate(ctx, "USD", "CHF")
+	assert.True(t, errors.Is(err, ErrUnsupported))
+}
+
+func TestConvert(t *testing.T) {
+	source, err := ParseStaticRates([]byte(`{"base": "USD", "rates": {"EUR": "0.8"}}`))
+	assert.NoError(t, err)
+
+	rate, err := source.Rate(context.Background(), "EUR", "USD")
+	assert.NoError(t, err)
+	amount, err := models.Money(1999).Convert(rate.Rate)
+	assert.NoError(t, err)
+	// 19.99 / 0.8 = 24.9875, rounded half away from zero.
+	assert.Equal(t, models.Money(2499), amount)
+
+	amount, err = models.Money(1).Convert("0.5")
+	assert.NoError(t, err)
+	assert.Equal(t, models.Money(1), amount)
+
+	_, err = models.Money(100).Convert("0")
+	assert.Error(t, err)
+}
+
+func TestNormalize(t *testing.T) {
+	code, err := Normalize(" eur ")
+	assert.NoError(t, err)
+	assert.Equal(t, "EUR", code)
+
+	for _, code := range []string{"", "EU", "EURO", "E1R"} {
+		_, err := Normalize(code)
+		assert.Error(t, err, code)
+	}
+
+	_, err = ParseStaticRates([]byte(`{"base": "USD", "rates": {"EUR": "-1"}}`))
+	assert.Error(t, err)
+	_, err = NewRateSource("ecb", "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,136 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"product-service/models"
+	"strings"
+	"sync"
+)
+
+// Fake tokens look like tok_<brand>_<last4>, e.g. tok_visa_4242. Tokens
+// ending in these digits make the fake provider misbehave, in the spirit of
+// the test cards published by real gateways.
+const (
+	FakeTokenPrefix             = "tok_"
+	FakeDeclinedSuffix          = "0002"
+	FakeInsufficientFundsSuffix = "9995"
+	FakeGatewayErrorSuffix      = "0119"
+)
+
+type fakeTransaction struct {
+	authorized models.Money
+	captured   models.Money
+	refunded   models.Money
+	voided     bool
+}
+
+// FakeProvider is an in-process gateway for tests and local development.
+// Outcomes depend only on the source and the operations performed, and
+// transaction ids are issued sequentially, so runs are reproducible.
+type FakeProvider struct {
+	mu           sync.Mutex
+	seq          int
+	transactions map[string]*fakeTransaction
+}
+
+func NewFakeProvider() *FakeProvider {
+	return &FakeProvider{transactions: make(map[string]*fakeTransaction)}
+}
+
+func (f *FakeProvider) Name() string {
+	return "fake"
+}
+
+func (f *FakeProvider) Authorize(c context.Context, request *AuthorizeRequest) (*Result, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case !strings.HasPrefix(request.Source, FakeTokenPrefix):
+		return f.result("authorize", "", request.Amount, "invalid_payment_method"), nil
+	case strings.HasSuffix(request.Source, FakeGatewayErrorSuffix):
+		return nil, fmt.Errorf("fake gateway: processing error")
+	case strings.HasSuffix(request.Source, FakeDeclinedSuffix):
+		return f.result("authorize", "", request.Amount, "card_declined"), nil
+	case strings.HasSuffix(request.Source, FakeInsufficientFundsSuffix):
+		return f.result("authorize", "", request.Amount, "insufficient_funds"), nil
+	case request.Amount <= 0:
+		return f.result("authorize", "", request.Amount, "invalid_amount"), nil
+	}
+
+	f.seq++
+	transactionId := fmt.Sprintf("fake_%06d", f.seq)
+	f.transactions[transactionId] = &fakeTransaction{authorized: request.Amount}
+	return f.result("authorize", transactionId, request.Amount, ""), nil
+}
+
+func (f *FakeProvider) Capture(c context.Context, transactionId string, amount models.Money) (*Result, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	tx, ok := f.transactions[transactionId]
+	switch {
+	case !ok:
+		return f.result("capture", transactionId, amount, "transaction_not_found"), nil
+	case tx.voided:
+		return f.result("capture", transactionId, amount, "transaction_voided"), nil
+	case tx.captured+amount > tx.authorized:
+		return f.result("capture", transactionId, amount, "amount_exceeds_authorization"), nil
+	}
+	tx.captured += amount
+	return f.result("capture", transactionId, amount, ""), nil
+}
+
+func (f *FakeProvider) Void(c context.Context, transactionId string) (*Result, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	tx, ok := f.transactions[transactionId]
+	switch {
+	case !ok:
+		return f.result("void", transactionId, 0, "transaction_not_found"), nil
+	case tx.captured > 0:
+		return f.result("void", transactionId, 0, "transaction_captured"), nil
+	}
+	tx.voided = true
+	return f.result("void", transactionId, tx.authorized, ""), nil
+}
+
+func (f *FakeProvider) Refund(c context.Context, transactionId string, amount models.Money) (*Result, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	tx, ok := f.transactions[transactionId]
+	switch {
+	case !ok:
+		return f.result("refund", transactionId, amount, "transaction_not_found"), nil
+	case tx.refunded+amount > tx.captured:
+		return f.result("refund", transactionId, amount, "amount_exceeds_capture"), nil
+	}
+	tx.refunded += amount
+	return f.result("refund", transactionId, amount, ""), nil
+}
+
+// DescribePaymentMethod reads the brand and last four digits out of a fake
+// token. Every fake card expires in December 2030.
+func (f *FakeProvider) DescribePaymentMethod(c context.Context, token string) (*PaymentMethodDetails, error) {
+	parts := strings.Split(strings.TrimPrefix(token, FakeTokenPrefix), "_")
+	if !strings.HasPrefix(token, FakeTokenPrefix) || len(parts) != 2 || parts[0] == "" || len(parts[1]) != 4 {
+		return nil, ErrInvalidToken
+	}
+	return &PaymentMethodDetails{Brand: parts[0], Last4: parts[1], ExpMonth: 12, ExpYear: 2030}, nil
+}
+
+func (f *FakeProvider) result(operation, transactionId string, amount models.Money, declineCode string) *Result {
+	res := &Result{
+		Operation:     operation,
+		TransactionId: transactionId,
+		Approved:      declineCode == "",
+		Amount:        amount,
+		DeclineCode:   declineCode,
+	}
+	res.Raw, _ = json.Marshal(res)
+	return res
+}
@@ -0,0 +1,80 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeProviderCaptureAndRefund(t *testing.T) {
+	provider := NewFakeProvider()
+	ctx := context.Background()
+
+	auth, err := provider.Authorize(ctx, &AuthorizeRequest{OrderId: "order-1", Amount: 50, Source: "tok_visa_4242"})
+	assert.NoError(t, err)
+	assert.True(t, auth.Approved)
+	assert.Equal(t, "fake_000001", auth.TransactionId)
+
+	capture, err := provider.Capture(ctx, auth.TransactionId, 50)
+	assert.NoError(t, err)
+	assert.True(t, capture.Approved)
+
+	refund, err := provider.Refund(ctx, auth.TransactionId, 20)
+	assert.NoError(t, err)
+	assert.True(t, refund.Approved)
+
+	refund, err = provider.Refund(ctx, auth.TransactionId, 40)
+	assert.NoError(t, err)
+	assert.False(t, refund.Approved)
+	assert.Equal(t, "amount_exceeds_capture", refund.DeclineCode)
+
+	void, err := provider.Void(ctx, auth.TransactionId)
+	assert.NoError(t, err)
+	assert.False(t, void.Approved)
+}
+
+func TestFakeProviderDeclines(t *testing.T) {
+	provider := NewFakeProvider()
+	ctx := context.Background()
+
+	auth, err := provider.Authorize(ctx, &AuthorizeRequest{Amount: 10, Source: "tok_visa_0002"})
+	assert.NoError(t, err)
+	assert.False(t, auth.Approved)
+	assert.Equal(t, "card_declined", auth.DeclineCode)
+
+	auth, err = provider.Authorize(ctx, &AuthorizeRequest{Amount: 10, Source: "tok_visa_9995"})
+	assert.NoError(t, err)
+	assert.Equal(t, "insufficient_funds", auth.DeclineCode)
+
+	_, err = provider.Authorize(ctx, &AuthorizeRequest{Amount: 10, Source: "tok_visa_0119"})
+	assert.Error(t, err)
+
+	auth, err = provider.Authorize(ctx, &AuthorizeRequest{Amount: 10, Source: "tok_visa_4242"})
+	assert.NoError(t, err)
+	void, err := provider.Void(ctx, auth.TransactionId)
+	assert.NoError(t, err)
+	assert.True(t, void.Approved)
+
+	capture, err := provider.Capture(ctx, auth.TransactionId, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, "transaction_voided", capture.DeclineCode)
+}
+
+func TestFakeProviderTokens(t *testing.T) {
+	provider := NewFakeProvider()
+	ctx := context.Background()
+
+	auth, err := provider.Authorize(ctx, &AuthorizeRequest{Amount: 10, Source: "4242424242424242"})
+	assert.NoError(t, err)
+	assert.False(t, auth.Approved)
+	assert.Equal(t, "invalid_payment_method", auth.DeclineCode)
+
+	details, err := provider.DescribePaymentMethod(ctx, "tok_visa_4242")
+	assert.NoError(t, err)
+	assert.Equal(t, "visa", details.Brand)
+	assert.Equal(t, "4242", details.Last4)
+
+	_, err = provider.DescribePaymentMethod(ctx, "4242424242424242")
+	assert.Equal(t, ErrInvalidToken, err)
+}
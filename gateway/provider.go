@@ -0,0 +1,63 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-service/models"
+)
+
+// ErrInvalidToken is returned for a payment method token the provider does
+// not know.
+var ErrInvalidToken = errors.New("invalid payment method token")
+
+// PaymentProvider is implemented by every payment gateway the service can
+// charge through. A declined operation is reported through Result.Approved;
+// the error is reserved for failures talking to the gateway.
+type PaymentProvider interface {
+	Name() string
+	Authorize(c context.Context, request *AuthorizeRequest) (*Result, error)
+	Capture(c context.Context, transactionId string, amount models.Money) (*Result, error)
+	Void(c context.Context, transactionId string) (*Result, error)
+	Refund(c context.Context, transactionId string, amount models.Money) (*Result, error)
+	DescribePaymentMethod(c context.Context, token string) (*PaymentMethodDetails, error)
+}
+
+type AuthorizeRequest struct {
+	OrderId string
+	Amount  models.Money
+	// Currency is the ISO 4217 code the amount is in.
+	Currency      string
+	PaymentMethod string
+	// Source is the payment method token issued by the provider. Raw card
+	// data never reaches the service.
+	Source string
+}
+
+// PaymentMethodDetails describes the card behind a token, for display only.
+type PaymentMethodDetails struct {
+	Brand    string
+	Last4    string
+	ExpMonth int32
+	ExpYear  int32
+}
+
+type Result struct {
+	Operation     string       `json:"operation"`
+	TransactionId string       `json:"transaction_id"`
+	Approved      bool         `json:"approved"`
+	Amount        models.Money `json:"amount"`
+	DeclineCode   string       `json:"decline_code,omitempty"`
+	// Raw is the gateway response as received, kept for auditing.
+	Raw []byte `json:"-"`
+}
+
+// NewProvider returns the payment provider registered under name.
+func NewProvider(name string) (PaymentProvider, error) {
+	switch name {
+	case "fake", "":
+		return NewFakeProvider(), nil
+	default:
+		return nil, fmt.Errorf("unknown payment provider: %s", name)
+	}
+}
@@ -0,0 +1,101 @@
+package gateway
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"product-service/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureHeader carries the webhook signature in the form
+// t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<body>">.
+const SignatureHeader = "X-Webhook-Signature"
+
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// Event is a payment update pushed by a provider to the webhook endpoint.
+type Event struct {
+	Id   string    `json:"id"`
+	Type string    `json:"type"`
+	Data EventData `json:"data"`
+	// Raw is the payload as received, kept for auditing.
+	Raw []byte `json:"-"`
+}
+
+type EventData struct {
+	TransactionId string       `json:"transaction_id"`
+	Amount        models.Money `json:"amount"`
+	DeclineCode   string       `json:"decline_code,omitempty"`
+}
+
+func signature(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Sign returns the signature header value for a payload sent at timestamp.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	t := timestamp.Unix()
+	return fmt.Sprintf("t=%d,v1=%s", t, signature(secret, t, body))
+}
+
+// VerifySignature checks the signature header of a webhook payload. The
+// signed timestamp must be within tolerance of now, so a captured request
+// cannot be replayed later.
+func VerifySignature(secret, header string, body []byte, now time.Time, tolerance time.Duration) error {
+	var timestamp int64
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			t, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return ErrInvalidSignature
+			}
+			timestamp = t
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	if timestamp == 0 || len(signatures) == 0 {
+		return ErrInvalidSignature
+	}
+
+	age := now.Sub(time.Unix(timestamp, 0))
+	if age > tolerance || age < -tolerance {
+		return fmt.Errorf("%w: timestamp outside tolerance", ErrInvalidSignature)
+	}
+
+	expected := []byte(signature(secret, timestamp, body))
+	for _, sig := range signatures {
+		if hmac.Equal(expected, []byte(sig)) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+// ParseEvent decodes a webhook payload.
+func ParseEvent(body []byte) (*Event, error) {
+	var event Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("invalid event payload: %v", err)
+	}
+	if event.Id == "" || event.Type == "" || event.Data.TransactionId == "" {
+		return nil, fmt.Errorf("event id, type and data.transaction_id are required")
+	}
+	event.Raw = body
+	return &event, nil
+}
@@ -44,6 +44,50 @@ type ProductServiceClient interface {
 	GetUserActivity(ctx context.Context, in *GetUserActivityRequest, opts ...grpc.CallOption) (*GetUserActivityResponse, error)
 	GetRecommendations(ctx context.Context, in *GetRecommendationsRequest, opts ...grpc.CallOption) (*GetRecommendationsResponse, error)
 	GetArtisanRankings(ctx context.Context, in *GetArtisanRankingsRequest, opts ...grpc.CallOption) (*GetArtisanRankingsResponse, error)
+	ListArtisanOrders(ctx context.Context, in *ListArtisanOrdersRequest, opts ...grpc.CallOption) (*ListArtisanOrdersResponse, error)
+	ListMyOrders(ctx context.Context, in *ListMyOrdersRequest, opts ...grpc.CallOption) (*ListMyOrdersResponse, error)
+	AddAddress(ctx context.Context, in *AddAddressRequest, opts ...grpc.CallOption) (*AddAddressResponse, error)
+	GetAddresses(ctx context.Context, in *GetAddressesRequest, opts ...grpc.CallOption) (*GetAddressesResponse, error)
+	UpdateAddress(ctx context.Context, in *UpdateAddressRequest, opts ...grpc.CallOption) (*UpdateAddressResponse, error)
+	DeleteAddress(ctx context.Context, in *DeleteAddressRequest, opts ...grpc.CallOption) (*DeleteAddressResponse, error)
+	RequestReturn(ctx context.Context, in *RequestReturnRequest, opts ...grpc.CallOption) (*RequestReturnResponse, error)
+	ApproveReturn(ctx context.Context, in *ResolveReturnRequest, opts ...grpc.CallOption) (*ResolveReturnResponse, error)
+	RejectReturn(ctx context.Context, in *ResolveReturnRequest, opts ...grpc.CallOption) (*ResolveReturnResponse, error)
+	GetReturns(ctx context.Context, in *GetReturnsRequest, opts ...grpc.CallOption) (*GetReturnsResponse, error)
+	RefundPayment(ctx context.Context, in *RefundPaymentRequest, opts ...grpc.CallOption) (*RefundPaymentResponse, error)
+	AddPaymentMethod(ctx context.Context, in *AddPaymentMethodRequest, opts ...grpc.CallOption) (*AddPaymentMethodResponse, error)
+	GetPaymentMethods(ctx context.Context, in *GetPaymentMethodsRequest, opts ...grpc.CallOption) (*GetPaymentMethodsResponse, error)
+	DeletePaymentMethod(ctx context.Context, in *DeletePaymentMethodRequest, opts ...grpc.CallOption) (*DeletePaymentMethodResponse, error)
+	AddTaxRule(ctx context.Context, in *AddTaxRuleRequest, opts ...grpc.CallOption) (*AddTaxRuleResponse, error)
+	GetTaxRules(ctx context.Context, in *GetTaxRulesRequest, opts ...grpc.CallOption) (*GetTaxRulesResponse, error)
+	DeleteTaxRule(ctx context.Context, in *DeleteTaxRuleRequest, opts ...grpc.CallOption) (*DeleteTaxRuleResponse, error)
+	QuoteShipping(ctx context.Context, in *QuoteShippingRequest, opts ...grpc.CallOption) (*QuoteShippingResponse, error)
+	AddCartItem(ctx context.Context, in *AddCartItemRequest, opts ...grpc.CallOption) (*AddCartItemResponse, error)
+	UpdateCartItem(ctx context.Context, in *UpdateCartItemRequest, opts ...grpc.CallOption) (*UpdateCartItemResponse, error)
+	RemoveCartItem(ctx context.Context, in *RemoveCartItemRequest, opts ...grpc.CallOption) (*RemoveCartItemResponse, error)
+	GetCart(ctx context.Context, in *GetCartRequest, opts ...grpc.CallOption) (*GetCartResponse, error)
+	CheckoutCart(ctx context.Context, in *CheckoutCartRequest, opts ...grpc.CallOption) (*PlaceOrderResponse, error)
+	AddPromotion(ctx context.Context, in *AddPromotionRequest, opts ...grpc.CallOption) (*AddPromotionResponse, error)
+	GetPromotions(ctx context.Context, in *GetPromotionsRequest, opts ...grpc.CallOption) (*GetPromotionsResponse, error)
+	DeletePromotion(ctx context.Context, in *DeletePromotionRequest, opts ...grpc.CallOption) (*DeletePromotionResponse, error)
+	CreateWishlist(ctx context.Context, in *CreateWishlistRequest, opts ...grpc.CallOption) (*CreateWishlistResponse, error)
+	GetWishlists(ctx context.Context, in *GetWishlistsRequest, opts ...grpc.CallOption) (*GetWishlistsResponse, error)
+	GetWishlist(ctx context.Context, in *GetWishlistRequest, opts ...grpc.CallOption) (*GetWishlistResponse, error)
+	DeleteWishlist(ctx context.Context, in *DeleteWishlistRequest, opts ...grpc.CallOption) (*DeleteWishlistResponse, error)
+	AddWishlistItem(ctx context.Context, in *AddWishlistItemRequest, opts ...grpc.CallOption) (*AddWishlistItemResponse, error)
+	RemoveWishlistItem(ctx context.Context, in *RemoveWishlistItemRequest, opts ...grpc.CallOption) (*RemoveWishlistItemResponse, error)
+	AddTrackingEvent(ctx context.Context, in *AddTrackingEventRequest, opts ...grpc.CallOption) (*AddTrackingEventResponse, error)
+	GetShipments(ctx context.Context, in *GetShipmentsRequest, opts ...grpc.CallOption) (*GetShipmentsResponse, error)
+	CreateShippingLabel(ctx context.Context, in *CreateShippingLabelRequest, opts ...grpc.CallOption) (*CreateShippingLabelResponse, error)
+	GetShippingLabel(ctx context.Context, in *GetShippingLabelRequest, opts ...grpc.CallOption) (*GetShippingLabelResponse, error)
+	RefreshTracking(ctx context.Context, in *RefreshTrackingRequest, opts ...grpc.CallOption) (*RefreshTrackingResponse, error)
+	ReportRating(ctx context.Context, in *ReportRatingRequest, opts ...grpc.CallOption) (*ReportRatingResponse, error)
+	GetModerationQueue(ctx context.Context, in *GetModerationQueueRequest, opts ...grpc.CallOption) (*GetModerationQueueResponse, error)
+	ModerateRating(ctx context.Context, in *ModerateRatingRequest, opts ...grpc.CallOption) (*ModerateRatingResponse, error)
+	ReplyToRating(ctx context.Context, in *ReplyToRatingRequest, opts ...grpc.CallOption) (*ReplyToRatingResponse, error)
+	VoteRating(ctx context.Context, in *VoteRatingRequest, opts ...grpc.CallOption) (*VoteRatingResponse, error)
+	AddRatingPhoto(ctx context.Context, in *AddRatingPhotoRequest, opts ...grpc.CallOption) (*AddRatingPhotoResponse, error)
+	DeleteRatingPhoto(ctx context.Context, in *DeleteRatingPhotoRequest, opts ...grpc.CallOption) (*DeleteRatingPhotoResponse, error)
 }
 
 type productServiceClient struct {
@@ -252,510 +296,1874 @@ func (c *productServiceClient) GetArtisanRankings(ctx context.Context, in *GetAr
 	return out, nil
 }
 
-// ProductServiceServer is the server API for ProductService service.
-// All implementations must embed UnimplementedProductServiceServer
-// for forward compatibility
-type ProductServiceServer interface {
-	AddProduct(context.Context, *AddProductRequest) (*AddProductResponse, error)
-	EditProduct(context.Context, *EditProductRequest) (*EditProductResponse, error)
-	GetProduct(context.Context, *GetProductRequest) (*GetProductResponse, error)
-	GetProducts(context.Context, *GetProductsRequest) (*GetProductsResponse, error)
-	DeleteProduct(context.Context, *DeleteProductRequest) (*DeleteProductResponse, error)
-	SearchProducts(context.Context, *SearchProductsRequest) (*SearchProductsResponse, error)
-	AddRating(context.Context, *AddRatingRequest) (*AddRatingResponse, error)
-	GetRatings(context.Context, *GetRatingsRequest) (*GetRatingsResponse, error)
-	PlaceOrder(context.Context, *PlaceOrderRequest) (*PlaceOrderResponse, error)
-	CancelOrder(context.Context, *CancelOrderRequest) (*CancelOrderResponse, error)
-	UpdateOrderStatus(context.Context, *UpdateOrderStatusRequest) (*UpdateOrderStatusResponse, error)
-	GetOrders(context.Context, *GetOrdersRequest) (*GetOrdersResponse, error)
-	GetOrder(context.Context, *GetOrderRequest) (*GetOrderResponse, error)
-	PayOrder(context.Context, *PayOrderRequest) (*PayOrderResponse, error)
-	CheckPaymentStatus(context.Context, *CheckPaymentStatusRequest) (*CheckPaymentStatusResponse, error)
-	UpdateShippingInfo(context.Context, *UpdateShippingInfoRequest) (*UpdateShippingInfoResponse, error)
-	AddArtisanCategory(context.Context, *AddArtisanCategoryRequest) (*AddArtisanCategoryResponse, error)
-	AddProductCategory(context.Context, *AddProductCategoryRequest) (*AddProductCategoryResponse, error)
-	GetStatistics(context.Context, *GetStatisticsRequest) (*GetStatisticsResponse, error)
-	GetUserActivity(context.Context, *GetUserActivityRequest) (*GetUserActivityResponse, error)
-	GetRecommendations(context.Context, *GetRecommendationsRequest) (*GetRecommendationsResponse, error)
-	GetArtisanRankings(context.Context, *GetArtisanRankingsRequest) (*GetArtisanRankingsResponse, error)
-	mustEmbedUnimplementedProductServiceServer()
+func (c *productServiceClient) ListArtisanOrders(ctx context.Context, in *ListArtisanOrdersRequest, opts ...grpc.CallOption) (*ListArtisanOrdersResponse, error) {
+	out := new(ListArtisanOrdersResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/ListArtisanOrders", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-// UnimplementedProductServiceServer must be embedded to have forward compatible implementations.
-type UnimplementedProductServiceServer struct {
+func (c *productServiceClient) ListMyOrders(ctx context.Context, in *ListMyOrdersRequest, opts ...grpc.CallOption) (*ListMyOrdersResponse, error) {
+	out := new(ListMyOrdersResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/ListMyOrders", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func (UnimplementedProductServiceServer) AddProduct(context.Context, *AddProductRequest) (*AddProductResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method AddProduct not implemented")
+func (c *productServiceClient) AddAddress(ctx context.Context, in *AddAddressRequest, opts ...grpc.CallOption) (*AddAddressResponse, error) {
+	out := new(AddAddressResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/AddAddress", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedProductServiceServer) EditProduct(context.Context, *EditProductRequest) (*EditProductResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method EditProduct not implemented")
+
+func (c *productServiceClient) GetAddresses(ctx context.Context, in *GetAddressesRequest, opts ...grpc.CallOption) (*GetAddressesResponse, error) {
+	out := new(GetAddressesResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/GetAddresses", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedProductServiceServer) GetProduct(context.Context, *GetProductRequest) (*GetProductResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetProduct not implemented")
+
+func (c *productServiceClient) UpdateAddress(ctx context.Context, in *UpdateAddressRequest, opts ...grpc.CallOption) (*UpdateAddressResponse, error) {
+	out := new(UpdateAddressResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/UpdateAddress", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedProductServiceServer) GetProducts(context.Context, *GetProductsRequest) (*GetProductsResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetProducts not implemented")
+
+func (c *productServiceClient) DeleteAddress(ctx context.Context, in *DeleteAddressRequest, opts ...grpc.CallOption) (*DeleteAddressResponse, error) {
+	out := new(DeleteAddressResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/DeleteAddress", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedProductServiceServer) DeleteProduct(context.Context, *DeleteProductRequest) (*DeleteProductResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DeleteProduct not implemented")
+
+func (c *productServiceClient) RequestReturn(ctx context.Context, in *RequestReturnRequest, opts ...grpc.CallOption) (*RequestReturnResponse, error) {
+	out := new(RequestReturnResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/RequestReturn", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedProductServiceServer) SearchProducts(context.Context, *SearchProductsRequest) (*SearchProductsResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SearchProducts not implemented")
+
+func (c *productServiceClient) ApproveReturn(ctx context.Context, in *ResolveReturnRequest, opts ...grpc.CallOption) (*ResolveReturnResponse, error) {
+	out := new(ResolveReturnResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/ApproveReturn", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedProductServiceServer) AddRating(context.Context, *AddRatingRequest) (*AddRatingResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method AddRating not implemented")
+
+func (c *productServiceClient) RejectReturn(ctx context.Context, in *ResolveReturnRequest, opts ...grpc.CallOption) (*ResolveReturnResponse, error) {
+	out := new(ResolveReturnResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/RejectReturn", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedProductServiceServer) GetRatings(context.Context, *GetRatingsRequest) (*GetRatingsResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetRatings not implemented")
+
+func (c *productServiceClient) GetReturns(ctx context.Context, in *GetReturnsRequest, opts ...grpc.CallOption) (*GetReturnsResponse, error) {
+	out := new(GetReturnsResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/GetReturns", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedProductServiceServer) PlaceOrder(context.Context, *PlaceOrderRequest) (*PlaceOrderResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method PlaceOrder not implemented")
+
+func (c *productServiceClient) RefundPayment(ctx context.Context, in *RefundPaymentRequest, opts ...grpc.CallOption) (*RefundPaymentResponse, error) {
+	out := new(RefundPaymentResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/RefundPayment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedProductServiceServer) CancelOrder(context.Context, *CancelOrderRequest) (*CancelOrderResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CancelOrder not implemented")
+
+func (c *productServiceClient) AddPaymentMethod(ctx context.Context, in *AddPaymentMethodRequest, opts ...grpc.CallOption) (*AddPaymentMethodResponse, error) {
+	out := new(AddPaymentMethodResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/AddPaymentMethod", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedProductServiceServer) UpdateOrderStatus(context.Context, *UpdateOrderStatusRequest) (*UpdateOrderStatusResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method UpdateOrderStatus not implemented")
+
+func (c *productServiceClient) GetPaymentMethods(ctx context.Context, in *GetPaymentMethodsRequest, opts ...grpc.CallOption) (*GetPaymentMethodsResponse, error) {
+	out := new(GetPaymentMethodsResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/GetPaymentMethods", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedProductServiceServer) GetOrders(context.Context, *GetOrdersRequest) (*GetOrdersResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetOrders not implemented")
+
+func (c *productServiceClient) DeletePaymentMethod(ctx context.Context, in *DeletePaymentMethodRequest, opts ...grpc.CallOption) (*DeletePaymentMethodResponse, error) {
+	out := new(DeletePaymentMethodResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/DeletePaymentMethod", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedProductServiceServer) GetOrder(context.Context, *GetOrderRequest) (*GetOrderResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetOrder not implemented")
+
+func (c *productServiceClient) AddTaxRule(ctx context.Context, in *AddTaxRuleRequest, opts ...grpc.CallOption) (*AddTaxRuleResponse, error) {
+	out := new(AddTaxRuleResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/AddTaxRule", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedProductServiceServer) PayOrder(context.Context, *PayOrderRequest) (*PayOrderResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method PayOrder not implemented")
+
+func (c *productServiceClient) GetTaxRules(ctx context.Context, in *GetTaxRulesRequest, opts ...grpc.CallOption) (*GetTaxRulesResponse, error) {
+	out := new(GetTaxRulesResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/GetTaxRules", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedProductServiceServer) CheckPaymentStatus(context.Context, *CheckPaymentStatusRequest) (*CheckPaymentStatusResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CheckPaymentStatus not implemented")
+
+func (c *productServiceClient) DeleteTaxRule(ctx context.Context, in *DeleteTaxRuleRequest, opts ...grpc.CallOption) (*DeleteTaxRuleResponse, error) {
+	out := new(DeleteTaxRuleResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/DeleteTaxRule", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedProductServiceServer) UpdateShippingInfo(context.Context, *UpdateShippingInfoRequest) (*UpdateShippingInfoResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method UpdateShippingInfo not implemented")
+
+func (c *productServiceClient) QuoteShipping(ctx context.Context, in *QuoteShippingRequest, opts ...grpc.CallOption) (*QuoteShippingResponse, error) {
+	out := new(QuoteShippingResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/QuoteShipping", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedProductServiceServer) AddArtisanCategory(context.Context, *AddArtisanCategoryRequest) (*AddArtisanCategoryResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method AddArtisanCategory not implemented")
+
+func (c *productServiceClient) AddCartItem(ctx context.Context, in *AddCartItemRequest, opts ...grpc.CallOption) (*AddCartItemResponse, error) {
+	out := new(AddCartItemResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/AddCartItem", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedProductServiceServer) AddProductCategory(context.Context, *AddProductCategoryRequest) (*AddProductCategoryResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method AddProductCategory not implemented")
+
+func (c *productServiceClient) UpdateCartItem(ctx context.Context, in *UpdateCartItemRequest, opts ...grpc.CallOption) (*UpdateCartItemResponse, error) {
+	out := new(UpdateCartItemResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/UpdateCartItem", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedProductServiceServer) GetStatistics(context.Context, *GetStatisticsRequest) (*GetStatisticsResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetStatistics not implemented")
+
+func (c *productServiceClient) RemoveCartItem(ctx context.Context, in *RemoveCartItemRequest, opts ...grpc.CallOption) (*RemoveCartItemResponse, error) {
+	out := new(RemoveCartItemResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/RemoveCartItem", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedProductServiceServer) GetUserActivity(context.Context, *GetUserActivityRequest) (*GetUserActivityResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetUserActivity not implemented")
+
+func (c *productServiceClient) GetCart(ctx context.Context, in *GetCartRequest, opts ...grpc.CallOption) (*GetCartResponse, error) {
+	out := new(GetCartResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/GetCart", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) CheckoutCart(ctx context.Context, in *CheckoutCartRequest, opts ...grpc.CallOption) (*PlaceOrderResponse, error) {
+	out := new(PlaceOrderResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/CheckoutCart", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) AddPromotion(ctx context.Context, in *AddPromotionRequest, opts ...grpc.CallOption) (*AddPromotionResponse, error) {
+	out := new(AddPromotionResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/AddPromotion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) GetPromotions(ctx context.Context, in *GetPromotionsRequest, opts ...grpc.CallOption) (*GetPromotionsResponse, error) {
+	out := new(GetPromotionsResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/GetPromotions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) DeletePromotion(ctx context.Context, in *DeletePromotionRequest, opts ...grpc.CallOption) (*DeletePromotionResponse, error) {
+	out := new(DeletePromotionResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/DeletePromotion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) CreateWishlist(ctx context.Context, in *CreateWishlistRequest, opts ...grpc.CallOption) (*CreateWishlistResponse, error) {
+	out := new(CreateWishlistResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/CreateWishlist", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) GetWishlists(ctx context.Context, in *GetWishlistsRequest, opts ...grpc.CallOption) (*GetWishlistsResponse, error) {
+	out := new(GetWishlistsResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/GetWishlists", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) GetWishlist(ctx context.Context, in *GetWishlistRequest, opts ...grpc.CallOption) (*GetWishlistResponse, error) {
+	out := new(GetWishlistResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/GetWishlist", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) DeleteWishlist(ctx context.Context, in *DeleteWishlistRequest, opts ...grpc.CallOption) (*DeleteWishlistResponse, error) {
+	out := new(DeleteWishlistResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/DeleteWishlist", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) AddWishlistItem(ctx context.Context, in *AddWishlistItemRequest, opts ...grpc.CallOption) (*AddWishlistItemResponse, error) {
+	out := new(AddWishlistItemResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/AddWishlistItem", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) RemoveWishlistItem(ctx context.Context, in *RemoveWishlistItemRequest, opts ...grpc.CallOption) (*RemoveWishlistItemResponse, error) {
+	out := new(RemoveWishlistItemResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/RemoveWishlistItem", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) AddTrackingEvent(ctx context.Context, in *AddTrackingEventRequest, opts ...grpc.CallOption) (*AddTrackingEventResponse, error) {
+	out := new(AddTrackingEventResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/AddTrackingEvent", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) GetShipments(ctx context.Context, in *GetShipmentsRequest, opts ...grpc.CallOption) (*GetShipmentsResponse, error) {
+	out := new(GetShipmentsResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/GetShipments", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) CreateShippingLabel(ctx context.Context, in *CreateShippingLabelRequest, opts ...grpc.CallOption) (*CreateShippingLabelResponse, error) {
+	out := new(CreateShippingLabelResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/CreateShippingLabel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) GetShippingLabel(ctx context.Context, in *GetShippingLabelRequest, opts ...grpc.CallOption) (*GetShippingLabelResponse, error) {
+	out := new(GetShippingLabelResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/GetShippingLabel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) RefreshTracking(ctx context.Context, in *RefreshTrackingRequest, opts ...grpc.CallOption) (*RefreshTrackingResponse, error) {
+	out := new(RefreshTrackingResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/RefreshTracking", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) ReportRating(ctx context.Context, in *ReportRatingRequest, opts ...grpc.CallOption) (*ReportRatingResponse, error) {
+	out := new(ReportRatingResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/ReportRating", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) GetModerationQueue(ctx context.Context, in *GetModerationQueueRequest, opts ...grpc.CallOption) (*GetModerationQueueResponse, error) {
+	out := new(GetModerationQueueResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/GetModerationQueue", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) ModerateRating(ctx context.Context, in *ModerateRatingRequest, opts ...grpc.CallOption) (*ModerateRatingResponse, error) {
+	out := new(ModerateRatingResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/ModerateRating", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) ReplyToRating(ctx context.Context, in *ReplyToRatingRequest, opts ...grpc.CallOption) (*ReplyToRatingResponse, error) {
+	out := new(ReplyToRatingResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/ReplyToRating", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) VoteRating(ctx context.Context, in *VoteRatingRequest, opts ...grpc.CallOption) (*VoteRatingResponse, error) {
+	out := new(VoteRatingResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/VoteRating", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) AddRatingPhoto(ctx context.Context, in *AddRatingPhotoRequest, opts ...grpc.CallOption) (*AddRatingPhotoResponse, error) {
+	out := new(AddRatingPhotoResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/AddRatingPhoto", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) DeleteRatingPhoto(ctx context.Context, in *DeleteRatingPhotoRequest, opts ...grpc.CallOption) (*DeleteRatingPhotoResponse, error) {
+	out := new(DeleteRatingPhotoResponse)
+	err := c.cc.Invoke(ctx, "/product_service.ProductService/DeleteRatingPhoto", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ProductServiceServer is the server API for ProductService service.
+// All implementations must embed UnimplementedProductServiceServer
+// for forward compatibility
+type ProductServiceServer interface {
+	AddProduct(context.Context, *AddProductRequest) (*AddProductResponse, error)
+	EditProduct(context.Context, *EditProductRequest) (*EditProductResponse, error)
+	GetProduct(context.Context, *GetProductRequest) (*GetProductResponse, error)
+	GetProducts(context.Context, *GetProductsRequest) (*GetProductsResponse, error)
+	DeleteProduct(context.Context, *DeleteProductRequest) (*DeleteProductResponse, error)
+	SearchProducts(context.Context, *SearchProductsRequest) (*SearchProductsResponse, error)
+	AddRating(context.Context, *AddRatingRequest) (*AddRatingResponse, error)
+	GetRatings(context.Context, *GetRatingsRequest) (*GetRatingsResponse, error)
+	PlaceOrder(context.Context, *PlaceOrderRequest) (*PlaceOrderResponse, error)
+	CancelOrder(context.Context, *CancelOrderRequest) (*CancelOrderResponse, error)
+	UpdateOrderStatus(context.Context, *UpdateOrderStatusRequest) (*UpdateOrderStatusResponse, error)
+	GetOrders(context.Context, *GetOrdersRequest) (*GetOrdersResponse, error)
+	GetOrder(context.Context, *GetOrderRequest) (*GetOrderResponse, error)
+	PayOrder(context.Context, *PayOrderRequest) (*PayOrderResponse, error)
+	CheckPaymentStatus(context.Context, *CheckPaymentStatusRequest) (*CheckPaymentStatusResponse, error)
+	UpdateShippingInfo(context.Context, *UpdateShippingInfoRequest) (*UpdateShippingInfoResponse, error)
+	AddArtisanCategory(context.Context, *AddArtisanCategoryRequest) (*AddArtisanCategoryResponse, error)
+	AddProductCategory(context.Context, *AddProductCategoryRequest) (*AddProductCategoryResponse, error)
+	GetStatistics(context.Context, *GetStatisticsRequest) (*GetStatisticsResponse, error)
+	GetUserActivity(context.Context, *GetUserActivityRequest) (*GetUserActivityResponse, error)
+	GetRecommendations(context.Context, *GetRecommendationsRequest) (*GetRecommendationsResponse, error)
+	GetArtisanRankings(context.Context, *GetArtisanRankingsRequest) (*GetArtisanRankingsResponse, error)
+	ListArtisanOrders(context.Context, *ListArtisanOrdersRequest) (*ListArtisanOrdersResponse, error)
+	ListMyOrders(context.Context, *ListMyOrdersRequest) (*ListMyOrdersResponse, error)
+	AddAddress(context.Context, *AddAddressRequest) (*AddAddressResponse, error)
+	GetAddresses(context.Context, *GetAddressesRequest) (*GetAddressesResponse, error)
+	UpdateAddress(context.Context, *UpdateAddressRequest) (*UpdateAddressResponse, error)
+	DeleteAddress(context.Context, *DeleteAddressRequest) (*DeleteAddressResponse, error)
+	RequestReturn(context.Context, *RequestReturnRequest) (*RequestReturnResponse, error)
+	ApproveReturn(context.Context, *ResolveReturnRequest) (*ResolveReturnResponse, error)
+	RejectReturn(context.Context, *ResolveReturnRequest) (*ResolveReturnResponse, error)
+	GetReturns(context.Context, *GetReturnsRequest) (*GetReturnsResponse, error)
+	RefundPayment(context.Context, *RefundPaymentRequest) (*RefundPaymentResponse, error)
+	AddPaymentMethod(context.Context, *AddPaymentMethodRequest) (*AddPaymentMethodResponse, error)
+	GetPaymentMethods(context.Context, *GetPaymentMethodsRequest) (*GetPaymentMethodsResponse, error)
+	DeletePaymentMethod(context.Context, *DeletePaymentMethodRequest) (*DeletePaymentMethodResponse, error)
+	AddTaxRule(context.Context, *AddTaxRuleRequest) (*AddTaxRuleResponse, error)
+	GetTaxRules(context.Context, *GetTaxRulesRequest) (*GetTaxRulesResponse, error)
+	DeleteTaxRule(context.Context, *DeleteTaxRuleRequest) (*DeleteTaxRuleResponse, error)
+	QuoteShipping(context.Context, *QuoteShippingRequest) (*QuoteShippingResponse, error)
+	AddCartItem(context.Context, *AddCartItemRequest) (*AddCartItemResponse, error)
+	UpdateCartItem(context.Context, *UpdateCartItemRequest) (*UpdateCartItemResponse, error)
+	RemoveCartItem(context.Context, *RemoveCartItemRequest) (*RemoveCartItemResponse, error)
+	GetCart(context.Context, *GetCartRequest) (*GetCartResponse, error)
+	CheckoutCart(context.Context, *CheckoutCartRequest) (*PlaceOrderResponse, error)
+	AddPromotion(context.Context, *AddPromotionRequest) (*AddPromotionResponse, error)
+	GetPromotions(context.Context, *GetPromotionsRequest) (*GetPromotionsResponse, error)
+	DeletePromotion(context.Context, *DeletePromotionRequest) (*DeletePromotionResponse, error)
+	CreateWishlist(context.Context, *CreateWishlistRequest) (*CreateWishlistResponse, error)
+	GetWishlists(context.Context, *GetWishlistsRequest) (*GetWishlistsResponse, error)
+	GetWishlist(context.Context, *GetWishlistRequest) (*GetWishlistResponse, error)
+	DeleteWishlist(context.Context, *DeleteWishlistRequest) (*DeleteWishlistResponse, error)
+	AddWishlistItem(context.Context, *AddWishlistItemRequest) (*AddWishlistItemResponse, error)
+	RemoveWishlistItem(context.Context, *RemoveWishlistItemRequest) (*RemoveWishlistItemResponse, error)
+	AddTrackingEvent(context.Context, *AddTrackingEventRequest) (*AddTrackingEventResponse, error)
+	GetShipments(context.Context, *GetShipmentsRequest) (*GetShipmentsResponse, error)
+	CreateShippingLabel(context.Context, *CreateShippingLabelRequest) (*CreateShippingLabelResponse, error)
+	GetShippingLabel(context.Context, *GetShippingLabelRequest) (*GetShippingLabelResponse, error)
+	RefreshTracking(context.Context, *RefreshTrackingRequest) (*RefreshTrackingResponse, error)
+	ReportRating(context.Context, *ReportRatingRequest) (*ReportRatingResponse, error)
+	GetModerationQueue(context.Context, *GetModerationQueueRequest) (*GetModerationQueueResponse, error)
+	ModerateRating(context.Context, *ModerateRatingRequest) (*ModerateRatingResponse, error)
+	ReplyToRating(context.Context, *ReplyToRatingRequest) (*ReplyToRatingResponse, error)
+	VoteRating(context.Context, *VoteRatingRequest) (*VoteRatingResponse, error)
+	AddRatingPhoto(context.Context, *AddRatingPhotoRequest) (*AddRatingPhotoResponse, error)
+	DeleteRatingPhoto(context.Context, *DeleteRatingPhotoRequest) (*DeleteRatingPhotoResponse, error)
+	mustEmbedUnimplementedProductServiceServer()
+}
+
+// UnimplementedProductServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedProductServiceServer struct {
+}
+
+func (UnimplementedProductServiceServer) AddProduct(context.Context, *AddProductRequest) (*AddProductResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddProduct not implemented")
+}
+func (UnimplementedProductServiceServer) EditProduct(context.Context, *EditProductRequest) (*EditProductResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EditProduct not implemented")
+}
+func (UnimplementedProductServiceServer) GetProduct(context.Context, *GetProductRequest) (*GetProductResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProduct not implemented")
+}
+func (UnimplementedProductServiceServer) GetProducts(context.Context, *GetProductsRequest) (*GetProductsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProducts not implemented")
+}
+func (UnimplementedProductServiceServer) DeleteProduct(context.Context, *DeleteProductRequest) (*DeleteProductResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteProduct not implemented")
+}
+func (UnimplementedProductServiceServer) SearchProducts(context.Context, *SearchProductsRequest) (*SearchProductsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchProducts not implemented")
+}
+func (UnimplementedProductServiceServer) AddRating(context.Context, *AddRatingRequest) (*AddRatingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddRating not implemented")
+}
+func (UnimplementedProductServiceServer) GetRatings(context.Context, *GetRatingsRequest) (*GetRatingsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRatings not implemented")
+}
+func (UnimplementedProductServiceServer) PlaceOrder(context.Context, *PlaceOrderRequest) (*PlaceOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PlaceOrder not implemented")
+}
+func (UnimplementedProductServiceServer) CancelOrder(context.Context, *CancelOrderRequest) (*CancelOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelOrder not implemented")
+}
+func (UnimplementedProductServiceServer) UpdateOrderStatus(context.Context, *UpdateOrderStatusRequest) (*UpdateOrderStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateOrderStatus not implemented")
+}
+func (UnimplementedProductServiceServer) GetOrders(context.Context, *GetOrdersRequest) (*GetOrdersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrders not implemented")
+}
+func (UnimplementedProductServiceServer) GetOrder(context.Context, *GetOrderRequest) (*GetOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrder not implemented")
+}
+func (UnimplementedProductServiceServer) PayOrder(context.Context, *PayOrderRequest) (*PayOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PayOrder not implemented")
+}
+func (UnimplementedProductServiceServer) CheckPaymentStatus(context.Context, *CheckPaymentStatusRequest) (*CheckPaymentStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckPaymentStatus not implemented")
+}
+func (UnimplementedProductServiceServer) UpdateShippingInfo(context.Context, *UpdateShippingInfoRequest) (*UpdateShippingInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateShippingInfo not implemented")
+}
+func (UnimplementedProductServiceServer) AddArtisanCategory(context.Context, *AddArtisanCategoryRequest) (*AddArtisanCategoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddArtisanCategory not implemented")
+}
+func (UnimplementedProductServiceServer) AddProductCategory(context.Context, *AddProductCategoryRequest) (*AddProductCategoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddProductCategory not implemented")
+}
+func (UnimplementedProductServiceServer) GetStatistics(context.Context, *GetStatisticsRequest) (*GetStatisticsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatistics not implemented")
+}
+func (UnimplementedProductServiceServer) GetUserActivity(context.Context, *GetUserActivityRequest) (*GetUserActivityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserActivity not implemented")
+}
+func (UnimplementedProductServiceServer) GetRecommendations(context.Context, *GetRecommendationsRequest) (*GetRecommendationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRecommendations not implemented")
+}
+func (UnimplementedProductServiceServer) GetArtisanRankings(context.Context, *GetArtisanRankingsRequest) (*GetArtisanRankingsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetArtisanRankings not implemented")
+}
+func (UnimplementedProductServiceServer) ListArtisanOrders(context.Context, *ListArtisanOrdersRequest) (*ListArtisanOrdersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListArtisanOrders not implemented")
+}
+func (UnimplementedProductServiceServer) ListMyOrders(context.Context, *ListMyOrdersRequest) (*ListMyOrdersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMyOrders not implemented")
+}
+func (UnimplementedProductServiceServer) AddAddress(context.Context, *AddAddressRequest) (*AddAddressResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddAddress not implemented")
+}
+func (UnimplementedProductServiceServer) GetAddresses(context.Context, *GetAddressesRequest) (*GetAddressesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAddresses not implemented")
+}
+func (UnimplementedProductServiceServer) UpdateAddress(context.Context, *UpdateAddressRequest) (*UpdateAddressResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateAddress not implemented")
+}
+func (UnimplementedProductServiceServer) DeleteAddress(context.Context, *DeleteAddressRequest) (*DeleteAddressResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAddress not implemented")
+}
+func (UnimplementedProductServiceServer) RequestReturn(context.Context, *RequestReturnRequest) (*RequestReturnResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestReturn not implemented")
+}
+func (UnimplementedProductServiceServer) ApproveReturn(context.Context, *ResolveReturnRequest) (*ResolveReturnResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveReturn not implemented")
+}
+func (UnimplementedProductServiceServer) RejectReturn(context.Context, *ResolveReturnRequest) (*ResolveReturnResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RejectReturn not implemented")
+}
+func (UnimplementedProductServiceServer) GetReturns(context.Context, *GetReturnsRequest) (*GetReturnsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetReturns not implemented")
+}
+func (UnimplementedProductServiceServer) RefundPayment(context.Context, *RefundPaymentRequest) (*RefundPaymentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RefundPayment not implemented")
+}
+func (UnimplementedProductServiceServer) AddPaymentMethod(context.Context, *AddPaymentMethodRequest) (*AddPaymentMethodResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddPaymentMethod not implemented")
+}
+func (UnimplementedProductServiceServer) GetPaymentMethods(context.Context, *GetPaymentMethodsRequest) (*GetPaymentMethodsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPaymentMethods not implemented")
+}
+func (UnimplementedProductServiceServer) DeletePaymentMethod(context.Context, *DeletePaymentMethodRequest) (*DeletePaymentMethodResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeletePaymentMethod not implemented")
+}
+func (UnimplementedProductServiceServer) AddTaxRule(context.Context, *AddTaxRuleRequest) (*AddTaxRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddTaxRule not implemented")
+}
+func (UnimplementedProductServiceServer) GetTaxRules(context.Context, *GetTaxRulesRequest) (*GetTaxRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTaxRules not implemented")
+}
+func (UnimplementedProductServiceServer) DeleteTaxRule(context.Context, *DeleteTaxRuleRequest) (*DeleteTaxRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTaxRule not implemented")
+}
+func (UnimplementedProductServiceServer) QuoteShipping(context.Context, *QuoteShippingRequest) (*QuoteShippingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QuoteShipping not implemented")
+}
+func (UnimplementedProductServiceServer) AddCartItem(context.Context, *AddCartItemRequest) (*AddCartItemResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddCartItem not implemented")
+}
+func (UnimplementedProductServiceServer) UpdateCartItem(context.Context, *UpdateCartItemRequest) (*UpdateCartItemResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateCartItem not implemented")
+}
+func (UnimplementedProductServiceServer) RemoveCartItem(context.Context, *RemoveCartItemRequest) (*RemoveCartItemResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveCartItem not implemented")
+}
+func (UnimplementedProductServiceServer) GetCart(context.Context, *GetCartRequest) (*GetCartResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCart not implemented")
+}
+func (UnimplementedProductServiceServer) CheckoutCart(context.Context, *CheckoutCartRequest) (*PlaceOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckoutCart not implemented")
+}
+func (UnimplementedProductServiceServer) AddPromotion(context.Context, *AddPromotionRequest) (*AddPromotionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddPromotion not implemented")
+}
+func (UnimplementedProductServiceServer) GetPromotions(context.Context, *GetPromotionsRequest) (*GetPromotionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPromotions not implemented")
+}
+func (UnimplementedProductServiceServer) DeletePromotion(context.Context, *DeletePromotionRequest) (*DeletePromotionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeletePromotion not implemented")
+}
+func (UnimplementedProductServiceServer) CreateWishlist(context.Context, *CreateWishlistRequest) (*CreateWishlistResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWishlist not implemented")
+}
+func (UnimplementedProductServiceServer) GetWishlists(context.Context, *GetWishlistsRequest) (*GetWishlistsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWishlists not implemented")
+}
+func (UnimplementedProductServiceServer) GetWishlist(context.Context, *GetWishlistRequest) (*GetWishlistResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWishlist not implemented")
+}
+func (UnimplementedProductServiceServer) DeleteWishlist(context.Context, *DeleteWishlistRequest) (*DeleteWishlistResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWishlist not implemented")
+}
+func (UnimplementedProductServiceServer) AddWishlistItem(context.Context, *AddWishlistItemRequest) (*AddWishlistItemResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddWishlistItem not implemented")
+}
+func (UnimplementedProductServiceServer) RemoveWishlistItem(context.Context, *RemoveWishlistItemRequest) (*RemoveWishlistItemResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveWishlistItem not implemented")
+}
+func (UnimplementedProductServiceServer) AddTrackingEvent(context.Context, *AddTrackingEventRequest) (*AddTrackingEventResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddTrackingEvent not implemented")
+}
+func (UnimplementedProductServiceServer) GetShipments(context.Context, *GetShipmentsRequest) (*GetShipmentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetShipments not implemented")
+}
+func (UnimplementedProductServiceServer) CreateShippingLabel(context.Context, *CreateShippingLabelRequest) (*CreateShippingLabelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateShippingLabel not implemented")
+}
+func (UnimplementedProductServiceServer) GetShippingLabel(context.Context, *GetShippingLabelRequest) (*GetShippingLabelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetShippingLabel not implemented")
+}
+func (UnimplementedProductServiceServer) RefreshTracking(context.Context, *RefreshTrackingRequest) (*RefreshTrackingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RefreshTracking not implemented")
+}
+func (UnimplementedProductServiceServer) ReportRating(context.Context, *ReportRatingRequest) (*ReportRatingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportRating not implemented")
+}
+func (UnimplementedProductServiceServer) GetModerationQueue(context.Context, *GetModerationQueueRequest) (*GetModerationQueueResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetModerationQueue not implemented")
+}
+func (UnimplementedProductServiceServer) ModerateRating(context.Context, *ModerateRatingRequest) (*ModerateRatingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ModerateRating not implemented")
+}
+func (UnimplementedProductServiceServer) ReplyToRating(context.Context, *ReplyToRatingRequest) (*ReplyToRatingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplyToRating not implemented")
+}
+func (UnimplementedProductServiceServer) VoteRating(context.Context, *VoteRatingRequest) (*VoteRatingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VoteRating not implemented")
+}
+func (UnimplementedProductServiceServer) AddRatingPhoto(context.Context, *AddRatingPhotoRequest) (*AddRatingPhotoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddRatingPhoto not implemented")
+}
+func (UnimplementedProductServiceServer) DeleteRatingPhoto(context.Context, *DeleteRatingPhotoRequest) (*DeleteRatingPhotoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteRatingPhoto not implemented")
+}
+func (UnimplementedProductServiceServer) mustEmbedUnimplementedProductServiceServer() {}
+
+// UnsafeProductServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ProductServiceServer will
+// result in compilation errors.
+type UnsafeProductServiceServer interface {
+	mustEmbedUnimplementedProductServiceServer()
+}
+
+func RegisterProductServiceServer(s grpc.ServiceRegistrar, srv ProductServiceServer) {
+	s.RegisterService(&ProductService_ServiceDesc, srv)
+}
+
+func _ProductService_AddProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).AddProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/AddProduct",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).AddProduct(ctx, req.(*AddProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_EditProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EditProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).EditProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/EditProduct",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).EditProduct(ctx, req.(*EditProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_GetProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).GetProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/GetProduct",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).GetProduct(ctx, req.(*GetProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_GetProducts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProductsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).GetProducts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/GetProducts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).GetProducts(ctx, req.(*GetProductsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_DeleteProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).DeleteProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/DeleteProduct",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).DeleteProduct(ctx, req.(*DeleteProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_SearchProducts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchProductsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).SearchProducts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/SearchProducts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).SearchProducts(ctx, req.(*SearchProductsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_AddRating_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddRatingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).AddRating(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/AddRating",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).AddRating(ctx, req.(*AddRatingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_GetRatings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRatingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).GetRatings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/GetRatings",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).GetRatings(ctx, req.(*GetRatingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_PlaceOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlaceOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).PlaceOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/PlaceOrder",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).PlaceOrder(ctx, req.(*PlaceOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_CancelOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).CancelOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/CancelOrder",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).CancelOrder(ctx, req.(*CancelOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_UpdateOrderStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateOrderStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).UpdateOrderStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/UpdateOrderStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).UpdateOrderStatus(ctx, req.(*UpdateOrderStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_GetOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrdersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).GetOrders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/GetOrders",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).GetOrders(ctx, req.(*GetOrdersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_GetOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).GetOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/GetOrder",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).GetOrder(ctx, req.(*GetOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_PayOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PayOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).PayOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/PayOrder",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).PayOrder(ctx, req.(*PayOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_CheckPaymentStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckPaymentStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).CheckPaymentStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/CheckPaymentStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).CheckPaymentStatus(ctx, req.(*CheckPaymentStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_UpdateShippingInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateShippingInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).UpdateShippingInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/UpdateShippingInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).UpdateShippingInfo(ctx, req.(*UpdateShippingInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_AddArtisanCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddArtisanCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).AddArtisanCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/AddArtisanCategory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).AddArtisanCategory(ctx, req.(*AddArtisanCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_AddProductCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddProductCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).AddProductCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/AddProductCategory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).AddProductCategory(ctx, req.(*AddProductCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_GetStatistics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatisticsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).GetStatistics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/GetStatistics",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).GetStatistics(ctx, req.(*GetStatisticsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_GetUserActivity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserActivityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).GetUserActivity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/GetUserActivity",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).GetUserActivity(ctx, req.(*GetUserActivityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_GetRecommendations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRecommendationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).GetRecommendations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/GetRecommendations",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).GetRecommendations(ctx, req.(*GetRecommendationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_GetArtisanRankings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetArtisanRankingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).GetArtisanRankings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/GetArtisanRankings",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).GetArtisanRankings(ctx, req.(*GetArtisanRankingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_ListArtisanOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListArtisanOrdersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).ListArtisanOrders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/ListArtisanOrders",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).ListArtisanOrders(ctx, req.(*ListArtisanOrdersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_ListMyOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMyOrdersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).ListMyOrders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/ListMyOrders",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).ListMyOrders(ctx, req.(*ListMyOrdersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_AddAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddAddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).AddAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/AddAddress",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).AddAddress(ctx, req.(*AddAddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_GetAddresses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAddressesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).GetAddresses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/GetAddresses",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).GetAddresses(ctx, req.(*GetAddressesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_UpdateAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateAddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).UpdateAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/UpdateAddress",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).UpdateAddress(ctx, req.(*UpdateAddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_DeleteAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).DeleteAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/DeleteAddress",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).DeleteAddress(ctx, req.(*DeleteAddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_RequestReturn_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestReturnRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).RequestReturn(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/RequestReturn",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).RequestReturn(ctx, req.(*RequestReturnRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_ApproveReturn_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveReturnRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).ApproveReturn(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/ApproveReturn",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).ApproveReturn(ctx, req.(*ResolveReturnRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_RejectReturn_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveReturnRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).RejectReturn(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/RejectReturn",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).RejectReturn(ctx, req.(*ResolveReturnRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_GetReturns_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReturnsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).GetReturns(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/GetReturns",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).GetReturns(ctx, req.(*GetReturnsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_RefundPayment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefundPaymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).RefundPayment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/RefundPayment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).RefundPayment(ctx, req.(*RefundPaymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_AddPaymentMethod_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddPaymentMethodRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).AddPaymentMethod(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/AddPaymentMethod",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).AddPaymentMethod(ctx, req.(*AddPaymentMethodRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_GetPaymentMethods_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPaymentMethodsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).GetPaymentMethods(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/GetPaymentMethods",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).GetPaymentMethods(ctx, req.(*GetPaymentMethodsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_DeletePaymentMethod_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeletePaymentMethodRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).DeletePaymentMethod(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/DeletePaymentMethod",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).DeletePaymentMethod(ctx, req.(*DeletePaymentMethodRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_AddTaxRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddTaxRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).AddTaxRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/AddTaxRule",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).AddTaxRule(ctx, req.(*AddTaxRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_GetTaxRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTaxRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).GetTaxRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/GetTaxRules",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).GetTaxRules(ctx, req.(*GetTaxRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_DeleteTaxRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTaxRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).DeleteTaxRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/DeleteTaxRule",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).DeleteTaxRule(ctx, req.(*DeleteTaxRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_QuoteShipping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QuoteShippingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).QuoteShipping(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/QuoteShipping",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).QuoteShipping(ctx, req.(*QuoteShippingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedProductServiceServer) GetRecommendations(context.Context, *GetRecommendationsRequest) (*GetRecommendationsResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetRecommendations not implemented")
+
+func _ProductService_AddCartItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddCartItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).AddCartItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/AddCartItem",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).AddCartItem(ctx, req.(*AddCartItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedProductServiceServer) GetArtisanRankings(context.Context, *GetArtisanRankingsRequest) (*GetArtisanRankingsResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetArtisanRankings not implemented")
+
+func _ProductService_UpdateCartItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateCartItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).UpdateCartItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/UpdateCartItem",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).UpdateCartItem(ctx, req.(*UpdateCartItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedProductServiceServer) mustEmbedUnimplementedProductServiceServer() {}
 
-// UnsafeProductServiceServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to ProductServiceServer will
-// result in compilation errors.
-type UnsafeProductServiceServer interface {
-	mustEmbedUnimplementedProductServiceServer()
+func _ProductService_RemoveCartItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveCartItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).RemoveCartItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/RemoveCartItem",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).RemoveCartItem(ctx, req.(*RemoveCartItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterProductServiceServer(s grpc.ServiceRegistrar, srv ProductServiceServer) {
-	s.RegisterService(&ProductService_ServiceDesc, srv)
+func _ProductService_GetCart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCartRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).GetCart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product_service.ProductService/GetCart",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).GetCart(ctx, req.(*GetCartRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _ProductService_AddProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(AddProductRequest)
+func _ProductService_CheckoutCart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckoutCartRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ProductServiceServer).AddProduct(ctx, in)
+		return srv.(ProductServiceServer).CheckoutCart(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/product_service.ProductService/AddProduct",
+		FullMethod: "/product_service.ProductService/CheckoutCart",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProductServiceServer).AddProduct(ctx, req.(*AddProductRequest))
+		return srv.(ProductServiceServer).CheckoutCart(ctx, req.(*CheckoutCartRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ProductService_EditProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(EditProductRequest)
+func _ProductService_AddPromotion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddPromotionRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ProductServiceServer).EditProduct(ctx, in)
+		return srv.(ProductServiceServer).AddPromotion(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/product_service.ProductService/EditProduct",
+		FullMethod: "/product_service.ProductService/AddPromotion",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProductServiceServer).EditProduct(ctx, req.(*EditProductRequest))
+		return srv.(ProductServiceServer).AddPromotion(ctx, req.(*AddPromotionRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ProductService_GetProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetProductRequest)
+func _ProductService_GetPromotions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPromotionsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ProductServiceServer).GetProduct(ctx, in)
+		return srv.(ProductServiceServer).GetPromotions(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/product_service.ProductService/GetProduct",
+		FullMethod: "/product_service.ProductService/GetPromotions",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProductServiceServer).GetProduct(ctx, req.(*GetProductRequest))
+		return srv.(ProductServiceServer).GetPromotions(ctx, req.(*GetPromotionsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ProductService_GetProducts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetProductsRequest)
+func _ProductService_DeletePromotion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeletePromotionRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ProductServiceServer).GetProducts(ctx, in)
+		return srv.(ProductServiceServer).DeletePromotion(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/product_service.ProductService/GetProducts",
+		FullMethod: "/product_service.ProductService/DeletePromotion",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProductServiceServer).GetProducts(ctx, req.(*GetProductsRequest))
+		return srv.(ProductServiceServer).DeletePromotion(ctx, req.(*DeletePromotionRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ProductService_DeleteProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeleteProductRequest)
+func _ProductService_CreateWishlist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateWishlistRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ProductServiceServer).DeleteProduct(ctx, in)
+		return srv.(ProductServiceServer).CreateWishlist(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/product_service.ProductService/DeleteProduct",
+		FullMethod: "/product_service.ProductService/CreateWishlist",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProductServiceServer).DeleteProduct(ctx, req.(*DeleteProductRequest))
+		return srv.(ProductServiceServer).CreateWishlist(ctx, req.(*CreateWishlistRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ProductService_SearchProducts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SearchProductsRequest)
+func _ProductService_GetWishlists_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWishlistsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ProductServiceServer).SearchProducts(ctx, in)
+		return srv.(ProductServiceServer).GetWishlists(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/product_service.ProductService/SearchProducts",
+		FullMethod: "/product_service.ProductService/GetWishlists",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProductServiceServer).SearchProducts(ctx, req.(*SearchProductsRequest))
+		return srv.(ProductServiceServer).GetWishlists(ctx, req.(*GetWishlistsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ProductService_AddRating_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(AddRatingRequest)
+func _ProductService_GetWishlist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWishlistRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ProductServiceServer).AddRating(ctx, in)
+		return srv.(ProductServiceServer).GetWishlist(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/product_service.ProductService/AddRating",
+		FullMethod: "/product_service.ProductService/GetWishlist",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProductServiceServer).AddRating(ctx, req.(*AddRatingRequest))
+		return srv.(ProductServiceServer).GetWishlist(ctx, req.(*GetWishlistRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ProductService_GetRatings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetRatingsRequest)
+func _ProductService_DeleteWishlist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteWishlistRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ProductServiceServer).GetRatings(ctx, in)
+		return srv.(ProductServiceServer).DeleteWishlist(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/product_service.ProductService/GetRatings",
+		FullMethod: "/product_service.ProductService/DeleteWishlist",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProductServiceServer).GetRatings(ctx, req.(*GetRatingsRequest))
+		return srv.(ProductServiceServer).DeleteWishlist(ctx, req.(*DeleteWishlistRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ProductService_PlaceOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PlaceOrderRequest)
+func _ProductService_AddWishlistItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddWishlistItemRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ProductServiceServer).PlaceOrder(ctx, in)
+		return srv.(ProductServiceServer).AddWishlistItem(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/product_service.ProductService/PlaceOrder",
+		FullMethod: "/product_service.ProductService/AddWishlistItem",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProductServiceServer).PlaceOrder(ctx, req.(*PlaceOrderRequest))
+		return srv.(ProductServiceServer).AddWishlistItem(ctx, req.(*AddWishlistItemRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ProductService_CancelOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CancelOrderRequest)
+func _ProductService_RemoveWishlistItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveWishlistItemRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ProductServiceServer).CancelOrder(ctx, in)
+		return srv.(ProductServiceServer).RemoveWishlistItem(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/product_service.ProductService/CancelOrder",
+		FullMethod: "/product_service.ProductService/RemoveWishlistItem",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProductServiceServer).CancelOrder(ctx, req.(*CancelOrderRequest))
+		return srv.(ProductServiceServer).RemoveWishlistItem(ctx, req.(*RemoveWishlistItemRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ProductService_UpdateOrderStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UpdateOrderStatusRequest)
+func _ProductService_AddTrackingEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddTrackingEventRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ProductServiceServer).UpdateOrderStatus(ctx, in)
+		return srv.(ProductServiceServer).AddTrackingEvent(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/product_service.ProductService/UpdateOrderStatus",
+		FullMethod: "/product_service.ProductService/AddTrackingEvent",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProductServiceServer).UpdateOrderStatus(ctx, req.(*UpdateOrderStatusRequest))
+		return srv.(ProductServiceServer).AddTrackingEvent(ctx, req.(*AddTrackingEventRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ProductService_GetOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetOrdersRequest)
+func _ProductService_GetShipments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetShipmentsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ProductServiceServer).GetOrders(ctx, in)
+		return srv.(ProductServiceServer).GetShipments(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/product_service.ProductService/GetOrders",
+		FullMethod: "/product_service.ProductService/GetShipments",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProductServiceServer).GetOrders(ctx, req.(*GetOrdersRequest))
+		return srv.(ProductServiceServer).GetShipments(ctx, req.(*GetShipmentsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ProductService_GetOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetOrderRequest)
+func _ProductService_CreateShippingLabel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateShippingLabelRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ProductServiceServer).GetOrder(ctx, in)
+		return srv.(ProductServiceServer).CreateShippingLabel(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/product_service.ProductService/GetOrder",
+		FullMethod: "/product_service.ProductService/CreateShippingLabel",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProductServiceServer).GetOrder(ctx, req.(*GetOrderRequest))
+		return srv.(ProductServiceServer).CreateShippingLabel(ctx, req.(*CreateShippingLabelRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ProductService_PayOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PayOrderRequest)
+func _ProductService_GetShippingLabel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetShippingLabelRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ProductServiceServer).PayOrder(ctx, in)
+		return srv.(ProductServiceServer).GetShippingLabel(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/product_service.ProductService/PayOrder",
+		FullMethod: "/product_service.ProductService/GetShippingLabel",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProductServiceServer).PayOrder(ctx, req.(*PayOrderRequest))
+		return srv.(ProductServiceServer).GetShippingLabel(ctx, req.(*GetShippingLabelRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ProductService_CheckPaymentStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CheckPaymentStatusRequest)
+func _ProductService_RefreshTracking_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefreshTrackingRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ProductServiceServer).CheckPaymentStatus(ctx, in)
+		return srv.(ProductServiceServer).RefreshTracking(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/product_service.ProductService/CheckPaymentStatus",
+		FullMethod: "/product_service.ProductService/RefreshTracking",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProductServiceServer).CheckPaymentStatus(ctx, req.(*CheckPaymentStatusRequest))
+		return srv.(ProductServiceServer).RefreshTracking(ctx, req.(*RefreshTrackingRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ProductService_UpdateShippingInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UpdateShippingInfoRequest)
+func _ProductService_ReportRating_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportRatingRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ProductServiceServer).UpdateShippingInfo(ctx, in)
+		return srv.(ProductServiceServer).ReportRating(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/product_service.ProductService/UpdateShippingInfo",
+		FullMethod: "/product_service.ProductService/ReportRating",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProductServiceServer).UpdateShippingInfo(ctx, req.(*UpdateShippingInfoRequest))
+		return srv.(ProductServiceServer).ReportRating(ctx, req.(*ReportRatingRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ProductService_AddArtisanCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(AddArtisanCategoryRequest)
+func _ProductService_GetModerationQueue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetModerationQueueRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ProductServiceServer).AddArtisanCategory(ctx, in)
+		return srv.(ProductServiceServer).GetModerationQueue(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/product_service.ProductService/AddArtisanCategory",
+		FullMethod: "/product_service.ProductService/GetModerationQueue",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProductServiceServer).AddArtisanCategory(ctx, req.(*AddArtisanCategoryRequest))
+		return srv.(ProductServiceServer).GetModerationQueue(ctx, req.(*GetModerationQueueRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ProductService_AddProductCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(AddProductCategoryRequest)
+func _ProductService_ModerateRating_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModerateRatingRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ProductServiceServer).AddProductCategory(ctx, in)
+		return srv.(ProductServiceServer).ModerateRating(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/product_service.ProductService/AddProductCategory",
+		FullMethod: "/product_service.ProductService/ModerateRating",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProductServiceServer).AddProductCategory(ctx, req.(*AddProductCategoryRequest))
+		return srv.(ProductServiceServer).ModerateRating(ctx, req.(*ModerateRatingRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ProductService_GetStatistics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetStatisticsRequest)
+func _ProductService_ReplyToRating_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplyToRatingRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ProductServiceServer).GetStatistics(ctx, in)
+		return srv.(ProductServiceServer).ReplyToRating(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/product_service.ProductService/GetStatistics",
+		FullMethod: "/product_service.ProductService/ReplyToRating",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProductServiceServer).GetStatistics(ctx, req.(*GetStatisticsRequest))
+		return srv.(ProductServiceServer).ReplyToRating(ctx, req.(*ReplyToRatingRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ProductService_GetUserActivity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetUserActivityRequest)
+func _ProductService_VoteRating_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VoteRatingRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ProductServiceServer).GetUserActivity(ctx, in)
+		return srv.(ProductServiceServer).VoteRating(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/product_service.ProductService/GetUserActivity",
+		FullMethod: "/product_service.ProductService/VoteRating",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProductServiceServer).GetUserActivity(ctx, req.(*GetUserActivityRequest))
+		return srv.(ProductServiceServer).VoteRating(ctx, req.(*VoteRatingRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ProductService_GetRecommendations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetRecommendationsRequest)
+func _ProductService_AddRatingPhoto_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddRatingPhotoRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ProductServiceServer).GetRecommendations(ctx, in)
+		return srv.(ProductServiceServer).AddRatingPhoto(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/product_service.ProductService/GetRecommendations",
+		FullMethod: "/product_service.ProductService/AddRatingPhoto",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProductServiceServer).GetRecommendations(ctx, req.(*GetRecommendationsRequest))
+		return srv.(ProductServiceServer).AddRatingPhoto(ctx, req.(*AddRatingPhotoRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ProductService_GetArtisanRankings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetArtisanRankingsRequest)
+func _ProductService_DeleteRatingPhoto_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRatingPhotoRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ProductServiceServer).GetArtisanRankings(ctx, in)
+		return srv.(ProductServiceServer).DeleteRatingPhoto(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/product_service.ProductService/GetArtisanRankings",
+		FullMethod: "/product_service.ProductService/DeleteRatingPhoto",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProductServiceServer).GetArtisanRankings(ctx, req.(*GetArtisanRankingsRequest))
+		return srv.(ProductServiceServer).DeleteRatingPhoto(ctx, req.(*DeleteRatingPhotoRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -855,6 +2263,182 @@ var ProductService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetArtisanRankings",
 			Handler:    _ProductService_GetArtisanRankings_Handler,
 		},
+		{
+			MethodName: "ListArtisanOrders",
+			Handler:    _ProductService_ListArtisanOrders_Handler,
+		},
+		{
+			MethodName: "ListMyOrders",
+			Handler:    _ProductService_ListMyOrders_Handler,
+		},
+		{
+			MethodName: "AddAddress",
+			Handler:    _ProductService_AddAddress_Handler,
+		},
+		{
+			MethodName: "GetAddresses",
+			Handler:    _ProductService_GetAddresses_Handler,
+		},
+		{
+			MethodName: "UpdateAddress",
+			Handler:    _ProductService_UpdateAddress_Handler,
+		},
+		{
+			MethodName: "DeleteAddress",
+			Handler:    _ProductService_DeleteAddress_Handler,
+		},
+		{
+			MethodName: "RequestReturn",
+			Handler:    _ProductService_RequestReturn_Handler,
+		},
+		{
+			MethodName: "ApproveReturn",
+			Handler:    _ProductService_ApproveReturn_Handler,
+		},
+		{
+			MethodName: "RejectReturn",
+			Handler:    _ProductService_RejectReturn_Handler,
+		},
+		{
+			MethodName: "GetReturns",
+			Handler:    _ProductService_GetReturns_Handler,
+		},
+		{
+			MethodName: "RefundPayment",
+			Handler:    _ProductService_RefundPayment_Handler,
+		},
+		{
+			MethodName: "AddPaymentMethod",
+			Handler:    _ProductService_AddPaymentMethod_Handler,
+		},
+		{
+			MethodName: "GetPaymentMethods",
+			Handler:    _ProductService_GetPaymentMethods_Handler,
+		},
+		{
+			MethodName: "DeletePaymentMethod",
+			Handler:    _ProductService_DeletePaymentMethod_Handler,
+		},
+		{
+			MethodName: "AddTaxRule",
+			Handler:    _ProductService_AddTaxRule_Handler,
+		},
+		{
+			MethodName: "GetTaxRules",
+			Handler:    _ProductService_GetTaxRules_Handler,
+		},
+		{
+			MethodName: "DeleteTaxRule",
+			Handler:    _ProductService_DeleteTaxRule_Handler,
+		},
+		{
+			MethodName: "QuoteShipping",
+			Handler:    _ProductService_QuoteShipping_Handler,
+		},
+		{
+			MethodName: "AddCartItem",
+			Handler:    _ProductService_AddCartItem_Handler,
+		},
+		{
+			MethodName: "UpdateCartItem",
+			Handler:    _ProductService_UpdateCartItem_Handler,
+		},
+		{
+			MethodName: "RemoveCartItem",
+			Handler:    _ProductService_RemoveCartItem_Handler,
+		},
+		{
+			MethodName: "GetCart",
+			Handler:    _ProductService_GetCart_Handler,
+		},
+		{
+			MethodName: "CheckoutCart",
+			Handler:    _ProductService_CheckoutCart_Handler,
+		},
+		{
+			MethodName: "AddPromotion",
+			Handler:    _ProductService_AddPromotion_Handler,
+		},
+		{
+			MethodName: "GetPromotions",
+			Handler:    _ProductService_GetPromotions_Handler,
+		},
+		{
+			MethodName: "DeletePromotion",
+			Handler:    _ProductService_DeletePromotion_Handler,
+		},
+		{
+			MethodName: "CreateWishlist",
+			Handler:    _ProductService_CreateWishlist_Handler,
+		},
+		{
+			MethodName: "GetWishlists",
+			Handler:    _ProductService_GetWishlists_Handler,
+		},
+		{
+			MethodName: "GetWishlist",
+			Handler:    _ProductService_GetWishlist_Handler,
+		},
+		{
+			MethodName: "DeleteWishlist",
+			Handler:    _ProductService_DeleteWishlist_Handler,
+		},
+		{
+			MethodName: "AddWishlistItem",
+			Handler:    _ProductService_AddWishlistItem_Handler,
+		},
+		{
+			MethodName: "RemoveWishlistItem",
+			Handler:    _ProductService_RemoveWishlistItem_Handler,
+		},
+		{
+			MethodName: "AddTrackingEvent",
+			Handler:    _ProductService_AddTrackingEvent_Handler,
+		},
+		{
+			MethodName: "GetShipments",
+			Handler:    _ProductService_GetShipments_Handler,
+		},
+		{
+			MethodName: "CreateShippingLabel",
+			Handler:    _ProductService_CreateShippingLabel_Handler,
+		},
+		{
+			MethodName: "GetShippingLabel",
+			Handler:    _ProductService_GetShippingLabel_Handler,
+		},
+		{
+			MethodName: "RefreshTracking",
+			Handler:    _ProductService_RefreshTracking_Handler,
+		},
+		{
+			MethodName: "ReportRating",
+			Handler:    _ProductService_ReportRating_Handler,
+		},
+		{
+			MethodName: "GetModerationQueue",
+			Handler:    _ProductService_GetModerationQueue_Handler,
+		},
+		{
+			MethodName: "ModerateRating",
+			Handler:    _ProductService_ModerateRating_Handler,
+		},
+		{
+			MethodName: "ReplyToRating",
+			Handler:    _ProductService_ReplyToRating_Handler,
+		},
+		{
+			MethodName: "VoteRating",
+			Handler:    _ProductService_VoteRating_Handler,
+		},
+		{
+			MethodName: "AddRatingPhoto",
+			Handler:    _ProductService_AddRatingPhoto_Handler,
+		},
+		{
+			MethodName: "DeleteRatingPhoto",
+			Handler:    _ProductService_DeleteRatingPhoto_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "product_service/product.proto",
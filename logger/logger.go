@@ -13,6 +13,7 @@ var log *logrus.Logger
 func InitLogger() {
 	log = logrus.New()
 	log.SetFormatter(&logrus.JSONFormatter{})
+	log.AddHook(&RedactHook{})
 
 	logFile := &lumberjack.Logger{
 		Filename:   "./logs/auth.log",
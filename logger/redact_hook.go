@@ -0,0 +1,40 @@
+package logger
+
+import (
+	"fmt"
+	"product-service/redact"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RedactHook scrubs card data from log entries before they are formatted, so
+// a card number passed through an error or a field never reaches the output.
+type RedactHook struct{}
+
+func (h *RedactHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *RedactHook) Fire(entry *logrus.Entry) error {
+	entry.Message = redact.String(entry.Message)
+
+	data := make(logrus.Fields, len(entry.Data))
+	for key, value := range entry.Data {
+		switch v := value.(type) {
+		case string:
+			data[key] = redact.Field(key, v)
+		case error:
+			data[key] = redact.Field(key, v.Error())
+		case fmt.Stringer:
+			data[key] = redact.Field(key, v.String())
+		default:
+			if redact.IsSensitiveKey(key) {
+				data[key] = redact.Field(key, fmt.Sprint(v))
+			} else {
+				data[key] = value
+			}
+		}
+	}
+	entry.Data = data
+	return nil
+}
@@ -0,0 +1,27 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactHook(t *testing.T) {
+	var out bytes.Buffer
+	log := logrus.New()
+	log.SetOutput(&out)
+	log.SetFormatter(&logrus.JSONFormatter{})
+	log.AddHook(&RedactHook{})
+
+	log.WithFields(logrus.Fields{
+		"cvv":   123,
+		"error": fmt.Errorf("charge of 4242424242424242 failed"),
+	}).Errorf("failed to pay order: card_number:%q", "4242424242424242")
+
+	assert.NotContains(t, out.String(), "4242424242424242")
+	assert.Contains(t, out.String(), "************4242")
+	assert.Contains(t, out.String(), `"cvv":"[REDACTED]"`)
+}
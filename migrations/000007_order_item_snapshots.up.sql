@@ -0,0 +1,3 @@
+ALTER TABLE order_items
+ADD COLUMN product_name VARCHAR(100),
+ADD COLUMN product_description TEXT;
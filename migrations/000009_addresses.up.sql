@@ -0,0 +1,17 @@
+CREATE TABLE addresses (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    label VARCHAR(50),
+    recipient_name VARCHAR(100),
+    phone VARCHAR(30),
+    street VARCHAR(255) NOT NULL,
+    city VARCHAR(100) NOT NULL,
+    region VARCHAR(100),
+    country CHAR(2) NOT NULL,
+    zip_code VARCHAR(20),
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE DEFAULT NULL
+);
+CREATE INDEX addresses_user_id_idx ON addresses (user_id) WHERE deleted_at IS NULL;
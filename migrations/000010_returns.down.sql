@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS refunds;
+DROP TABLE IF EXISTS return_requests;
+DROP TYPE IF EXISTS return_status;
+ALTER TABLE orders DROP COLUMN IF EXISTS delivered_at;
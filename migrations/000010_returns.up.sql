@@ -0,0 +1,37 @@
+ALTER TABLE orders
+ADD COLUMN delivered_at TIMESTAMP WITH TIME ZONE;
+
+CREATE TYPE return_status AS ENUM (
+    'requested',
+    'approved',
+    'rejected'
+);
+
+CREATE TABLE return_requests (
+    id UUID PRIMARY KEY,
+    order_id UUID REFERENCES orders(id),
+    order_item_id UUID REFERENCES order_items(id),
+    user_id UUID NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    reason TEXT NOT NULL,
+    status return_status NOT NULL DEFAULT 'requested',
+    note TEXT,
+    resolved_by UUID,
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE DEFAULT NULL
+);
+
+CREATE TABLE refunds (
+    id UUID PRIMARY KEY,
+    payment_id UUID REFERENCES payments(id),
+    order_id UUID REFERENCES orders(id),
+    return_id UUID REFERENCES return_requests(id),
+    amount DECIMAL(10, 2) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE DEFAULT NULL
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS payment_provider_responses;
+ALTER TABLE payments DROP COLUMN IF EXISTS provider;
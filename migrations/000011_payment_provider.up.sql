@@ -0,0 +1,15 @@
+ALTER TABLE payments
+ADD COLUMN provider VARCHAR(50);
+
+CREATE TABLE payment_provider_responses (
+    id UUID PRIMARY KEY,
+    payment_id UUID REFERENCES payments(id),
+    provider VARCHAR(50) NOT NULL,
+    operation VARCHAR(20) NOT NULL,
+    approved BOOLEAN NOT NULL,
+    transaction_id VARCHAR(100),
+    amount DECIMAL(10, 2) NOT NULL,
+    decline_code VARCHAR(50),
+    response JSONB,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
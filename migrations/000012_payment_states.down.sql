@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS payment_status_history;
+ALTER TABLE payments DROP CONSTRAINT IF EXISTS payments_status_check;
+UPDATE payments SET status = 'paid' WHERE status = 'captured';
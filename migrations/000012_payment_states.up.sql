@@ -0,0 +1,21 @@
+UPDATE payments SET status = 'captured' WHERE status = 'paid';
+
+ALTER TABLE payments
+ADD CONSTRAINT payments_status_check CHECK (status IN (
+    'pending',
+    'authorized',
+    'captured',
+    'failed',
+    'voided',
+    'refunded',
+    'partially_refunded'
+));
+
+CREATE TABLE payment_status_history (
+    id UUID PRIMARY KEY,
+    payment_id UUID REFERENCES payments(id),
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS payments_one_active_per_order;
@@ -0,0 +1,15 @@
+UPDATE payments SET deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE deleted_at IS NULL
+AND status IN ('pending', 'authorized', 'captured', 'partially_refunded', 'refunded')
+AND id NOT IN (
+    SELECT DISTINCT ON (order_id) id
+    FROM payments
+    WHERE deleted_at IS NULL
+    AND status IN ('pending', 'authorized', 'captured', 'partially_refunded', 'refunded')
+    ORDER BY order_id,
+        CASE status WHEN 'pending' THEN 2 WHEN 'authorized' THEN 1 ELSE 0 END,
+        created_at DESC
+);
+
+CREATE UNIQUE INDEX payments_one_active_per_order ON payments (order_id)
+WHERE status IN ('pending', 'authorized', 'captured', 'partially_refunded', 'refunded') AND deleted_at IS NULL;
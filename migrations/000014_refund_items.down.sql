@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS refund_items;
+ALTER TABLE refunds DROP CONSTRAINT IF EXISTS refunds_status_check;
+ALTER TABLE refunds DROP COLUMN IF EXISTS transaction_id;
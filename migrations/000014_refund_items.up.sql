@@ -0,0 +1,19 @@
+ALTER TABLE refunds
+ADD COLUMN transaction_id VARCHAR(100);
+
+ALTER TABLE refunds
+ADD CONSTRAINT refunds_status_check CHECK (status IN (
+    'pending',
+    'processing',
+    'completed',
+    'failed'
+));
+
+CREATE TABLE refund_items (
+    id UUID PRIMARY KEY,
+    refund_id UUID REFERENCES refunds(id),
+    order_item_id UUID REFERENCES order_items(id),
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    amount DECIMAL(10, 2) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,16 @@
+CREATE TABLE payment_methods (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    provider VARCHAR(50) NOT NULL,
+    token VARCHAR(255) NOT NULL,
+    brand VARCHAR(30),
+    last4 CHAR(4),
+    exp_month INTEGER,
+    exp_year INTEGER,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE DEFAULT NULL
+);
+CREATE INDEX payment_methods_user_id_idx ON payment_methods (user_id) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX payment_methods_user_token_idx ON payment_methods (user_id, provider, token) WHERE deleted_at IS NULL;
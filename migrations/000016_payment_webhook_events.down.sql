@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS payments_transaction_id_idx;
+DROP TABLE IF EXISTS payment_webhook_events;
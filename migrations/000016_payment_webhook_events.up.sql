@@ -0,0 +1,13 @@
+CREATE TABLE payment_webhook_events (
+    provider VARCHAR(50) NOT NULL,
+    event_id VARCHAR(100) NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    transaction_id VARCHAR(100),
+    payload JSONB NOT NULL,
+    outcome VARCHAR(20),
+    note TEXT,
+    received_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    processed_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (provider, event_id)
+);
+CREATE INDEX payments_transaction_id_idx ON payments (provider, transaction_id) WHERE deleted_at IS NULL;
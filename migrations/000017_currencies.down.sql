@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS order_exchange_rates;
+ALTER TABLE payments DROP COLUMN IF EXISTS currency;
+ALTER TABLE order_items
+DROP COLUMN IF EXISTS original_price,
+DROP COLUMN IF EXISTS original_currency;
+ALTER TABLE orders DROP COLUMN IF EXISTS currency;
+ALTER TABLE products DROP COLUMN IF EXISTS currency;
@@ -0,0 +1,23 @@
+ALTER TABLE products
+ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'USD';
+
+ALTER TABLE orders
+ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'USD';
+
+ALTER TABLE order_items
+ADD COLUMN original_price DECIMAL(10, 2),
+ADD COLUMN original_currency CHAR(3);
+
+ALTER TABLE payments
+ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'USD';
+
+CREATE TABLE order_exchange_rates (
+    order_id UUID NOT NULL REFERENCES orders(id),
+    base_currency CHAR(3) NOT NULL,
+    quote_currency CHAR(3) NOT NULL,
+    rate DECIMAL(20, 10) NOT NULL CHECK (rate > 0),
+    source VARCHAR(50) NOT NULL,
+    as_of TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (order_id, base_currency)
+);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS order_tax_lines;
+ALTER TABLE orders
+DROP COLUMN IF EXISTS subtotal_amount,
+DROP COLUMN IF EXISTS tax_amount;
+DROP TABLE IF EXISTS tax_rules;
@@ -0,0 +1,39 @@
+CREATE TABLE tax_rules (
+    id UUID PRIMARY KEY,
+    country CHAR(2) NOT NULL,
+    region VARCHAR(100) NOT NULL DEFAULT '',
+    category_id UUID REFERENCES product_categories(id),
+    name VARCHAR(100) NOT NULL,
+    rate DECIMAL(7, 6) NOT NULL CHECK (rate >= 0 AND rate < 1),
+    inclusive BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE DEFAULT NULL
+);
+CREATE UNIQUE INDEX tax_rules_scope_idx ON tax_rules (
+    country, lower(region), COALESCE(category_id, '00000000-0000-0000-0000-000000000000')
+) WHERE deleted_at IS NULL;
+
+ALTER TABLE orders
+ADD COLUMN subtotal_amount DECIMAL(10, 2),
+ADD COLUMN tax_amount DECIMAL(10, 2) NOT NULL DEFAULT 0;
+
+UPDATE orders SET subtotal_amount = total_amount;
+
+ALTER TABLE orders
+ALTER COLUMN subtotal_amount SET NOT NULL,
+ALTER COLUMN subtotal_amount SET DEFAULT 0;
+
+CREATE TABLE order_tax_lines (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL REFERENCES orders(id),
+    order_item_id UUID NOT NULL REFERENCES order_items(id),
+    rule_id UUID REFERENCES tax_rules(id),
+    name VARCHAR(100) NOT NULL,
+    rate DECIMAL(7, 6) NOT NULL,
+    inclusive BOOLEAN NOT NULL,
+    taxable_amount DECIMAL(10, 2) NOT NULL,
+    amount DECIMAL(10, 2) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX order_tax_lines_order_id_idx ON order_tax_lines (order_id);
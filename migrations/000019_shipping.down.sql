@@ -0,0 +1,4 @@
+ALTER TABLE orders
+DROP COLUMN IF EXISTS shipping_method,
+DROP COLUMN IF EXISTS shipping_amount;
+ALTER TABLE products DROP COLUMN IF EXISTS weight_grams;
@@ -0,0 +1,6 @@
+ALTER TABLE products
+ADD COLUMN weight_grams INTEGER NOT NULL DEFAULT 0 CHECK (weight_grams >= 0);
+
+ALTER TABLE orders
+ADD COLUMN shipping_method VARCHAR(20),
+ADD COLUMN shipping_amount DECIMAL(10, 2) NOT NULL DEFAULT 0;
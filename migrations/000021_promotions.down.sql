@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS order_discount_lines;
+ALTER TABLE orders
+DROP COLUMN IF EXISTS discount_amount,
+DROP COLUMN IF EXISTS coupon_code;
+DROP TABLE IF EXISTS promotions;
@@ -0,0 +1,38 @@
+CREATE TABLE promotions (
+    id UUID PRIMARY KEY,
+    code VARCHAR(64),
+    name VARCHAR(100) NOT NULL,
+    kind VARCHAR(16) NOT NULL CHECK (kind IN ('percentage', 'fixed')),
+    percent_off DECIMAL(5, 2) CHECK (percent_off > 0 AND percent_off <= 100),
+    amount_off DECIMAL(10, 2) NOT NULL DEFAULT 0 CHECK (amount_off >= 0),
+    currency CHAR(3) NOT NULL DEFAULT 'USD',
+    min_order_amount DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    max_uses INTEGER NOT NULL DEFAULT 0,
+    max_uses_per_user INTEGER NOT NULL DEFAULT 0,
+    used_count INTEGER NOT NULL DEFAULT 0,
+    artisan_id UUID,
+    category_id UUID REFERENCES product_categories(id),
+    starts_at TIMESTAMP WITH TIME ZONE,
+    ends_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE DEFAULT NULL
+);
+CREATE UNIQUE INDEX promotions_code_idx ON promotions (upper(code)) WHERE code IS NOT NULL AND deleted_at IS NULL;
+
+ALTER TABLE orders
+ADD COLUMN discount_amount DECIMAL(10, 2) NOT NULL DEFAULT 0,
+ADD COLUMN coupon_code VARCHAR(64);
+
+CREATE TABLE order_discount_lines (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL REFERENCES orders(id),
+    order_item_id UUID NOT NULL REFERENCES order_items(id),
+    promotion_id UUID NOT NULL REFERENCES promotions(id),
+    code VARCHAR(64) NOT NULL DEFAULT '',
+    name VARCHAR(100) NOT NULL,
+    amount DECIMAL(10, 2) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX order_discount_lines_order_id_idx ON order_discount_lines (order_id);
+CREATE INDEX order_discount_lines_promotion_id_idx ON order_discount_lines (promotion_id);
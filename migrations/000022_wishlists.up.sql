@@ -0,0 +1,21 @@
+CREATE TABLE wishlists (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE DEFAULT NULL
+);
+CREATE UNIQUE INDEX wishlists_user_name_idx ON wishlists (user_id, lower(name)) WHERE deleted_at IS NULL;
+
+CREATE TABLE wishlist_items (
+    wishlist_id UUID NOT NULL REFERENCES wishlists(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES products(id),
+    added_price DECIMAL(10, 2) NOT NULL,
+    last_price DECIMAL(10, 2) NOT NULL,
+    last_currency CHAR(3) NOT NULL,
+    last_in_stock BOOLEAN NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (wishlist_id, product_id)
+);
+CREATE INDEX wishlist_items_product_id_idx ON wishlist_items (product_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS tracking_events;
+DROP TABLE IF EXISTS shipments;
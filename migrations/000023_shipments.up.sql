@@ -0,0 +1,34 @@
+CREATE TABLE shipments (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL REFERENCES orders(id),
+    carrier VARCHAR(255) NOT NULL,
+    tracking_number VARCHAR(255) NOT NULL,
+    status VARCHAR(32) NOT NULL DEFAULT 'label_created'
+        CHECK (status IN ('label_created', 'in_transit', 'out_for_delivery', 'delivered', 'exception')),
+    estimated_delivery_date DATE,
+    delivered_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO shipments (id, order_id, carrier, tracking_number, status, estimated_delivery_date, delivered_at)
+SELECT id, id, COALESCE(carrier, ''), tracking_number,
+    CASE WHEN status = 'delivered' THEN 'delivered' ELSE 'in_transit' END,
+    estimated_delivery_date::date, delivered_at
+FROM orders
+WHERE tracking_number IS NOT NULL AND tracking_number <> '';
+
+CREATE UNIQUE INDEX shipments_tracking_idx ON shipments (order_id, lower(carrier), tracking_number);
+CREATE INDEX shipments_tracking_number_idx ON shipments (lower(carrier), tracking_number);
+
+CREATE TABLE tracking_events (
+    id UUID PRIMARY KEY,
+    shipment_id UUID NOT NULL REFERENCES shipments(id),
+    status VARCHAR(32) NOT NULL
+        CHECK (status IN ('label_created', 'in_transit', 'out_for_delivery', 'delivered', 'exception')),
+    location VARCHAR(255) NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX tracking_events_dedup_idx ON tracking_events (shipment_id, status, occurred_at);
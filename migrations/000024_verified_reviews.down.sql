@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS ratings_product_user_idx;
+ALTER TABLE ratings
+DROP COLUMN IF EXISTS verified_purchase,
+DROP CONSTRAINT IF EXISTS ratings_rating_check,
+ALTER COLUMN rating TYPE DECIMAL(2, 1);
@@ -0,0 +1,22 @@
+UPDATE ratings SET deleted_at = CURRENT_TIMESTAMP
+WHERE deleted_at IS NULL AND id NOT IN (
+    SELECT DISTINCT ON (product_id, user_id) id
+    FROM ratings
+    WHERE deleted_at IS NULL
+    ORDER BY product_id, user_id, created_at DESC
+);
+
+ALTER TABLE ratings
+ALTER COLUMN rating TYPE SMALLINT USING LEAST(GREATEST(round(rating), 1), 5),
+ADD CONSTRAINT ratings_rating_check CHECK (rating BETWEEN 1 AND 5),
+ADD COLUMN verified_purchase BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE UNIQUE INDEX ratings_product_user_idx ON ratings (product_id, user_id) WHERE deleted_at IS NULL;
+
+UPDATE ratings r SET verified_purchase = TRUE
+WHERE EXISTS (
+    SELECT 1 FROM orders o
+    JOIN order_items oi ON oi.order_id = o.id
+    WHERE o.user_id = r.user_id AND oi.product_id = r.product_id
+    AND o.status = 'delivered' AND o.deleted_at IS NULL AND oi.deleted_at IS NULL
+);
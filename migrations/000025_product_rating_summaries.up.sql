@@ -0,0 +1,23 @@
+CREATE TABLE product_rating_summaries (
+    product_id UUID PRIMARY KEY REFERENCES products(id),
+    rating_count INTEGER NOT NULL DEFAULT 0,
+    rating_average DECIMAL(3, 2) NOT NULL DEFAULT 0,
+    stars_1 INTEGER NOT NULL DEFAULT 0,
+    stars_2 INTEGER NOT NULL DEFAULT 0,
+    stars_3 INTEGER NOT NULL DEFAULT 0,
+    stars_4 INTEGER NOT NULL DEFAULT 0,
+    stars_5 INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX product_rating_summaries_average_idx ON product_rating_summaries (rating_average DESC, rating_count DESC);
+
+INSERT INTO product_rating_summaries (product_id, rating_count, rating_average, stars_1, stars_2, stars_3, stars_4, stars_5)
+SELECT product_id, count(*), round(avg(rating), 2),
+    count(*) FILTER (WHERE rating = 1),
+    count(*) FILTER (WHERE rating = 2),
+    count(*) FILTER (WHERE rating = 3),
+    count(*) FILTER (WHERE rating = 4),
+    count(*) FILTER (WHERE rating = 5)
+FROM ratings
+WHERE deleted_at IS NULL AND product_id IS NOT NULL
+GROUP BY product_id;
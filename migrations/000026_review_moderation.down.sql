@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS rating_reports;
+DROP INDEX IF EXISTS ratings_pending_idx;
+ALTER TABLE ratings
+DROP COLUMN IF EXISTS moderated_at,
+DROP COLUMN IF EXISTS moderated_by,
+DROP COLUMN IF EXISTS moderation_reason,
+DROP COLUMN IF EXISTS status;
@@ -0,0 +1,16 @@
+ALTER TABLE ratings
+ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'visible' CHECK (status IN ('visible', 'pending', 'hidden')),
+ADD COLUMN moderation_reason TEXT NOT NULL DEFAULT '',
+ADD COLUMN moderated_by UUID,
+ADD COLUMN moderated_at TIMESTAMP WITH TIME ZONE;
+CREATE INDEX ratings_pending_idx ON ratings (created_at) WHERE status = 'pending' AND deleted_at IS NULL;
+
+CREATE TABLE rating_reports (
+    id UUID PRIMARY KEY,
+    rating_id UUID NOT NULL REFERENCES ratings(id),
+    user_id UUID NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP WITH TIME ZONE
+);
+CREATE UNIQUE INDEX rating_reports_open_idx ON rating_reports (rating_id, user_id) WHERE resolved_at IS NULL;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS rating_votes;
+DROP TABLE IF EXISTS rating_replies;
+ALTER TABLE ratings
+DROP COLUMN IF EXISTS unhelpful_count,
+DROP COLUMN IF EXISTS helpful_count;
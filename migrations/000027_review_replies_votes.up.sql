@@ -0,0 +1,20 @@
+ALTER TABLE ratings
+ADD COLUMN helpful_count INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN unhelpful_count INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE rating_replies (
+    rating_id UUID PRIMARY KEY REFERENCES ratings(id),
+    artisan_id UUID NOT NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE rating_votes (
+    rating_id UUID NOT NULL REFERENCES ratings(id),
+    user_id UUID NOT NULL,
+    helpful BOOLEAN NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (rating_id, user_id)
+);
@@ -0,0 +1,10 @@
+CREATE TABLE rating_photos (
+    id UUID PRIMARY KEY,
+    rating_id UUID NOT NULL REFERENCES ratings(id),
+    storage_key TEXT NOT NULL UNIQUE,
+    content_type VARCHAR(50) NOT NULL,
+    size_bytes INTEGER NOT NULL CHECK (size_bytes > 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX rating_photos_rating_id_idx ON rating_photos (rating_id);
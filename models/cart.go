@@ -0,0 +1,37 @@
+package models
+
+// Cart item warnings, set when an item cannot be ordered as it is.
+const (
+	CartWarningUnavailable       = "unavailable"
+	CartWarningOutOfStock        = "out_of_stock"
+	CartWarningInsufficientStock = "insufficient_stock"
+)
+
+// CartItem is a product in a user's cart with its live price and stock.
+type CartItem struct {
+	ProductId   string `db:"product_id" json:"product_id"`
+	ProductName string `db:"product_name" json:"product_name"`
+	Quantity    int32  `db:"quantity" json:"quantity"`
+	Price       Money  `db:"price" json:"price"`
+	Currency    string `db:"currency" json:"currency"`
+	// Available is the stock left, and Deleted is set when the product was
+	// removed from sale after it was added.
+	Available int32  `db:"available" json:"available"`
+	Deleted   bool   `db:"deleted" json:"deleted"`
+	CreatedAt string `db:"created_at" json:"created_at"`
+	UpdatedAt string `db:"updated_at" json:"updated_at"`
+}
+
+// Warning reports why the item cannot be ordered in its quantity, if it
+// cannot.
+func (item *CartItem) Warning() string {
+	switch {
+	case item.Deleted:
+		return CartWarningUnavailable
+	case item.Available <= 0:
+		return CartWarningOutOfStock
+	case item.Available < item.Quantity:
+		return CartWarningInsufficientStock
+	}
+	return ""
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// ExchangeRate converts an amount in Base into Quote: quote = base * Rate.
+// Rate is decimal text so it can be stored and replayed exactly.
+type ExchangeRate struct {
+	Base   string    `db:"base_currency" json:"base_currency"`
+	Quote  string    `db:"quote_currency" json:"quote_currency"`
+	Rate   string    `db:"rate" json:"rate"`
+	Source string    `db:"source" json:"source"`
+	AsOf   time.Time `db:"as_of" json:"as_of"`
+}
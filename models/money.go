@@ -0,0 +1,142 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Money is an amount in minor units (cents). Prices and totals are stored as
+// DECIMAL(10, 2) and read into Money exactly, and all arithmetic is done on
+// integers. float64 only appears at the API boundary, where the proto
+// messages carry amounts as doubles.
+type Money int64
+
+// MoneyFromFloat converts an amount received as a float, rounding it to the
+// nearest cent.
+func MoneyFromFloat(f float64) Money {
+	return Money(math.Round(f * 100))
+}
+
+// ParseMoney reads a decimal amount such as "12.34", "-0.5" or "7" exactly.
+// More than two decimal places is an error rather than a silent rounding.
+func ParseMoney(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	negative := strings.HasPrefix(s, "-")
+	digits := strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+
+	whole, frac, _ := strings.Cut(digits, ".")
+	frac = strings.TrimRight(frac, "0")
+	if whole == "" && frac == "" || len(frac) > 2 {
+		return 0, fmt.Errorf("invalid money amount %q", s)
+	}
+	for len(frac) < 2 {
+		frac += "0"
+	}
+	if whole == "" {
+		whole = "0"
+	}
+
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || units > math.MaxInt64/100-1 {
+		return 0, fmt.Errorf("invalid money amount %q", s)
+	}
+	cents, err := strconv.ParseInt(frac, 10, 64)
+	if err != nil || strings.ContainsAny(whole+frac, "+-") {
+		return 0, fmt.Errorf("invalid money amount %q", s)
+	}
+
+	m := Money(units*100 + cents)
+	if negative {
+		m = -m
+	}
+	return m, nil
+}
+
+// Float64 converts the amount for a proto message.
+func (m Money) Float64() float64 {
+	return float64(m) / 100
+}
+
+// Mul returns the amount multiplied by a quantity.
+func (m Money) Mul(quantity int32) Money {
+	return m * Money(quantity)
+}
+
+// Share returns part/whole of the amount, rounded down to the cent.
+func (m Money) Share(part, whole int32) Money {
+	return m * Money(part) / Money(whole)
+}
+
+// Convert returns the amount multiplied by an exchange rate given as decimal
+// text, rounded half away from zero to the nearest cent.
+func (m Money) Convert(rate string) (Money, error) {
+	r, ok := new(big.Rat).SetString(rate)
+	if !ok || r.Sign() <= 0 {
+		return 0, fmt.Errorf("invalid exchange rate %q", rate)
+	}
+	r.Mul(r, new(big.Rat).SetInt64(int64(m)))
+
+	// Rat.FloatString rounds half away from zero.
+	cents, err := strconv.ParseInt(r.FloatString(0), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("converted amount out of range: %v", err)
+	}
+	return Money(cents), nil
+}
+
+// String formats the amount with two decimal places, e.g. "12.34".
+func (m Money) String() string {
+	sign := ""
+	if m < 0 {
+		sign = "-"
+		m = -m
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, m/100, m%100)
+}
+
+// Scan reads a DECIMAL column, which lib/pq returns as text.
+func (m *Money) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*m = 0
+	case []byte:
+		return m.Scan(string(v))
+	case string:
+		parsed, err := ParseMoney(v)
+		if err != nil {
+			return err
+		}
+		*m = parsed
+	case int64:
+		*m = Money(v * 100)
+	case float64:
+		*m = MoneyFromFloat(v)
+	default:
+		return fmt.Errorf("cannot scan %T into Money", src)
+	}
+	return nil
+}
+
+// Value writes the amount as a decimal string so Postgres stores it exactly.
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// MarshalJSON writes the amount as a JSON number with two decimal places.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON reads a JSON number or string without going through float64.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	parsed, err := ParseMoney(strings.Trim(string(data), `"`))
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
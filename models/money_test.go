@@ -0,0 +1,69 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMoney(t *testing.T) {
+	for in, want := range map[string]Money{
+		"12.34": 1234,
+		"7":     700,
+		"-0.5":  -50,
+		".05":   5,
+		"1.230": 123,
+	} {
+		got, err := ParseMoney(in)
+		assert.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+
+	for _, in := range []string{"", "1.234", "abc", "1.-2", "--1"} {
+		_, err := ParseMoney(in)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	assert.Equal(t, "12.34", Money(1234).String())
+	assert.Equal(t, "0.05", Money(5).String())
+	assert.Equal(t, "-1.50", Money(-150).String())
+}
+
+func TestMoneyFromFloat(t *testing.T) {
+	// 0.1 + 0.2 is not 0.3 in float64, but rounds to 30 cents.
+	assert.Equal(t, Money(30), MoneyFromFloat(0.1+0.2))
+	assert.Equal(t, Money(4999), MoneyFromFloat(49.99))
+	assert.Equal(t, 49.99, Money(4999).Float64())
+}
+
+func TestMoneyScan(t *testing.T) {
+	var m Money
+	assert.NoError(t, m.Scan([]byte("99.99")))
+	assert.Equal(t, Money(9999), m)
+	assert.NoError(t, m.Scan(int64(3)))
+	assert.Equal(t, Money(300), m)
+	assert.NoError(t, m.Scan(nil))
+	assert.Equal(t, Money(0), m)
+	assert.Error(t, m.Scan(true))
+}
+
+func TestMoneyJSON(t *testing.T) {
+	var v struct {
+		Amount Money `json:"amount"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(`{"amount": 20.1}`), &v))
+	assert.Equal(t, Money(2010), v.Amount)
+
+	out, err := json.Marshal(v)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"amount": 20.10}`, string(out))
+}
+
+func TestMoneyShare(t *testing.T) {
+	assert.Equal(t, Money(999), Money(2999).Share(1, 3))
+	assert.Equal(t, Money(2999), Money(2999).Share(3, 3))
+	assert.Equal(t, Money(0), Money(2999).Share(0, 3))
+}
@@ -0,0 +1,102 @@
+package models
+
+import "fmt"
+
+// Payment statuses. A payment starts pending and moves through the gateway
+// lifecycle; failed, voided and refunded are final.
+const (
+	PaymentPending           = "pending"
+	PaymentAuthorized        = "authorized"
+	PaymentCaptured          = "captured"
+	PaymentFailed            = "failed"
+	PaymentVoided            = "voided"
+	PaymentRefunded          = "refunded"
+	PaymentPartiallyRefunded = "partially_refunded"
+)
+
+var paymentTransitions = map[string][]string{
+	PaymentPending:           {PaymentAuthorized, PaymentFailed},
+	PaymentAuthorized:        {PaymentCaptured, PaymentVoided, PaymentFailed},
+	PaymentCaptured:          {PaymentPartiallyRefunded, PaymentRefunded},
+	PaymentPartiallyRefunded: {PaymentPartiallyRefunded, PaymentRefunded},
+}
+
+// ValidatePaymentTransition reports whether a payment may move from one
+// status to another.
+func ValidatePaymentTransition(from, to string) error {
+	for _, next := range paymentTransitions[from] {
+		if next == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid payment transition from %s to %s", from, to)
+}
+
+type Payment struct {
+	Id            string `db:"id" json:"id"`
+	OrderId       string `db:"order_id" json:"order_id"`
+	Amount        Money  `db:"amount" json:"amount"`
+	Status        string `db:"status" json:"status"`
+	TransactionId string `db:"transaction_id" json:"transaction_id"`
+	PaymentMethod string `db:"payment_method" json:"payment_method"`
+	Provider      string `db:"provider" json:"provider"`
+}
+
+type ProviderResponse struct {
+	Provider      string `db:"provider" json:"provider"`
+	Operation     string `db:"operation" json:"operation"`
+	Approved      bool   `db:"approved" json:"approved"`
+	TransactionId string `db:"transaction_id" json:"transaction_id"`
+	Amount        Money  `db:"amount" json:"amount"`
+	DeclineCode   string `db:"decline_code" json:"decline_code"`
+	Response      []byte `db:"response" json:"response"`
+}
+
+// PaymentTransition moves a payment to a new status, recording the provider
+// responses that caused it.
+type PaymentTransition struct {
+	PaymentId     string
+	To            string
+	Reason        string
+	TransactionId string
+	Responses     []*ProviderResponse
+	// OrderStatus, when set, moves the pending order to this status in the
+	// same transaction.
+	OrderStatus string
+}
+
+// PaymentMethod is a provider token saved by a user. Only the token and
+// display details are stored, never card data.
+type PaymentMethod struct {
+	UserId    string `db:"user_id" json:"user_id"`
+	Provider  string `db:"provider" json:"provider"`
+	Token     string `db:"token" json:"token"`
+	Brand     string `db:"brand" json:"brand"`
+	Last4     string `db:"last4" json:"last4"`
+	ExpMonth  int32  `db:"exp_month" json:"exp_month"`
+	ExpYear   int32  `db:"exp_year" json:"exp_year"`
+	IsDefault bool   `db:"is_default" json:"is_default"`
+}
+
+// Outcomes of a provider webhook event.
+const (
+	PaymentEventProcessed = "processed"
+	PaymentEventDuplicate = "duplicate"
+	PaymentEventIgnored   = "ignored"
+)
+
+// PaymentEvent is a payment update pushed by the provider. Event ids are
+// unique per provider and recorded, so a replayed event is not applied twice.
+type PaymentEvent struct {
+	Provider      string
+	EventId       string
+	Type          string
+	TransactionId string
+	Payload       []byte
+	// To is the status the payment moves to, and OrderStatus the status a
+	// pending order moves to with it, as in PaymentTransition.
+	To          string
+	OrderStatus string
+	Reason      string
+	Response    *ProviderResponse
+}
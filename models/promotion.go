@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// Promotion kinds.
+const (
+	PromotionPercentage = "percentage"
+	PromotionFixed      = "fixed"
+)
+
+// Promotion discounts orders. Promotions with a code are coupons the buyer
+// has to enter; those without one apply automatically. PercentOff is a
+// percentage such as "15" for percentage promotions, AmountOff the discount of
+// fixed ones. Amounts are in Currency. A promotion can be limited to one
+// artisan's products or one category, and to a number of uses overall and
+// per user, where zero means unlimited.
+type Promotion struct {
+	Id             string     `db:"id" json:"id"`
+	Code           string     `db:"code" json:"code"`
+	Name           string     `db:"name" json:"name"`
+	Kind           string     `db:"kind" json:"kind"`
+	PercentOff     string     `db:"percent_off" json:"percent_off"`
+	AmountOff      Money      `db:"amount_off" json:"amount_off"`
+	Currency       string     `db:"currency" json:"currency"`
+	MinOrderAmount Money      `db:"min_order_amount" json:"min_order_amount"`
+	MaxUses        int32      `db:"max_uses" json:"max_uses"`
+	MaxUsesPerUser int32      `db:"max_uses_per_user" json:"max_uses_per_user"`
+	UsedCount      int32      `db:"used_count" json:"used_count"`
+	ArtisanId      string     `db:"artisan_id" json:"artisan_id"`
+	CategoryId     string     `db:"category_id" json:"category_id"`
+	StartsAt       *time.Time `db:"starts_at" json:"starts_at"`
+	EndsAt         *time.Time `db:"ends_at" json:"ends_at"`
+}
+
+// DiscountLine is the discount one promotion gave on one order item.
+type DiscountLine struct {
+	OrderItemId string `db:"order_item_id" json:"order_item_id"`
+	ProductId   string `db:"product_id" json:"product_id"`
+	PromotionId string `db:"promotion_id" json:"promotion_id"`
+	Code        string `db:"code" json:"code"`
+	Name        string `db:"name" json:"name"`
+	Amount      Money  `db:"amount" json:"amount"`
+}
@@ -0,0 +1,31 @@
+package models
+
+// Refund statuses. Refunds created by an approved return wait as pending
+// until they are sent to the provider; a refund being sent is processing.
+const (
+	RefundPending    = "pending"
+	RefundProcessing = "processing"
+	RefundCompleted  = "completed"
+	RefundFailed     = "failed"
+)
+
+type RefundItem struct {
+	OrderItemId string `db:"order_item_id" json:"order_item_id"`
+	Quantity    int32  `db:"quantity" json:"quantity"`
+	Amount      Money  `db:"amount" json:"amount"`
+}
+
+// Refund asks for part of a captured payment back. Amount and Items are
+// mutually exclusive: an item refund is priced from the order lines. A refund
+// for an approved return is identified by ReturnId alone.
+type Refund struct {
+	PaymentId string
+	OrderId   string
+	ReturnId  string
+	Amount    Money
+	Items     []*RefundItem
+	Reason    string
+	// ArtisanId is set when an artisan refunds, who may only refund their
+	// own products. It is empty for an admin.
+	ArtisanId string
+}
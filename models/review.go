@@ -0,0 +1,43 @@
+package models
+
+// Sort options of product listings besides the default order.
+const (
+	// ProductSortRating puts the best rated products first.
+	ProductSortRating = "rating"
+	// ProductSortReviews puts the most reviewed products first.
+	ProductSortReviews = "reviews"
+)
+
+// Statuses of a review. Only visible reviews are shown and counted in the
+// product's rating summary; pending reviews wait for a moderator.
+const (
+	RatingVisible = "visible"
+	RatingPending = "pending"
+	RatingHidden  = "hidden"
+)
+
+// Moderation actions on a review.
+const (
+	ModerationApprove = "approve"
+	ModerationHide    = "hide"
+	ModerationDelete  = "delete"
+)
+
+// Sort options of a product's reviews.
+const (
+	RatingSortNewest  = "newest"
+	RatingSortHighest = "highest"
+	RatingSortLowest  = "lowest"
+	RatingSortHelpful = "most_helpful"
+)
+
+// RatingPhoto is a photo attached to a review. The file itself is kept in
+// blob storage under Key.
+type RatingPhoto struct {
+	Id          string
+	RatingId    string
+	Key         string
+	ContentType string
+	SizeBytes   int64
+	CreatedAt   string
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Tracking statuses of a shipment, in the order a parcel normally goes
+// through them. An exception can happen at any point.
+const (
+	TrackingLabelCreated   = "label_created"
+	TrackingInTransit      = "in_transit"
+	TrackingOutForDelivery = "out_for_delivery"
+	TrackingDelivered      = "delivered"
+	TrackingException      = "exception"
+)
+
+// TrackingStatuses lists the valid tracking statuses.
+var TrackingStatuses = map[string]bool{
+	TrackingLabelCreated:   true,
+	TrackingInTransit:      true,
+	TrackingOutForDelivery: true,
+	TrackingDelivered:      true,
+	TrackingException:      true,
+}
+
+// TrackingEvent is a step in a shipment's journey. The shipment is named by
+// ShipmentId, or by Carrier and TrackingNumber when it comes from a carrier.
+type TrackingEvent struct {
+	ShipmentId     string    `json:"shipment_id"`
+	Carrier        string    `json:"carrier"`
+	TrackingNumber string    `json:"tracking_number"`
+	Status         string    `json:"status"`
+	Location       string    `json:"location"`
+	Description    string    `json:"description"`
+	OccurredAt     time.Time `json:"occurred_at"`
+}
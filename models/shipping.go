@@ -0,0 +1,24 @@
+package models
+
+// ShippingQuote is the price of sending an order with one shipping method.
+type ShippingQuote struct {
+	Method   string `json:"method"`
+	Name     string `json:"name"`
+	Amount   Money  `json:"amount"`
+	Currency string `json:"currency"`
+	MinDays  int32  `json:"min_days"`
+	MaxDays  int32  `json:"max_days"`
+}
+
+// Checkout is what the service works out before an order is written: the
+// exchange rates into the order currency, keyed by base currency, and the
+// shipping quote, already converted into the order currency. Promotions are
+// the ones the order may qualify for, with their amounts converted too.
+// FromCart removes the ordered products from the buyer's cart along with
+// placing the order.
+type Checkout struct {
+	Rates      map[string]*ExchangeRate
+	Shipping   *ShippingQuote
+	Promotions []*Promotion
+	FromCart   bool
+}
@@ -0,0 +1,26 @@
+package models
+
+// TaxRule taxes orders shipped to a country, optionally narrowed to a region
+// and to a product category. Rate is a decimal fraction, e.g. "0.2" for 20%.
+// Inclusive rules treat the listed price as already containing the tax.
+type TaxRule struct {
+	Id         string `db:"id" json:"id"`
+	Country    string `db:"country" json:"country"`
+	Region     string `db:"region" json:"region"`
+	CategoryId string `db:"category_id" json:"category_id"`
+	Name       string `db:"name" json:"name"`
+	Rate       string `db:"rate" json:"rate"`
+	Inclusive  bool   `db:"inclusive" json:"inclusive"`
+}
+
+// TaxLine is the tax charged on one order item under one rule.
+type TaxLine struct {
+	OrderItemId   string `db:"order_item_id" json:"order_item_id"`
+	ProductId     string `db:"product_id" json:"product_id"`
+	RuleId        string `db:"rule_id" json:"rule_id"`
+	Name          string `db:"name" json:"name"`
+	Rate          string `db:"rate" json:"rate"`
+	Inclusive     bool   `db:"inclusive" json:"inclusive"`
+	TaxableAmount Money  `db:"taxable_amount" json:"taxable_amount"`
+	Amount        Money  `db:"amount" json:"amount"`
+}
@@ -0,0 +1,22 @@
+package models
+
+// Wishlist alert kinds.
+const (
+	WishlistPriceDrop   = "price_drop"
+	WishlistBackInStock = "back_in_stock"
+)
+
+// WishlistAlert tells a buyer that a product on one of their wishlists got
+// cheaper or came back in stock.
+type WishlistAlert struct {
+	Kind         string `json:"kind"`
+	UserId       string `json:"user_id"`
+	WishlistId   string `json:"wishlist_id"`
+	WishlistName string `json:"wishlist_name"`
+	ProductId    string `json:"product_id"`
+	ProductName  string `json:"product_name"`
+	OldPrice     Money  `json:"old_price"`
+	Price        Money  `json:"price"`
+	Currency     string `json:"currency"`
+	Quantity     int32  `json:"quantity"`
+}
@@ -0,0 +1,36 @@
+package notify
+
+import (
+	"context"
+	"product-service/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LogNotifier writes notifications to the service log instead of delivering
+// them, for development and until a delivery channel is configured.
+type LogNotifier struct {
+	log *logrus.Logger
+}
+
+func NewLogNotifier(log *logrus.Logger) *LogNotifier {
+	return &LogNotifier{log: log}
+}
+
+func (n *LogNotifier) Name() string {
+	return "log"
+}
+
+func (n *LogNotifier) WishlistAlert(c context.Context, alert *models.WishlistAlert) error {
+	n.log.WithFields(logrus.Fields{
+		"kind":        alert.Kind,
+		"user_id":     alert.UserId,
+		"wishlist_id": alert.WishlistId,
+		"product_id":  alert.ProductId,
+		"old_price":   alert.OldPrice.String(),
+		"price":       alert.Price.String(),
+		"currency":    alert.Currency,
+		"quantity":    alert.Quantity,
+	}).Infof("wishlist alert: %s", alert.ProductName)
+	return nil
+}
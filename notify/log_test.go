@@ -0,0 +1,36 @@
+package notify
+
+import (
+	"context"
+	"testing"
+
+	"product-service/models"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogNotifierWishlistAlert(t *testing.T) {
+	log, hook := test.NewNullLogger()
+	notifier, err := NewNotifier("log", log)
+	assert.NoError(t, err)
+
+	err = notifier.WishlistAlert(context.Background(), &models.WishlistAlert{
+		Kind:        models.WishlistPriceDrop,
+		UserId:      "user-1",
+		ProductId:   "prod-1",
+		ProductName: "Vase",
+		OldPrice:    2500,
+		Price:       1999,
+		Currency:    "USD",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(hook.Entries))
+	assert.Equal(t, logrus.InfoLevel, hook.LastEntry().Level)
+	assert.Equal(t, "price_drop", hook.LastEntry().Data["kind"])
+	assert.Equal(t, "19.99", hook.LastEntry().Data["price"])
+
+	_, err = NewNotifier("pigeon", log)
+	assert.EqualError(t, err, "unknown notifier: pigeon")
+}
@@ -0,0 +1,27 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"product-service/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Notifier is implemented by every channel buyer notifications can be sent
+// through. Notifications are best effort: callers log a failed delivery and
+// carry on.
+type Notifier interface {
+	Name() string
+	WishlistAlert(c context.Context, alert *models.WishlistAlert) error
+}
+
+// NewNotifier returns the notifier registered under name.
+func NewNotifier(name string, log *logrus.Logger) (Notifier, error) {
+	switch name {
+	case "log", "":
+		return NewLogNotifier(log), nil
+	default:
+		return nil, fmt.Errorf("unknown notifier: %s", name)
+	}
+}
@@ -0,0 +1,190 @@
+package promotion
+
+import (
+	"fmt"
+	"math/big"
+	"product-service/models"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Line is an order item that can be discounted. Amount is the price times
+// quantity, in the order currency.
+type Line struct {
+	OrderItemId string
+	ProductId   string
+	ArtisanId   string
+	CategoryId  string
+	Amount      models.Money
+}
+
+// Result is the discount breakdown of an order. Applied lists the promotions
+// that gave a discount, in the order they were applied.
+type Result struct {
+	Lines    []*models.DiscountLine
+	Applied  []*models.Promotion
+	Discount models.Money
+}
+
+// ItemDiscount is the total discount given on an order item.
+func (r *Result) ItemDiscount(orderItemId string) models.Money {
+	var amount models.Money
+	for _, line := range r.Lines {
+		if line.OrderItemId == orderItemId {
+			amount += line.Amount
+		}
+	}
+	return amount
+}
+
+// IsApplied reports whether the promotion gave a discount.
+func (r *Result) IsApplied(promotionId string) bool {
+	for _, promo := range r.Applied {
+		if promo.Id == promotionId {
+			return true
+		}
+	}
+	return false
+}
+
+// ParsePercent reads a percentage discount, which must be in (0, 100].
+func ParsePercent(percent string) (*big.Rat, error) {
+	p, ok := new(big.Rat).SetString(strings.TrimSpace(percent))
+	if !ok || p.Sign() <= 0 || p.Cmp(big.NewRat(100, 1)) > 0 {
+		return nil, fmt.Errorf("invalid percentage %q, expected a number such as 15", percent)
+	}
+	return p, nil
+}
+
+// Active reports whether now falls in the promotion's validity window. The
+// window includes its start and excludes its end.
+func Active(promo *models.Promotion, now time.Time) bool {
+	if promo.StartsAt != nil && now.Before(*promo.StartsAt) {
+		return false
+	}
+	if promo.EndsAt != nil && !now.Before(*promo.EndsAt) {
+		return false
+	}
+	return true
+}
+
+// Eligible reports whether a line falls in the promotion's artisan and
+// category scope.
+func Eligible(promo *models.Promotion, line Line) bool {
+	if promo.ArtisanId != "" && promo.ArtisanId != line.ArtisanId {
+		return false
+	}
+	if promo.CategoryId != "" && promo.CategoryId != line.CategoryId {
+		return false
+	}
+	return true
+}
+
+// Apply discounts the lines with every active promotion whose minimum order
+// amount the eligible lines reach. Automatic promotions go first and coupons
+// after them, each on what is left of the line amounts, so a line is never
+// discounted below zero. Amounts must already be in the order currency.
+func Apply(promos []*models.Promotion, lines []Line, now time.Time) (*Result, error) {
+	ordered := make([]*models.Promotion, len(promos))
+	copy(ordered, promos)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].Code == "" && ordered[j].Code != ""
+	})
+
+	remaining := make([]models.Money, len(lines))
+	for i, line := range lines {
+		remaining[i] = line.Amount
+	}
+
+	res := &Result{}
+	for _, promo := range ordered {
+		if !Active(promo, now) {
+			continue
+		}
+		var eligible []int
+		var listed, left models.Money
+		for i, line := range lines {
+			if Eligible(promo, line) {
+				eligible = append(eligible, i)
+				listed += line.Amount
+				left += remaining[i]
+			}
+		}
+		if len(eligible) == 0 || listed < promo.MinOrderAmount || left <= 0 {
+			continue
+		}
+
+		amounts, err := discounts(promo, eligible, remaining, left)
+		if err != nil {
+			return nil, fmt.Errorf("promotion %s: %v", promo.Id, err)
+		}
+		var given models.Money
+		for k, i := range eligible {
+			if amounts[k] <= 0 {
+				continue
+			}
+			remaining[i] -= amounts[k]
+			given += amounts[k]
+			res.Lines = append(res.Lines, &models.DiscountLine{
+				OrderItemId: lines[i].OrderItemId,
+				ProductId:   lines[i].ProductId,
+				PromotionId: promo.Id,
+				Code:        promo.Code,
+				Name:        promo.Name,
+				Amount:      amounts[k],
+			})
+		}
+		if given > 0 {
+			res.Discount += given
+			res.Applied = append(res.Applied, promo)
+		}
+	}
+	return res, nil
+}
+
+// discounts works out the discount of each eligible line. Percentages are
+// rounded half away from zero per line; a fixed amount is capped at what is
+// left and spread over the lines in proportion to their amounts, with the
+// cents lost to rounding given to the first lines that still have room.
+func discounts(promo *models.Promotion, eligible []int, remaining []models.Money, left models.Money) ([]models.Money, error) {
+	amounts := make([]models.Money, len(eligible))
+	switch promo.Kind {
+	case models.PromotionPercentage:
+		percent, err := ParsePercent(promo.PercentOff)
+		if err != nil {
+			return nil, err
+		}
+		for k, i := range eligible {
+			amount := new(big.Rat).Mul(new(big.Rat).SetInt64(int64(remaining[i])), percent)
+			amount.Quo(amount, big.NewRat(100, 1))
+			cents, err := strconv.ParseInt(amount.FloatString(0), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("discount out of range: %v", err)
+			}
+			amounts[k] = models.Money(cents)
+		}
+	case models.PromotionFixed:
+		total := promo.AmountOff
+		if total > left {
+			total = left
+		}
+		var spread models.Money
+		for k, i := range eligible {
+			amounts[k] = total * remaining[i] / left
+			spread += amounts[k]
+		}
+		for k, i := range eligible {
+			extra := total - spread
+			if room := remaining[i] - amounts[k]; room < extra {
+				extra = room
+			}
+			amounts[k] += extra
+			spread += extra
+		}
+	default:
+		return nil, fmt.Errorf("unknown promotion kind %q", promo.Kind)
+	}
+	return amounts, nil
+}
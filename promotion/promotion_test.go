@@ -0,0 +1,82 @@
+package promotion
+
+import (
+	"testing"
+	"time"
+
+	"product-service/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var lines = []Line{
+	{OrderItemId: "item-1", ProductId: "prod-1", ArtisanId: "artisan-1", CategoryId: "cat-pottery", Amount: 3000},
+	{OrderItemId: "item-2", ProductId: "prod-2", ArtisanId: "artisan-2", CategoryId: "cat-food", Amount: 1000},
+}
+
+func TestApplyPercentage(t *testing.T) {
+	promo := &models.Promotion{Id: "promo-1", Name: "Spring sale", Kind: models.PromotionPercentage, PercentOff: "12.5"}
+
+	res, err := Apply([]*models.Promotion{promo}, lines, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, models.Money(500), res.Discount)
+	assert.Equal(t, models.Money(375), res.ItemDiscount("item-1"))
+	assert.Equal(t, models.Money(125), res.ItemDiscount("item-2"))
+	assert.True(t, res.IsApplied("promo-1"))
+}
+
+func TestApplyScopeAndMinimum(t *testing.T) {
+	pottery := &models.Promotion{Id: "promo-1", Kind: models.PromotionFixed, AmountOff: 500, CategoryId: "cat-pottery", MinOrderAmount: 2500}
+	food := &models.Promotion{Id: "promo-2", Kind: models.PromotionFixed, AmountOff: 500, ArtisanId: "artisan-2", MinOrderAmount: 2500}
+
+	res, err := Apply([]*models.Promotion{pottery, food}, lines, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, models.Money(500), res.Discount)
+	assert.Equal(t, models.Money(500), res.ItemDiscount("item-1"))
+	assert.False(t, res.IsApplied("promo-2"))
+}
+
+func TestApplyFixedIsSpreadAndCapped(t *testing.T) {
+	promo := &models.Promotion{Id: "promo-1", Code: "TENOFF", Kind: models.PromotionFixed, AmountOff: 1001}
+	res, err := Apply([]*models.Promotion{promo}, lines, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, models.Money(1001), res.Discount)
+	assert.Equal(t, models.Money(751), res.ItemDiscount("item-1"))
+	assert.Equal(t, models.Money(250), res.ItemDiscount("item-2"))
+
+	promo.AmountOff = 10000
+	res, err = Apply([]*models.Promotion{promo}, lines, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, models.Money(4000), res.Discount)
+}
+
+func TestApplyCouponAfterAutomatic(t *testing.T) {
+	coupon := &models.Promotion{Id: "promo-1", Code: "SAVE10", Kind: models.PromotionPercentage, PercentOff: "10"}
+	automatic := &models.Promotion{Id: "promo-2", Kind: models.PromotionFixed, AmountOff: 2000}
+
+	res, err := Apply([]*models.Promotion{coupon, automatic}, lines, time.Now())
+	assert.NoError(t, err)
+	// The automatic 20.00 leaves 20.00, of which the coupon takes 10%.
+	assert.Equal(t, models.Money(2200), res.Discount)
+	assert.Equal(t, "promo-2", res.Applied[0].Id)
+}
+
+func TestApplyValidityWindow(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	ended := now
+	promo := &models.Promotion{Id: "promo-1", Kind: models.PromotionPercentage, PercentOff: "10", EndsAt: &ended}
+
+	res, err := Apply([]*models.Promotion{promo}, lines, now)
+	assert.NoError(t, err)
+	assert.Equal(t, models.Money(0), res.Discount)
+	assert.True(t, Active(promo, now.Add(-time.Second)))
+}
+
+func TestParsePercent(t *testing.T) {
+	for _, in := range []string{"", "0", "-5", "100.5", "abc"} {
+		_, err := ParsePercent(in)
+		assert.Error(t, err, in)
+	}
+	_, err := ParsePercent("100")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,91 @@
+package redact
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// cardNumberPattern matches 13 to 19 digits, optionally grouped with
+	// spaces or dashes. Matches are only treated as card numbers when they
+	// pass the Luhn check.
+	cardNumberPattern = regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`)
+	cvvPattern        = regexp.MustCompile(`(?i)\b(cvv2?|cvc2?|security[_ ]code)(["']?\s*[:=]\s*["']?)\d{3,4}`)
+)
+
+// sensitiveKeys are field names whose values are always hidden.
+var sensitiveKeys = map[string]bool{
+	"card_number": true,
+	"cardnumber":  true,
+	"pan":         true,
+	"cvv":         true,
+	"cvc":         true,
+	"expiry_date": true,
+	"expirydate":  true,
+}
+
+const mask = "[REDACTED]"
+
+// luhn reports whether the digits form a valid card number checksum.
+func luhn(digits string) bool {
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+func digitsOf(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// ContainsCardNumber reports whether s holds something that looks like a
+// payment card number.
+func ContainsCardNumber(s string) bool {
+	for _, match := range cardNumberPattern.FindAllString(s, -1) {
+		if luhn(digitsOf(match)) {
+			return true
+		}
+	}
+	return false
+}
+
+// String masks card numbers, keeping their last four digits, and CVV codes
+// found in s.
+func String(s string) string {
+	s = cardNumberPattern.ReplaceAllStringFunc(s, func(match string) string {
+		digits := digitsOf(match)
+		if !luhn(digits) {
+			return match
+		}
+		return strings.Repeat("*", len(digits)-4) + digits[len(digits)-4:]
+	})
+	return cvvPattern.ReplaceAllString(s, "${1}${2}***")
+}
+
+// IsSensitiveKey reports whether a field with this name holds card data.
+func IsSensitiveKey(key string) bool {
+	return sensitiveKeys[strings.ToLower(key)]
+}
+
+// Field hides the value of a sensitive field and redacts any other.
+func Field(key, value string) string {
+	if IsSensitiveKey(key) && value != "" {
+		return mask
+	}
+	return String(value)
+}
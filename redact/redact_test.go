@@ -0,0 +1,29 @@
+package redact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestString(t *testing.T) {
+	assert.Equal(t, "card ************4242 declined", String("card 4242424242424242 declined"))
+	assert.Equal(t, "card ************1111", String("card 4111-1111-1111-1111"))
+	assert.Equal(t, `card_number:"************4242" cvv:"***"`, String(`card_number:"4242424242424242" cvv:"123"`))
+	assert.Equal(t, `{"cvc": "***"}`, String(`{"cvc": "1234"}`))
+
+	// Numbers that fail the Luhn check are left alone.
+	assert.Equal(t, "order 1234567890123456", String("order 1234567890123456"))
+}
+
+func TestContainsCardNumber(t *testing.T) {
+	assert.True(t, ContainsCardNumber("4242 4242 4242 4242"))
+	assert.False(t, ContainsCardNumber("tok_visa_4242"))
+	assert.False(t, ContainsCardNumber("1234567890123456"))
+}
+
+func TestField(t *testing.T) {
+	assert.Equal(t, "[REDACTED]", Field("cvv", "123"))
+	assert.Equal(t, "[REDACTED]", Field("Card_Number", "tok_visa_4242"))
+	assert.Equal(t, "************4242", Field("source", "4242424242424242"))
+}
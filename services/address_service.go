@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	pro "product-service/genproto/product_service"
+	"regexp"
+	"strings"
+)
+
+// addressRule describes what a valid address looks like in a country.
+type addressRule struct {
+	requireRegion  bool
+	requireZipCode bool
+	zipCode        *regexp.Regexp
+}
+
+var countryCode = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// addressRules are keyed by ISO 3166-1 alpha-2 country code. Countries that
+// are not listed only need street, city and country.
+var addressRules = map[string]addressRule{
+	"US": {requireRegion: true, requireZipCode: true, zipCode: regexp.MustCompile(`^\d{5}(-\d{4})?$`)},
+	"CA": {requireRegion: true, requireZipCode: true, zipCode: regexp.MustCompile(`^[A-Z]\d[A-Z] ?\d[A-Z]\d$`)},
+	"GB": {requireZipCode: true, zipCode: regexp.MustCompile(`^[A-Z]{1,2}\d[A-Z\d]? ?\d[A-Z]{2}$`)},
+	"DE": {requireZipCode: true, zipCode: regexp.MustCompile(`^\d{5}$`)},
+	"FR": {requireZipCode: true, zipCode: regexp.MustCompile(`^\d{5}$`)},
+	"IT": {requireZipCode: true, zipCode: regexp.MustCompile(`^\d{5}$`)},
+	"ES": {requireZipCode: true, zipCode: regexp.MustCompile(`^\d{5}$`)},
+	"TR": {requireZipCode: true, zipCode: regexp.MustCompile(`^\d{5}$`)},
+	"UZ": {requireZipCode: true, zipCode: regexp.MustCompile(`^\d{6}$`)},
+	"RU": {requireZipCode: true, zipCode: regexp.MustCompile(`^\d{6}$`)},
+	"IN": {requireRegion: true, requireZipCode: true, zipCode: regexp.MustCompile(`^\d{6}$`)},
+	"JP": {requireRegion: true, requireZipCode: true, zipCode: regexp.MustCompile(`^\d{3}-?\d{4}$`)},
+	"AE": {},
+}
+
+// normalizeAddress trims the address fields and upper-cases the country and
+// postal code so they can be matched against addressRules.
+func normalizeAddress(address *pro.Address) {
+	address.Street = strings.TrimSpace(address.Street)
+	address.City = strings.TrimSpace(address.City)
+	address.Region = strings.TrimSpace(address.Region)
+	address.RecipientName = strings.TrimSpace(address.RecipientName)
+	address.Phone = strings.TrimSpace(address.Phone)
+	address.Country = strings.ToUpper(strings.TrimSpace(address.Country))
+	address.ZipCode = strings.ToUpper(strings.TrimSpace(address.ZipCode))
+}
+
+func validateAddress(address *pro.Address) error {
+	if address == nil {
+		return fmt.Errorf("address is required")
+	}
+	normalizeAddress(address)
+
+	if address.Street == "" {
+		return fmt.Errorf("street is required")
+	}
+	if address.City == "" {
+		return fmt.Errorf("city is required")
+	}
+	if !countryCode.MatchString(address.Country) {
+		return fmt.Errorf("country must be a two-letter ISO code")
+	}
+
+	rule := addressRules[address.Country]
+	if rule.requireRegion && address.Region == "" {
+		return fmt.Errorf("region is required for %s", address.Country)
+	}
+	if address.ZipCode == "" {
+		if rule.requireZipCode {
+			return fmt.Errorf("zip code is required for %s", address.Country)
+		}
+		return nil
+	}
+	if rule.zipCode != nil && !rule.zipCode.MatchString(address.ZipCode) {
+		return fmt.Errorf("invalid zip code %q for %s", address.ZipCode, address.Country)
+	}
+	return nil
+}
+
+// formatAddress renders an address as a single line.
+func formatAddress(address *pro.Address) string {
+	var parts []string
+	for _, part := range []string{address.Street, address.City, address.Region, address.ZipCode, address.Country} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// resolveAddress returns the user's saved address when addressId is given, or
+// the address sent with the request otherwise, validated.
+func (p *productService) resolveAddress(c context.Context, address *pro.Address, addressId, userId string) (*pro.Address, error) {
+	if addressId != "" {
+		saved, err := p.productRepo.GetAddress(c, addressId, userId)
+		if err != nil {
+			return nil, err
+		}
+		address = saved.Address
+	}
+	if address == nil {
+		return nil, fmt.Errorf("shipping address is required")
+	}
+	if err := validateAddress(address); err != nil {
+		return nil, fmt.Errorf("invalid shipping address: %v", err)
+	}
+	return address, nil
+}
+
+// resolveShippingAddress fills the order address from the user's address book
+// when address_id is given, and validates it. Older clients that only send
+// the free-form shipping_address still place orders: the text is kept as the
+// street, as before addresses were structured. Such orders have no country,
+// so they ship at the fallback rate and no tax rule applies to them.
+func (p *productService) resolveShippingAddress(c context.Context, order *pro.PlaceOrderRequest) error {
+	if order.Address == nil && order.AddressId == "" {
+		if legacy := strings.TrimSpace(order.ShippingAddress); legacy != "" {
+			order.Address = &pro.Address{Street: legacy}
+			order.ShippingAddress = legacy
+			return nil
+		}
+	}
+	address, err := p.resolveAddress(c, order.Address, order.AddressId, order.UserId)
+	if err != nil {
+		return err
+	}
+	order.Address = address
+	order.ShippingAddress = formatAddress(order.Address)
+	return nil
+}
+
+func (p *productService) AddAddress(c context.Context, request *pro.AddAddressRequest) (*pro.AddAddressResponse, error) {
+	if !p.isValidUser(request.UserId) {
+		p.log.Errorf("user is not valid")
+		return nil, fmt.Errorf("user is not valid")
+	}
+	if err := validateAddress(request.Address); err != nil {
+		return nil, fmt.Errorf("invalid address: %v", err)
+	}
+
+	res, err := p.productRepo.AddAddress(c, request)
+	if err != nil {
+		p.log.Errorf("failed to add address: %v", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+func (p *productService) GetAddresses(c context.Context, request *pro.GetAddressesRequest) (*pro.GetAddressesResponse, error) {
+	res, err := p.productRepo.GetAddresses(c, request)
+	if err != nil {
+		p.log.Errorf("failed to get addresses: %v", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+func (p *productService) UpdateAddress(c context.Context, request *pro.UpdateAddressRequest) (*pro.UpdateAddressResponse, error) {
+	if err := validateAddress(request.Address); err != nil {
+		return nil, fmt.Errorf("invalid address: %v", err)
+	}
+
+	res, err := p.productRepo.UpdateAddress(c, request)
+	if err != nil {
+		p.log.Errorf("failed to update address: %v", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+func (p *productService) DeleteAddress(c context.Context, request *pro.DeleteAddressRequest) (*pro.DeleteAddressResponse, error) {
+	res, err := p.productRepo.DeleteAddress(c, request)
+	if err != nil {
+		p.log.Errorf("failed to delete address: %v", err)
+		return nil, err
+	}
+	res.Message = "address deleted successfully"
+	return res, nil
+}
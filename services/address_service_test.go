@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	pro "product-service/genproto/product_service"
+	"product-service/storage/postgres"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		address *pro.Address
+		wantErr bool
+	}{
+		{"missing address", nil, true},
+		{"missing street", &pro.Address{City: "Tashkent", Country: "UZ", ZipCode: "100000"}, true},
+		{"bad country", &pro.Address{Street: "Amir Temur 1", City: "Tashkent", Country: "Uzbekistan"}, true},
+		{"uz ok", &pro.Address{Street: "Amir Temur 1", City: "Tashkent", Country: "uz", ZipCode: "100000"}, false},
+		{"uz bad zip", &pro.Address{Street: "Amir Temur 1", City: "Tashkent", Country: "UZ", ZipCode: "1000"}, true},
+		{"us needs region", &pro.Address{Street: "1 Main St", City: "Austin", Country: "US", ZipCode: "73301"}, true},
+		{"us zip+4", &pro.Address{Street: "1 Main St", City: "Austin", Region: "TX", Country: "US", ZipCode: "73301-0001"}, false},
+		{"gb postcode", &pro.Address{Street: "10 Downing St", City: "London", Country: "GB", ZipCode: "sw1a 2aa"}, false},
+		{"unlisted country without zip", &pro.Address{Street: "Main 1", City: "Almaty", Country: "KZ"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAddress(tt.address)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+type addressRepo struct {
+	postgres.ProductRepo
+	saved map[string]*pro.SavedAddress
+}
+
+func (r *addressRepo) GetAddress(c context.Context, id, userId string) (*pro.SavedAddress, error) {
+	if address, ok := r.saved[id]; ok && address.UserId == userId {
+		return address, nil
+	}
+	return nil, fmt.Errorf("address not found")
+}
+
+func TestResolveShippingAddress(t *testing.T) {
+	repo := &addressRepo{saved: map[string]*pro.SavedAddress{
+		"addr-1": {Id: "addr-1", UserId: "user-1", Address: &pro.Address{Street: "Amir Temur 1", City: "Tashkent", Country: "UZ", ZipCode: "100000"}},
+	}}
+	p := newTestService(repo, nil)
+	ctx := context.Background()
+
+	order := &pro.PlaceOrderRequest{UserId: "user-1", AddressId: "addr-1"}
+	assert.NoError(t, p.resolveShippingAddress(ctx, order))
+	assert.Equal(t, "UZ", order.Address.Country)
+	assert.Equal(t, "Amir Temur 1, Tashkent, 100000, UZ", order.ShippingAddress)
+
+	order = &pro.PlaceOrderRequest{UserId: "user-2", AddressId: "addr-1"}
+	assert.EqualError(t, p.resolveShippingAddress(ctx, order), "address not found")
+
+	order = &pro.PlaceOrderRequest{UserId: "user-1", Address: &pro.Address{Street: "1 Main St", City: "Austin", Country: "us", ZipCode: "73301"}}
+	assert.EqualError(t, p.resolveShippingAddress(ctx, order), "invalid shipping address: region is required for US")
+
+	order = &pro.PlaceOrderRequest{UserId: "user-1", ShippingAddress: " Main 1, Tashkent "}
+	assert.NoError(t, p.resolveShippingAddress(ctx, order))
+	assert.Equal(t, "Main 1, Tashkent", order.Address.Street)
+	assert.Equal(t, "Main 1, Tashkent", order.ShippingAddress)
+
+	order = &pro.PlaceOrderRequest{UserId: "user-1"}
+	assert.EqualError(t, p.resolveShippingAddress(ctx, order), "shipping address is required")
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	pro "product-service/genproto/product_service"
+	"product-service/models"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// cart prices the user's cart in the requested currency. Prices and stock are
+// read live, so every item carries a warning when it can no longer be ordered
+// as it is. Products taken off sale do not count towards the subtotal.
+func (p *productService) cart(c context.Context, userId, code string) (*pro.Cart, error) {
+	code, err := p.resolveCurrency(code)
+	if err != nil {
+		return nil, err
+	}
+	items, err := p.productRepo.GetCartItems(c, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	var bases []string
+	for _, item := range items {
+		bases = append(bases, item.Currency)
+	}
+	rates, err := p.exchangeRates(c, bases, code)
+	if err != nil {
+		return nil, err
+	}
+
+	cart := &pro.Cart{UserId: userId, Currency: code, Items: []*pro.CartItem{}}
+	var subtotal models.Money
+	for _, item := range items {
+		price := item.Price
+		if item.Currency != code {
+			price, err = item.Price.Convert(rates[item.Currency].Rate)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if !item.Deleted {
+			subtotal += price.Mul(item.Quantity)
+		}
+		cart.ItemCount += item.Quantity
+		cart.Items = append(cart.Items, &pro.CartItem{
+			ProductId:         item.ProductId,
+			ProductName:       item.ProductName,
+			Quantity:          item.Quantity,
+			Price:             price.Float64(),
+			Currency:          code,
+			AvailableQuantity: item.Available,
+			Warning:           item.Warning(),
+			CreatedAt:         item.CreatedAt,
+			UpdatedAt:         item.UpdatedAt,
+		})
+	}
+	cart.SubtotalAmount = subtotal.Float64()
+	return cart, nil
+}
+
+func (p *productService) AddCartItem(c context.Context, request *pro.AddCartItemRequest) (*pro.AddCartItemResponse, error) {
+	if !p.isValidUser(request.UserId) {
+		p.log.Errorf("user is not valid")
+		return nil, fmt.Errorf("user is not valid")
+	}
+	if request.Quantity <= 0 {
+		return nil, fmt.Errorf("quantity must be positive")
+	}
+
+	if err := p.productRepo.AddCartItem(c, request.UserId, request.ProductId, request.Quantity); err != nil {
+		p.log.Errorf("failed to add cart item: %v", err)
+		return nil, err
+	}
+	cart, err := p.cart(c, request.UserId, request.Currency)
+	if err != nil {
+		p.log.Errorf("failed to get cart: %v", err)
+		return nil, err
+	}
+	return &pro.AddCartItemResponse{Cart: cart}, nil
+}
+
+// UpdateCartItem sets the quantity of an item in the cart; a quantity of zero
+// removes it.
+func (p *productService) UpdateCartItem(c context.Context, request *pro.UpdateCartItemRequest) (*pro.UpdateCartItemResponse, error) {
+	if request.Quantity < 0 {
+		return nil, fmt.Errorf("quantity must not be negative")
+	}
+
+	var err error
+	if request.Quantity == 0 {
+		err = p.productRepo.RemoveCartItem(c, request.UserId, request.ProductId)
+	} else {
+		err = p.productRepo.UpdateCartItem(c, request.UserId, request.ProductId, request.Quantity)
+	}
+	if err != nil {
+		p.log.Errorf("failed to update cart item: %v", err)
+		return nil, err
+	}
+	cart, err := p.cart(c, request.UserId, request.Currency)
+	if err != nil {
+		p.log.Errorf("failed to get cart: %v", err)
+		return nil, err
+	}
+	return &pro.UpdateCartItemResponse{Cart: cart}, nil
+}
+
+func (p *productService) RemoveCartItem(c context.Context, request *pro.RemoveCartItemRequest) (*pro.RemoveCartItemResponse, error) {
+	if err := p.productRepo.RemoveCartItem(c, request.UserId, request.ProductId); err != nil {
+		p.log.Errorf("failed to remove cart item: %v", err)
+		return nil, err
+	}
+	cart, err := p.cart(c, request.UserId, request.Currency)
+	if err != nil {
+		p.log.Errorf("failed to get cart: %v", err)
+		return nil, err
+	}
+	return &pro.RemoveCartItemResponse{Cart: cart}, nil
+}
+
+func (p *productService) GetCart(c context.Context, request *pro.GetCartRequest) (*pro.GetCartResponse, error) {
+	cart, err := p.cart(c, request.UserId, request.Currency)
+	if err != nil {
+		p.log.Errorf("failed to get cart: %v", err)
+		return nil, err
+	}
+	return &pro.GetCartResponse{Cart: cart}, nil
+}
+
+// CheckoutCart places an order for everything in the user's cart, the same
+// way PlaceOrder does, and empties the cart once the order is written. Carts
+// with items that cannot be ordered as they are must be fixed first.
+func (p *productService) CheckoutCart(c context.Context, request *pro.CheckoutCartRequest) (*pro.PlaceOrderResponse, error) {
+	if !p.isValidUser(request.UserId) {
+		p.log.Errorf("user is not valid")
+		return nil, fmt.Errorf("user is not valid")
+	}
+
+	key := idempotencyKey(c, request.IdempotencyKey)
+	res, err := p.idempotent(c, "checkout_cart", key, request, &pro.PlaceOrderResponse{}, func() (proto.Message, error) {
+		items, err := p.productRepo.GetCartItems(c, request.UserId)
+		if err != nil {
+			return nil, err
+		}
+		if len(items) == 0 {
+			return nil, fmt.Errorf("cart is empty")
+		}
+
+		order := &pro.PlaceOrderRequest{
+			UserId:         request.UserId,
+			Status:         "pending",
+			Address:        request.Address,
+			AddressId:      request.AddressId,
+			Currency:       request.Currency,
+			ShippingMethod: request.ShippingMethod,
+			CouponCode:     request.CouponCode,
+		}
+		for _, item := range items {
+			if warning := item.Warning(); warning != "" {
+				return nil, fmt.Errorf("cart item %s cannot be ordered: %s", item.ProductId, warning)
+			}
+			order.Items = append(order.Items, &pro.Item{ProductId: item.ProductId, Quantity: item.Quantity})
+		}
+		return p.placeOrder(c, order, true)
+	})
+	if err != nil {
+		p.log.Errorf("failed to check out cart: %v", err)
+		return nil, err
+	}
+
+	return res.(*pro.PlaceOrderResponse), nil
+}
@@ -0,0 +1,34 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"product-service/currency"
+	"product-service/models"
+)
+
+// resolveCurrency validates a currency code, falling back to the configured
+// default when none is given.
+func (p *productService) resolveCurrency(code string) (string, error) {
+	if code == "" {
+		code = p.config.CurrencyConfig.DefaultCurrency
+	}
+	return currency.Normalize(code)
+}
+
+// exchangeRates fetches the rates that convert each of the given currencies
+// into quote, keyed by base currency.
+func (p *productService) exchangeRates(c context.Context, bases []string, quote string) (map[string]*models.ExchangeRate, error) {
+	rates := make(map[string]*models.ExchangeRate)
+	for _, base := range bases {
+		if base == quote || rates[base] != nil {
+			continue
+		}
+		rate, err := p.rateSource.Rate(c, base, quote)
+		if err != nil {
+			return nil, fmt.Errorf("cannot price order in %s: %v", quote, err)
+		}
+		rates[base] = rate
+	}
+	return rates, nil
+}
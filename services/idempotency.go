@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"product-service/models"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+// idempotencyKeyHeader is the gRPC metadata key clients may use instead of the
+// idempotency_key request field.
+const idempotencyKeyHeader = "idempotency-key"
+
+// idempotencyKey returns the key from the request field, falling back to the
+// incoming gRPC metadata.
+func idempotencyKey(c context.Context, fromRequest string) string {
+	if fromRequest != "" {
+		return fromRequest
+	}
+	md, ok := metadata.FromIncomingContext(c)
+	if !ok {
+		return ""
+	}
+	if values := md.Get(idempotencyKeyHeader); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// requestHash fingerprints a request, ignoring its idempotency_key field, so a
+// retried request can be told apart from a different one reusing the key.
+func requestHash(request proto.Message) (string, error) {
+	m := proto.Clone(request).ProtoReflect()
+	if fd := m.Descriptor().Fields().ByName("idempotency_key"); fd != nil {
+		m.Clear(fd)
+	}
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(m.Interface())
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// idempotent runs call at most once per scope and key within the configured
+// window. Retries with the same parameters get the stored response unmarshaled
+// into response; reusing the key with different parameters is rejected.
+func (p *productService) idempotent(c context.Context, scope, key string, request, response proto.Message, call func() (proto.Message, error)) (proto.Message, error) {
+	if key == "" {
+		return call()
+	}
+
+	hash, err := requestHash(request)
+	if err != nil {
+		return nil, fmt.Errorf("error hashing request: %v", err)
+	}
+	record := &models.IdempotencyKey{Scope: scope, Key: key, RequestHash: hash}
+	expiredBefore := time.Now().Add(-p.config.OrderConfig.IdempotencyWindow)
+
+	existing, reserved, err := p.productRepo.ReserveIdempotencyKey(c, record, expiredBefore)
+	if err != nil {
+		p.log.Errorf("failed to reserve idempotency key: %v", err)
+		return nil, err
+	}
+	if !reserved {
+		if existing.RequestHash != hash {
+			return nil, fmt.Errorf("idempotency key %s was already used with different parameters", key)
+		}
+		if existing.Response == nil {
+			return nil, fmt.Errorf("request with idempotency key %s is still in progress", key)
+		}
+		if err := proto.Unmarshal(existing.Response, response); err != nil {
+			return nil, fmt.Errorf("error decoding stored response: %v", err)
+		}
+		return response, nil
+	}
+
+	res, err := call()
+	if err != nil {
+		p.releaseIdempotencyKey(c, record)
+		return nil, err
+	}
+
+	record.Response, err = proto.Marshal(res)
+	if err != nil {
+		p.releaseIdempotencyKey(c, record)
+		return nil, fmt.Errorf("error encoding response: %v", err)
+	}
+	p.completeIdempotencyKey(c, record)
+	return res, nil
+}
+
+// completeIdempotencyAttempts is how often storing a response is tried.
+const completeIdempotencyAttempts = 3
+
+// completeIdempotencyKey stores the response of a call that succeeded. The
+// key is never released at this point: a retry would run the call again, so
+// if the response cannot be stored the key stays in progress until it
+// expires.
+func (p *productService) completeIdempotencyKey(c context.Context, record *models.IdempotencyKey) {
+	c = context.WithoutCancel(c)
+	var err error
+	for attempt := 1; attempt <= completeIdempotencyAttempts; attempt++ {
+		if err = p.productRepo.CompleteIdempotencyKey(c, record); err == nil {
+			return
+		}
+		if attempt < completeIdempotencyAttempts {
+			time.Sleep(time.Duration(attempt) * 50 * time.Millisecond)
+		}
+	}
+	p.log.Errorf("failed to store idempotent response for key %s: %v", record.Key, err)
+}
+
+// releaseIdempotencyKey frees a reserved key so the client can retry with it.
+// It also runs when the client has already gone away.
+func (p *productService) releaseIdempotencyKey(c context.Context, record *models.IdempotencyKey) {
+	if err := p.productRepo.ReleaseIdempotencyKey(context.WithoutCancel(c), record); err != nil {
+		p.log.Errorf("failed to release idempotency key %s: %v", record.Key, err)
+	}
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	pro "product-service/genproto/product_service"
+	"product-service/models"
+	"product-service/storage/postgres"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+type idempotencyRepo struct {
+	postgres.ProductRepo
+	// completeFailures is how many calls to CompleteIdempotencyKey fail.
+	completeFailures int
+	completed        int
+	released         int
+	// ctxErr is the error of a canceled context passed to the repository.
+	ctxErr error
+}
+
+func (r *idempotencyRepo) ReserveIdempotencyKey(c context.Context, key *models.IdempotencyKey, expiredBefore time.Time) (*models.IdempotencyKey, bool, error) {
+	return key, true, nil
+}
+
+func (r *idempotencyRepo) CompleteIdempotencyKey(c context.Context, key *models.IdempotencyKey) error {
+	r.completed++
+	if c.Err() != nil {
+		r.ctxErr = c.Err()
+	}
+	if r.completed <= r.completeFailures {
+		return fmt.Errorf("connection reset")
+	}
+	return nil
+}
+
+func (r *idempotencyRepo) ReleaseIdempotencyKey(c context.Context, key *models.IdempotencyKey) error {
+	r.released++
+	if c.Err() != nil {
+		r.ctxErr = c.Err()
+	}
+	return nil
+}
+
+func TestIdempotentReleasesKeyOnFailure(t *testing.T) {
+	repo := &idempotencyRepo{}
+	p := newTestService(repo, nil)
+	request := &pro.PayOrderRequest{OrderId: "order-1"}
+
+	_, err := p.idempotent(context.Background(), "pay_order", "key-1", request, &pro.PayOrderResponse{}, func() (proto.Message, error) {
+		return nil, fmt.Errorf("card declined")
+	})
+	assert.EqualError(t, err, "card declined")
+	assert.Equal(t, 0, repo.completed)
+	assert.Equal(t, 1, repo.released)
+}
+
+func TestIdempotentRetriesStoringResponse(t *testing.T) {
+	repo := &idempotencyRepo{completeFailures: 1}
+	p := newTestService(repo, nil)
+	request := &pro.PayOrderRequest{OrderId: "order-1"}
+
+	c, cancel := context.WithCancel(context.Background())
+	cancel()
+	res, err := p.idempotent(c, "pay_order", "key-1", request, &pro.PayOrderResponse{}, func() (proto.Message, error) {
+		return &pro.PayOrderResponse{Id: "pay-1"}, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "pay-1", res.(*pro.PayOrderResponse).Id)
+	assert.Equal(t, 2, repo.completed)
+	assert.Equal(t, 0, repo.released)
+	assert.NoError(t, repo.ctxErr)
+}
+
+func TestIdempotentKeepsKeyWhenResponseIsNotStored(t *testing.T) {
+	repo := &idempotencyRepo{completeFailures: completeIdempotencyAttempts}
+	p := newTestService(repo, nil)
+	request := &pro.PayOrderRequest{OrderId: "order-1"}
+
+	res, err := p.idempotent(context.Background(), "pay_order", "key-1", request, &pro.PayOrderResponse{}, func() (proto.Message, error) {
+		return &pro.PayOrderResponse{Id: "pay-1"}, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "pay-1", res.(*pro.PayOrderResponse).Id)
+	assert.Equal(t, completeIdempotencyAttempts, repo.completed)
+	// Releasing the key would let a retry charge the order again.
+	assert.Equal(t, 0, repo.released)
+}
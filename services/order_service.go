@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	auth "product-service/genproto/authentication_service"
+	pro "product-service/genproto/product_service"
+	"strconv"
+	"time"
+)
+
+var orderStatuses = map[string]bool{
+	"pending":    true,
+	"processing": true,
+	"shipped":    true,
+	"delivered":  true,
+	"canceled":   true,
+	"returned":   true,
+}
+
+const (
+	defaultOrderPageSize = 10
+	maxOrderPageSize     = 100
+)
+
+// orderPage validates the paging of an order listing and fills in the
+// defaults: the first page of 10 orders. Pages start at 1 and hold at most
+// 100 orders.
+func orderPage(limit, page string) (string, string, error) {
+	if limit == "" {
+		limit = strconv.Itoa(defaultOrderPageSize)
+	}
+	if page == "" {
+		page = "1"
+	}
+	l, err := strconv.Atoi(limit)
+	if err != nil || l < 1 || l > maxOrderPageSize {
+		return "", "", fmt.Errorf("limit must be a number from 1 to %d", maxOrderPageSize)
+	}
+	n, err := strconv.Atoi(page)
+	if err != nil || n < 1 {
+		return "", "", fmt.Errorf("page must be a positive number")
+	}
+	return strconv.Itoa(l), strconv.Itoa(n), nil
+}
+
+func (p *productService) ListArtisanOrders(c context.Context, request *pro.ListArtisanOrdersRequest) (*pro.ListArtisanOrdersResponse, error) {
+	// Only artisans can see their orders, and only for their own products
+	res, err := p.authService.GetUserInfo(c, &auth.GetUserInfoRequest{Id: request.ArtisanId})
+	if err != nil {
+		p.log.Errorf("user is not found: %v", err)
+		return nil, fmt.Errorf("user is not found: %v", err)
+	}
+	if res.User == nil || res.User.UserType != "artisan" {
+		p.log.Errorf("user is not an artisan")
+		return nil, fmt.Errorf("user is not an artisan")
+	}
+
+	if request.Status != "" && !orderStatuses[request.Status] {
+		return nil, fmt.Errorf("invalid order status: %s", request.Status)
+	}
+	for _, date := range []string{request.FromDate, request.ToDate} {
+		if date == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return nil, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", date)
+		}
+	}
+	limit, page, err := orderPage(request.Limit, request.Page)
+	if err != nil {
+		return nil, err
+	}
+	request.Limit, request.Page = limit, page
+
+	orders, err := p.productRepo.ListArtisanOrders(c, request)
+	if err != nil {
+		p.log.Errorf("failed to list artisan orders: %v", err)
+		return nil, err
+	}
+	return orders, nil
+}
+
+func (p *productService) ListMyOrders(c context.Context, request *pro.ListMyOrdersRequest) (*pro.ListMyOrdersResponse, error) {
+	if !p.isValidUser(request.UserId) {
+		p.log.Errorf("user is not valid")
+		return nil, fmt.Errorf("user is not valid")
+	}
+	if request.Status != "" && !orderStatuses[request.Status] {
+		return nil, fmt.Errorf("invalid order status: %s", request.Status)
+	}
+	limit, page, err := orderPage(request.Limit, request.Page)
+	if err != nil {
+		return nil, err
+	}
+	request.Limit, request.Page = limit, page
+
+	res, err := p.productRepo.ListMyOrders(c, request)
+	if err != nil {
+		p.log.Errorf("failed to list user orders: %v", err)
+		return nil, err
+	}
+	return res, nil
+}
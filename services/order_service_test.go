@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	pro "product-service/genproto/product_service"
+	"product-service/models"
+	"product-service/storage/postgres"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderPage(t *testing.T) {
+	limit, page, err := orderPage("", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "10", limit)
+	assert.Equal(t, "1", page)
+
+	limit, page, err = orderPage("100", "3")
+	assert.NoError(t, err)
+	assert.Equal(t, "100", limit)
+	assert.Equal(t, "3", page)
+
+	for _, l := range []string{"0", "-5", "101", "ten"} {
+		_, _, err := orderPage(l, "1")
+		assert.EqualError(t, err, "limit must be a number from 1 to 100", l)
+	}
+	for _, p := range []string{"0", "-1", "first"} {
+		_, _, err := orderPage("10", p)
+		assert.EqualError(t, err, "page must be a positive number", p)
+	}
+}
+
+type orderStatusRepo struct {
+	postgres.ProductRepo
+	updated []string
+}
+
+func (r *orderStatusRepo) UpdateOrderStatus(c context.Context, request *pro.UpdateOrderStatusRequest) (*pro.UpdateOrderStatusResponse, error) {
+	r.updated = append(r.updated, request.Status)
+	return &pro.UpdateOrderStatusResponse{Id: request.Id, Status: request.Status}, nil
+}
+
+func TestUpdateOrderStatusDoesNotCancel(t *testing.T) {
+	repo := &orderStatusRepo{}
+	p := newTestService(repo, nil)
+
+	_, err := p.UpdateOrderStatus(context.Background(), &pro.UpdateOrderStatusRequest{Id: "order-1", Status: "canceled"})
+	assert.EqualError(t, err, "use CancelOrder to cancel an order")
+
+	_, err = p.UpdateOrderStatus(context.Background(), &pro.UpdateOrderStatusRequest{Id: "order-1", Status: "shipped"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"shipped"}, repo.updated)
+}
+
+type cancelOrderRepo struct {
+	postgres.ProductRepo
+	checked []string
+}
+
+func (r *cancelOrderRepo) CancelOrder(c context.Context, request *pro.CancelOrderRequest) (*pro.CancelOrderResponse, error) {
+	return &pro.CancelOrderResponse{Id: request.Id, Status: "canceled"}, nil
+}
+
+func (r *cancelOrderRepo) GetOrder(c context.Context, request *pro.GetOrderRequest) (*pro.GetOrderResponse, error) {
+	return &pro.GetOrderResponse{Order: &pro.Order{Id: request.Id, Items: []*pro.OrderItem{
+		{ProductId: "product-1"}, {ProductId: "product-2"}, {ProductId: "product-1"},
+	}}}, nil
+}
+
+func (r *cancelOrderRepo) WishlistAlerts(c context.Context, productId string) ([]*models.WishlistAlert, error) {
+	r.checked = append(r.checked, productId)
+	return nil, nil
+}
+
+func TestCancelOrderChecksWishlists(t *testing.T) {
+	repo := &cancelOrderRepo{}
+	p := newTestService(repo, nil)
+
+	res, err := p.CancelOrder(context.Background(), &pro.CancelOrderRequest{Id: "order-1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "canceled", res.Status)
+	assert.Equal(t, []string{"product-1", "product-2"}, repo.checked)
+}
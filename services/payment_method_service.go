@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	pro "product-service/genproto/product_service"
+	"product-service/models"
+	"product-service/redact"
+	"strings"
+)
+
+// hasRawCardData reports whether a payment request carries card data instead
+// of a token. Such requests are rejected before anything is stored or logged.
+func hasRawCardData(request *pro.PayOrderRequest) bool {
+	if request.CardNumber != "" || request.ExpiryDate != "" || request.Cvv != "" {
+		return true
+	}
+	return redact.ContainsCardNumber(request.PaymentMethodToken)
+}
+
+// paymentSource resolves the provider token to charge: either the token sent
+// with the request or the token of one of the user's saved payment methods.
+func (p *productService) paymentSource(c context.Context, request *pro.PayOrderRequest) (string, error) {
+	switch {
+	case request.PaymentMethodToken != "" && request.PaymentMethodId != "":
+		return "", fmt.Errorf("send either payment_method_token or payment_method_id, not both")
+	case request.PaymentMethodToken != "":
+		return request.PaymentMethodToken, nil
+	case request.PaymentMethodId != "":
+		if request.UserId == "" {
+			return "", fmt.Errorf("user_id is required to pay with a saved payment method")
+		}
+		return p.productRepo.GetPaymentMethodToken(c, request.PaymentMethodId, request.UserId)
+	default:
+		return "", fmt.Errorf("payment_method_token or payment_method_id is required")
+	}
+}
+
+func (p *productService) AddPaymentMethod(c context.Context, request *pro.AddPaymentMethodRequest) (*pro.AddPaymentMethodResponse, error) {
+	if !p.isValidUser(request.UserId) {
+		p.log.Errorf("user is not valid")
+		return nil, fmt.Errorf("user is not valid")
+	}
+	request.Token = strings.TrimSpace(request.Token)
+	if request.Token == "" || redact.ContainsCardNumber(request.Token) {
+		return nil, fmt.Errorf("a payment method token is required")
+	}
+
+	details, err := p.paymentProvider.DescribePaymentMethod(c, request.Token)
+	if err != nil {
+		p.log.Errorf("failed to describe payment method: %v", err)
+		return nil, err
+	}
+
+	res, err := p.productRepo.AddPaymentMethod(c, &models.PaymentMethod{
+		UserId:    request.UserId,
+		Provider:  p.paymentProvider.Name(),
+		Token:     request.Token,
+		Brand:     details.Brand,
+		Last4:     details.Last4,
+		ExpMonth:  details.ExpMonth,
+		ExpYear:   details.ExpYear,
+		IsDefault: request.IsDefault,
+	})
+	if err != nil {
+		p.log.Errorf("failed to add payment method: %v", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+func (p *productService) GetPaymentMethods(c context.Context, request *pro.GetPaymentMethodsRequest) (*pro.GetPaymentMethodsResponse, error) {
+	res, err := p.productRepo.GetPaymentMethods(c, request)
+	if err != nil {
+		p.log.Errorf("failed to get payment methods: %v", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+func (p *productService) DeletePaymentMethod(c context.Context, request *pro.DeletePaymentMethodRequest) (*pro.DeletePaymentMethodResponse, error) {
+	res, err := p.productRepo.DeletePaymentMethod(c, request)
+	if err != nil {
+		p.log.Errorf("failed to delete payment method: %v", err)
+		return nil, err
+	}
+	res.Message = "payment method deleted successfully"
+	return res, nil
+}
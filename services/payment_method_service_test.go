@@ -0,0 +1,17 @@
+package services
+
+import (
+	"testing"
+
+	pro "product-service/genproto/product_service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHasRawCardData(t *testing.T) {
+	assert.False(t, hasRawCardData(&pro.PayOrderRequest{PaymentMethodToken: "tok_visa_4242"}))
+	assert.False(t, hasRawCardData(&pro.PayOrderRequest{PaymentMethodId: "pm-1"}))
+	assert.True(t, hasRawCardData(&pro.PayOrderRequest{CardNumber: "4242424242424242"}))
+	assert.True(t, hasRawCardData(&pro.PayOrderRequest{PaymentMethodToken: "tok_visa_4242", Cvv: "123"}))
+	assert.True(t, hasRawCardData(&pro.PayOrderRequest{PaymentMethodToken: "4242 4242 4242 4242"}))
+}
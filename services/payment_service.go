@@ -0,0 +1,255 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"product-service/gateway"
+	auth "product-service/genproto/authentication_service"
+	pro "product-service/genproto/product_service"
+	"product-service/models"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func providerResponse(provider gateway.PaymentProvider, res *gateway.Result) *models.ProviderResponse {
+	return &models.ProviderResponse{
+		Provider:      provider.Name(),
+		Operation:     res.Operation,
+		Approved:      res.Approved,
+		TransactionId: res.TransactionId,
+		Amount:        res.Amount,
+		DeclineCode:   res.DeclineCode,
+		Response:      res.Raw,
+	}
+}
+
+func payOrderResponse(payment *pro.Payment) *pro.PayOrderResponse {
+	return &pro.PayOrderResponse{
+		Id:            payment.Id,
+		OrderId:       payment.OrderId,
+		Amount:        payment.Amount,
+		Status:        payment.Status,
+		TransactionId: payment.TransactionId,
+		PaymentMethod: payment.PaymentMethod,
+		CreatedAt:     payment.CreatedAt,
+	}
+}
+
+// failPayment moves a payment to failed, logging rather than returning
+// bookkeeping errors so the original failure reaches the caller.
+func (p *productService) failPayment(c context.Context, transition *models.PaymentTransition) {
+	transition.To = models.PaymentFailed
+	if _, err := p.productRepo.TransitionPayment(c, transition); err != nil {
+		p.log.Errorf("failed to mark payment %s as failed: %v", transition.PaymentId, err)
+	}
+}
+
+// chargeOrder runs a payment through the provider: the payment is created
+// pending, authorized and then captured, with every step recorded as a status
+// transition together with the provider response. A declined payment ends up
+// failed (or voided, if the authorization succeeded) and is reported as an
+// error.
+func (p *productService) chargeOrder(c context.Context, request *pro.PayOrderRequest) (*pro.PayOrderResponse, error) {
+	source, err := p.paymentSource(c, request)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := p.paymentProvider
+	payment, err := p.productRepo.CreatePayment(c, &models.Payment{
+		OrderId:       request.OrderId,
+		Amount:        models.MoneyFromFloat(request.Amount),
+		PaymentMethod: request.PaymentMethod,
+		Provider:      provider.Name(),
+	}, time.Now().Add(-p.config.PaymentConfig.PendingTimeout))
+	if err != nil {
+		return nil, err
+	}
+	amount := models.MoneyFromFloat(payment.Amount)
+
+	auth, err := provider.Authorize(c, &gateway.AuthorizeRequest{
+		OrderId:       request.OrderId,
+		Amount:        amount,
+		Currency:      payment.Currency,
+		PaymentMethod: request.PaymentMethod,
+		Source:        source,
+	})
+	if err != nil {
+		p.failPayment(c, &models.PaymentTransition{PaymentId: payment.Id, Reason: "gateway error"})
+		return nil, fmt.Errorf("payment provider error: %v", err)
+	}
+	if !auth.Approved {
+		p.failPayment(c, &models.PaymentTransition{
+			PaymentId: payment.Id,
+			Reason:    auth.DeclineCode,
+			Responses: []*models.ProviderResponse{providerResponse(provider, auth)},
+		})
+		return nil, fmt.Errorf("payment declined: %s", auth.DeclineCode)
+	}
+
+	payment, err = p.productRepo.TransitionPayment(c, &models.PaymentTransition{
+		PaymentId:     payment.Id,
+		To:            models.PaymentAuthorized,
+		TransactionId: auth.TransactionId,
+		Responses:     []*models.ProviderResponse{providerResponse(provider, auth)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	capture, err := provider.Capture(c, auth.TransactionId, amount)
+	if err == nil && capture.Approved {
+		captured, err := p.productRepo.TransitionPayment(c, &models.PaymentTransition{
+			PaymentId:   payment.Id,
+			To:          models.PaymentCaptured,
+			Responses:   []*models.ProviderResponse{providerResponse(provider, capture)},
+			OrderStatus: "processing",
+		})
+		if err != nil {
+			// The money was taken but the order could not be marked paid,
+			// so give it back.
+			p.log.Errorf("failed to record capture of payment %s: %v", payment.Id, err)
+			responses := []*models.ProviderResponse{providerResponse(provider, capture)}
+			if refund, err := provider.Refund(c, auth.TransactionId, amount); err != nil {
+				p.log.Errorf("failed to refund payment %s: %v", payment.Id, err)
+			} else {
+				responses = append(responses, providerResponse(provider, refund))
+			}
+			p.failPayment(c, &models.PaymentTransition{PaymentId: payment.Id, Reason: "capture not recorded", Responses: responses})
+			return nil, err
+		}
+		return payOrderResponse(captured), nil
+	}
+
+	// The capture did not go through: release the authorization.
+	reason := "capture failed"
+	var responses []*models.ProviderResponse
+	if err != nil {
+		p.log.Errorf("failed to capture payment %s: %v", payment.Id, err)
+	} else {
+		reason = capture.DeclineCode
+		responses = append(responses, providerResponse(provider, capture))
+	}
+	void, err := provider.Void(c, auth.TransactionId)
+	if err != nil || !void.Approved {
+		if void != nil {
+			responses = append(responses, providerResponse(provider, void))
+		}
+		p.failPayment(c, &models.PaymentTransition{PaymentId: payment.Id, Reason: reason, Responses: responses})
+		return nil, fmt.Errorf("payment declined: %s", reason)
+	}
+	responses = append(responses, providerResponse(provider, void))
+	if _, err := p.productRepo.TransitionPayment(c, &models.PaymentTransition{
+		PaymentId: payment.Id,
+		To:        models.PaymentVoided,
+		Reason:    reason,
+		Responses: responses,
+	}); err != nil {
+		p.log.Errorf("failed to mark payment %s as voided: %v", payment.Id, err)
+	}
+	return nil, fmt.Errorf("payment declined: %s", reason)
+}
+
+// RefundPayment refunds a payment on behalf of an admin, or of the artisan
+// whose products are refunded.
+func (p *productService) RefundPayment(c context.Context, request *pro.RefundPaymentRequest) (*pro.RefundPaymentResponse, error) {
+	artisanId, err := p.refundingArtisan(c, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+	key := idempotencyKey(c, request.IdempotencyKey)
+	res, err := p.idempotent(c, "refund_payment", key, request, &pro.RefundPaymentResponse{}, func() (proto.Message, error) {
+		return p.refundPayment(c, request, artisanId)
+	})
+	if err != nil {
+		p.log.Errorf("failed to refund payment: %v", err)
+		return nil, err
+	}
+	return res.(*pro.RefundPaymentResponse), nil
+}
+
+// refundingArtisan checks that the user may refund payments. It returns the
+// user id for an artisan, who may only refund their own products, and an
+// empty id for an admin.
+func (p *productService) refundingArtisan(c context.Context, userId string) (string, error) {
+	if userId == "" {
+		return "", fmt.Errorf("user_id is required")
+	}
+	res, err := p.authService.GetUserInfo(c, &auth.GetUserInfoRequest{Id: userId})
+	if err != nil {
+		p.log.Errorf("user is not found: %v", err)
+		return "", fmt.Errorf("user is not found: %v", err)
+	}
+	switch {
+	case res.User != nil && res.User.UserType == "admin":
+		return "", nil
+	case res.User != nil && res.User.UserType == "artisan":
+		return userId, nil
+	}
+	p.log.Errorf("user %s may not refund payments", userId)
+	return "", fmt.Errorf("only the artisan of the order or an admin can refund payments")
+}
+
+// refundPayment gives back part or all of a captured payment. Exactly one of
+// amount, items or return_id says what is refunded.
+func (p *productService) refundPayment(c context.Context, request *pro.RefundPaymentRequest, artisanId string) (*pro.RefundPaymentResponse, error) {
+	if request.PaymentId == "" {
+		return nil, fmt.Errorf("payment_id is required")
+	}
+	if request.Amount < 0 {
+		return nil, fmt.Errorf("invalid refund amount")
+	}
+	kinds := 0
+	for _, set := range []bool{request.Amount > 0, len(request.Items) > 0, request.ReturnId != ""} {
+		if set {
+			kinds++
+		}
+	}
+	if kinds != 1 {
+		return nil, fmt.Errorf("exactly one of amount, items or return_id is required")
+	}
+
+	refund := &models.Refund{
+		PaymentId: request.PaymentId,
+		OrderId:   request.OrderId,
+		ReturnId:  request.ReturnId,
+		Amount:    models.MoneyFromFloat(request.Amount),
+		Reason:    request.Reason,
+		ArtisanId: artisanId,
+	}
+	seen := make(map[string]bool)
+	for _, item := range request.Items {
+		if item.Quantity <= 0 {
+			return nil, fmt.Errorf("invalid quantity for order item %s", item.OrderItemId)
+		}
+		if seen[item.OrderItemId] {
+			return nil, fmt.Errorf("order item %s is listed twice", item.OrderItemId)
+		}
+		seen[item.OrderItemId] = true
+		refund.Items = append(refund.Items, &models.RefundItem{OrderItemId: item.OrderItemId, Quantity: item.Quantity})
+	}
+
+	created, payment, err := p.productRepo.CreateRefund(c, refund)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := p.paymentProvider
+	result, err := provider.Refund(c, payment.TransactionId, models.MoneyFromFloat(created.Amount))
+	if err != nil {
+		if _, err := p.productRepo.SettleRefund(c, created.Id, nil); err != nil {
+			p.log.Errorf("failed to release refund %s: %v", created.Id, err)
+		}
+		return nil, fmt.Errorf("payment provider error: %v", err)
+	}
+
+	res, err := p.productRepo.SettleRefund(c, created.Id, providerResponse(provider, result))
+	if err != nil {
+		return nil, err
+	}
+	if !result.Approved {
+		return nil, fmt.Errorf("refund declined: %s", result.DeclineCode)
+	}
+	return res, nil
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	pro "product-service/genproto/product_service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefundPaymentRejectsCaller(t *testing.T) {
+	p := newTestService(nil, map[string]string{"buyer-1": "customer"})
+	request := &pro.RefundPaymentRequest{PaymentId: "pay-1", Amount: 10}
+
+	_, err := p.RefundPayment(context.Background(), request)
+	assert.EqualError(t, err, "user_id is required")
+
+	request.UserId = "buyer-1"
+	_, err = p.RefundPayment(context.Background(), request)
+	assert.EqualError(t, err, "only the artisan of the order or an admin can refund payments")
+
+	request.UserId = "stranger"
+	_, err = p.RefundPayment(context.Background(), request)
+	assert.Error(t, err)
+}
+
+func TestRefundingArtisan(t *testing.T) {
+	p := newTestService(nil, map[string]string{"admin-1": "admin", "artisan-1": "artisan"})
+
+	artisanId, err := p.refundingArtisan(context.Background(), "admin-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "", artisanId)
+
+	artisanId, err = p.refundingArtisan(context.Background(), "artisan-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "artisan-1", artisanId)
+}
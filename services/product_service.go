@@ -4,11 +4,19 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"product-service/blob"
+	"product-service/carrier"
+	"product-service/configs"
+	"product-service/currency"
+	"product-service/gateway"
 	auth "product-service/genproto/authentication_service"
 	pro "product-service/genproto/product_service"
+	"product-service/notify"
+	"product-service/shipping"
 	"product-service/storage/postgres"
 
 	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/proto"
 )
 
 type ProductService interface {
@@ -20,6 +28,13 @@ type ProductService interface {
 	SearchProducts(c context.Context, request *pro.SearchProductsRequest) (*pro.SearchProductsResponse, error)
 	AddRating(c context.Context, rating *pro.AddRatingRequest) (*pro.AddRatingResponse, error)
 	GetRatings(c context.Context, request *pro.GetRatingsRequest) (*pro.GetRatingsResponse, error)
+	ReportRating(c context.Context, request *pro.ReportRatingRequest) (*pro.ReportRatingResponse, error)
+	GetModerationQueue(c context.Context, request *pro.GetModerationQueueRequest) (*pro.GetModerationQueueResponse, error)
+	ModerateRating(c context.Context, request *pro.ModerateRatingRequest) (*pro.ModerateRatingResponse, error)
+	ReplyToRating(c context.Context, request *pro.ReplyToRatingRequest) (*pro.ReplyToRatingResponse, error)
+	VoteRating(c context.Context, request *pro.VoteRatingRequest) (*pro.VoteRatingResponse, error)
+	AddRatingPhoto(c context.Context, request *pro.AddRatingPhotoRequest) (*pro.AddRatingPhotoResponse, error)
+	DeleteRatingPhoto(c context.Context, request *pro.DeleteRatingPhotoRequest) (*pro.DeleteRatingPhotoResponse, error)
 	PlaceOrder(c context.Context, order *pro.PlaceOrderRequest) (*pro.PlaceOrderResponse, error)
 	CancelOrder(c context.Context, request *pro.CancelOrderRequest) (*pro.CancelOrderResponse, error)
 	UpdateOrderStatus(c context.Context, request *pro.UpdateOrderStatusRequest) (*pro.UpdateOrderStatusResponse, error)
@@ -28,23 +43,68 @@ type ProductService interface {
 	PayOrder(c context.Context, request *pro.PayOrderRequest) (*pro.PayOrderResponse, error)
 	CheckPaymentStatus(c context.Context, request *pro.CheckPaymentStatusRequest) (*pro.CheckPaymentStatusResponse, error)
 	UpdateShippingInfo(c context.Context, request *pro.UpdateShippingInfoRequest) (*pro.UpdateShippingInfoResponse, error)
+	AddTrackingEvent(c context.Context, request *pro.AddTrackingEventRequest) (*pro.AddTrackingEventResponse, error)
+	GetShipments(c context.Context, request *pro.GetShipmentsRequest) (*pro.GetShipmentsResponse, error)
+	CreateShippingLabel(c context.Context, request *pro.CreateShippingLabelRequest) (*pro.CreateShippingLabelResponse, error)
+	GetShippingLabel(c context.Context, request *pro.GetShippingLabelRequest) (*pro.GetShippingLabelResponse, error)
+	RefreshTracking(c context.Context, request *pro.RefreshTrackingRequest) (*pro.RefreshTrackingResponse, error)
 	AddArtisanCategory(c context.Context, request *pro.AddArtisanCategoryRequest) (*pro.AddArtisanCategoryResponse, error)
 	AddProductCategory(c context.Context, request *pro.AddProductCategoryRequest) (*pro.AddProductCategoryResponse, error)
 	GetStatistics(c context.Context, request *pro.GetStatisticsRequest) (*pro.GetStatisticsResponse, error)
 	GetUserActivity(c context.Context, request *pro.GetUserActivityRequest) (*pro.GetUserActivityResponse, error)
 	GetRecommendations(c context.Context, request *pro.GetRecommendationsRequest) (*pro.GetRecommendationsResponse, error)
 	GetArtisanRankings(c context.Context, request *pro.GetArtisanRankingsRequest) (*pro.GetArtisanRankingsResponse, error)
+	ListArtisanOrders(c context.Context, request *pro.ListArtisanOrdersRequest) (*pro.ListArtisanOrdersResponse, error)
+	ListMyOrders(c context.Context, request *pro.ListMyOrdersRequest) (*pro.ListMyOrdersResponse, error)
+	AddAddress(c context.Context, request *pro.AddAddressRequest) (*pro.AddAddressResponse, error)
+	GetAddresses(c context.Context, request *pro.GetAddressesRequest) (*pro.GetAddressesResponse, error)
+	UpdateAddress(c context.Context, request *pro.UpdateAddressRequest) (*pro.UpdateAddressResponse, error)
+	DeleteAddress(c context.Context, request *pro.DeleteAddressRequest) (*pro.DeleteAddressResponse, error)
+	RequestReturn(c context.Context, request *pro.RequestReturnRequest) (*pro.RequestReturnResponse, error)
+	ApproveReturn(c context.Context, request *pro.ResolveReturnRequest) (*pro.ResolveReturnResponse, error)
+	RejectReturn(c context.Context, request *pro.ResolveReturnRequest) (*pro.ResolveReturnResponse, error)
+	GetReturns(c context.Context, request *pro.GetReturnsRequest) (*pro.GetReturnsResponse, error)
+	RefundPayment(c context.Context, request *pro.RefundPaymentRequest) (*pro.RefundPaymentResponse, error)
+	AddPaymentMethod(c context.Context, request *pro.AddPaymentMethodRequest) (*pro.AddPaymentMethodResponse, error)
+	GetPaymentMethods(c context.Context, request *pro.GetPaymentMethodsRequest) (*pro.GetPaymentMethodsResponse, error)
+	DeletePaymentMethod(c context.Context, request *pro.DeletePaymentMethodRequest) (*pro.DeletePaymentMethodResponse, error)
+	HandlePaymentEvent(c context.Context, event *gateway.Event) (string, error)
+	AddTaxRule(c context.Context, request *pro.AddTaxRuleRequest) (*pro.AddTaxRuleResponse, error)
+	GetTaxRules(c context.Context, request *pro.GetTaxRulesRequest) (*pro.GetTaxRulesResponse, error)
+	DeleteTaxRule(c context.Context, request *pro.DeleteTaxRuleRequest) (*pro.DeleteTaxRuleResponse, error)
+	AddPromotion(c context.Context, request *pro.AddPromotionRequest) (*pro.AddPromotionResponse, error)
+	GetPromotions(c context.Context, request *pro.GetPromotionsRequest) (*pro.GetPromotionsResponse, error)
+	DeletePromotion(c context.Context, request *pro.DeletePromotionRequest) (*pro.DeletePromotionResponse, error)
+	QuoteShipping(c context.Context, request *pro.QuoteShippingRequest) (*pro.QuoteShippingResponse, error)
+	AddCartItem(c context.Context, request *pro.AddCartItemRequest) (*pro.AddCartItemResponse, error)
+	UpdateCartItem(c context.Context, request *pro.UpdateCartItemRequest) (*pro.UpdateCartItemResponse, error)
+	RemoveCartItem(c context.Context, request *pro.RemoveCartItemRequest) (*pro.RemoveCartItemResponse, error)
+	GetCart(c context.Context, request *pro.GetCartRequest) (*pro.GetCartResponse, error)
+	CheckoutCart(c context.Context, request *pro.CheckoutCartRequest) (*pro.PlaceOrderResponse, error)
+	CreateWishlist(c context.Context, request *pro.CreateWishlistRequest) (*pro.CreateWishlistResponse, error)
+	GetWishlists(c context.Context, request *pro.GetWishlistsRequest) (*pro.GetWishlistsResponse, error)
+	GetWishlist(c context.Context, request *pro.GetWishlistRequest) (*pro.GetWishlistResponse, error)
+	DeleteWishlist(c context.Context, request *pro.DeleteWishlistRequest) (*pro.DeleteWishlistResponse, error)
+	AddWishlistItem(c context.Context, request *pro.AddWishlistItemRequest) (*pro.AddWishlistItemResponse, error)
+	RemoveWishlistItem(c context.Context, request *pro.RemoveWishlistItemRequest) (*pro.RemoveWishlistItemResponse, error)
 }
 
 type productService struct {
 	authService auth.AuthenticationServiceClient
 	productRepo postgres.ProductRepo
 	pro.UnimplementedProductServiceServer
-	log *logrus.Logger
+	log             *logrus.Logger
+	config          *configs.Config
+	paymentProvider gateway.PaymentProvider
+	rateSource      currency.RateSource
+	shippingRates   *shipping.Table
+	carriers        *carrier.Registry
+	notifier        notify.Notifier
+	blobs           blob.Store
 }
 
-func NewProductService(authService auth.AuthenticationServiceClient, productRepo postgres.ProductRepo, log *logrus.Logger) ProductService {
-	return &productService{authService: authService, productRepo: productRepo, log: log}
+func NewProductService(authService auth.AuthenticationServiceClient, productRepo postgres.ProductRepo, log *logrus.Logger, config *configs.Config, paymentProvider gateway.PaymentProvider, rateSource currency.RateSource, shippingRates *shipping.Table, carriers *carrier.Registry, notifier notify.Notifier, blobs blob.Store) ProductService {
+	return &productService{authService: authService, productRepo: productRepo, log: log, config: config, paymentProvider: paymentProvider, rateSource: rateSource, shippingRates: shippingRates, carriers: carriers, notifier: notifier, blobs: blobs}
 }
 
 func (p *productService) AddProduct(c context.Context, product *pro.AddProductRequest) (*pro.AddProductResponse, error) {
@@ -58,6 +118,12 @@ func (p *productService) AddProduct(c context.Context, product *pro.AddProductRe
 		p.log.Errorf("user is not an artistan: %v", err)
 		return nil, fmt.Errorf("user is not an artistant")
 	}
+	if product.Currency, err = p.resolveCurrency(product.Currency); err != nil {
+		return nil, err
+	}
+	if product.WeightGrams < 0 {
+		return nil, fmt.Errorf("weight must not be negative")
+	}
 	return p.productRepo.AddProduct(c, product)
 }
 
@@ -74,7 +140,21 @@ func (p *productService) EditProduct(c context.Context, product *pro.EditProduct
 		p.log.Errorf("user is not an artistant")
 		return nil, fmt.Errorf("user is not an artistant")
 	}
-	return p.productRepo.EditProduct(c, product)
+	// An edit without a currency keeps the product's current one.
+	if product.Currency != "" {
+		if product.Currency, err = currency.Normalize(product.Currency); err != nil {
+			return nil, err
+		}
+	}
+	if product.WeightGrams < 0 {
+		return nil, fmt.Errorf("weight must not be negative")
+	}
+	edited, err := p.productRepo.EditProduct(c, product)
+	if err != nil {
+		return nil, err
+	}
+	p.notifyWishlists(c, product.Id)
+	return edited, nil
 }
 
 func (p *productService) DeleteProduct(c context.Context, request *pro.DeleteProductRequest) (*pro.DeleteProductResponse, error) {
@@ -142,40 +222,47 @@ func (p *productService) SearchProducts(c context.Context, request *pro.SearchPr
 	return res, nil
 }
 
-func (p *productService) AddRating(c context.Context, rating *pro.AddRatingRequest) (*pro.AddRatingResponse, error) {
-	_, err := p.authService.GetUserInfo(c, &auth.GetUserInfoRequest{Id: rating.UserId})
-	if err != nil {
-		p.log.Errorf("user is not found: %v", err)
-		return nil, fmt.Errorf("user is not found: %v", err)
-	}
-
-	return p.productRepo.AddRating(c, rating)
-}
-
-func (p *productService) GetRatings(c context.Context, request *pro.GetRatingsRequest) (*pro.GetRatingsResponse, error) {
-	res, err := p.productRepo.GetRatings(c, request)
-	if err != nil {
-		p.log.Errorf("failed to get ratings: %v", err)
-		return nil, err
-	}
-	return res, nil
-}
-
 func (p *productService) PlaceOrder(c context.Context, order *pro.PlaceOrderRequest) (*pro.PlaceOrderResponse, error) {
 	if !p.isValidUser(order.UserId) {
 		p.log.Errorf("user is not valid")
 		return nil, fmt.Errorf("user is not valid")
 	}
 
-	res, err := p.productRepo.PlaceOrder(c, order)
+	key := idempotencyKey(c, order.IdempotencyKey)
+	res, err := p.idempotent(c, "place_order", key, order, &pro.PlaceOrderResponse{}, func() (proto.Message, error) {
+		return p.placeOrder(c, order, false)
+	})
 	if err != nil {
 		p.log.Errorf("failed to place order: %v", err)
 		return nil, err
 	}
 
+	return res.(*pro.PlaceOrderResponse), nil
+}
+
+// placeOrder resolves the shipping address, prices the order and writes it.
+// fromCart also takes the ordered products out of the buyer's cart.
+func (p *productService) placeOrder(c context.Context, order *pro.PlaceOrderRequest, fromCart bool) (*pro.PlaceOrderResponse, error) {
+	if err := p.resolveShippingAddress(c, order); err != nil {
+		return nil, err
+	}
+	checkout, err := p.prepareCheckout(c, order)
+	if err != nil {
+		return nil, err
+	}
+	checkout.FromCart = fromCart
+	res, err := p.productRepo.PlaceOrder(c, order, checkout)
+	if err != nil {
+		return nil, err
+	}
+	// Record what the order took, so a product it sold out is alerted as
+	// back in stock when it returns.
+	p.notifyWishlistsOf(c, itemProductIds(order.Items))
 	return res, nil
 }
 
+// CancelOrder cancels a pending order, putting back its stock, and alerts
+// the wishlists of products that are back in stock.
 func (p *productService) CancelOrder(c context.Context, request *pro.CancelOrderRequest) (*pro.CancelOrderResponse, error) {
 
 	res, err := p.productRepo.CancelOrder(c, request)
@@ -184,10 +271,25 @@ func (p *productService) CancelOrder(c context.Context, request *pro.CancelOrder
 		return nil, err
 	}
 
+	order, err := p.productRepo.GetOrder(c, &pro.GetOrderRequest{Id: request.Id})
+	if err != nil {
+		p.log.Errorf("failed to get canceled order %s: %v", request.Id, err)
+		return res, nil
+	}
+	productIds := make([]string, 0, len(order.Order.Items))
+	for _, item := range order.Order.Items {
+		productIds = append(productIds, item.ProductId)
+	}
+	p.notifyWishlistsOf(c, productIds)
 	return res, nil
 }
 
+// UpdateOrderStatus moves an order along. Orders are canceled through
+// CancelOrder, which also puts back the stock and promotion uses they took.
 func (p *productService) UpdateOrderStatus(c context.Context, request *pro.UpdateOrderStatusRequest) (*pro.UpdateOrderStatusResponse, error) {
+	if request.Status == "canceled" {
+		return nil, fmt.Errorf("use CancelOrder to cancel an order")
+	}
 	res, err := p.productRepo.UpdateOrderStatus(c, request)
 	if err != nil {
 		p.log.Errorf("failed to update order status: %v", err)
@@ -205,7 +307,12 @@ func (p *productService) GetOrders(c context.Context, request *pro.GetOrdersRequ
 	return res, nil
 }
 
+// GetOrder returns one of the user's orders. Orders of other users are not
+// found.
 func (p *productService) GetOrder(c context.Context, request *pro.GetOrderRequest) (*pro.GetOrderResponse, error) {
+	if request.UserId == "" {
+		return nil, fmt.Errorf("user_id is required")
+	}
 	res, err := p.productRepo.GetOrder(c, request)
 	if err != nil {
 		p.log.Errorf("failed to get order: %v", err)
@@ -216,12 +323,19 @@ func (p *productService) GetOrder(c context.Context, request *pro.GetOrderReques
 }
 
 func (p *productService) PayOrder(c context.Context, request *pro.PayOrderRequest) (*pro.PayOrderResponse, error) {
-	res, err := p.productRepo.PayOrder(c, request)
+	if hasRawCardData(request) {
+		p.log.Errorf("rejected payment with raw card data for order %s", request.OrderId)
+		return nil, fmt.Errorf("raw card data is not accepted, pay with a payment method token")
+	}
+	key := idempotencyKey(c, request.IdempotencyKey)
+	res, err := p.idempotent(c, "pay_order", key, request, &pro.PayOrderResponse{}, func() (proto.Message, error) {
+		return p.chargeOrder(c, request)
+	})
 	if err != nil {
 		p.log.Errorf("failed to pay order: %v", err)
 		return nil, err
 	}
-	return res, nil
+	return res.(*pro.PayOrderResponse), nil
 }
 
 func (p *productService) CheckPaymentStatus(c context.Context, request *pro.CheckPaymentStatusRequest) (*pro.CheckPaymentStatusResponse, error) {
@@ -233,15 +347,6 @@ func (p *productService) CheckPaymentStatus(c context.Context, request *pro.Chec
 	return res, nil
 }
 
-func (p *productService) UpdateShippingInfo(c context.Context, request *pro.UpdateShippingInfoRequest) (*pro.UpdateShippingInfoResponse, error) {
-	res, err := p.productRepo.UpdateShippingInfo(c, request)
-	if err != nil {
-		p.log.Errorf("failed to update shipping info: %v", err)
-		return nil, err
-	}
-	return res, nil
-}
-
 func (p *productService) GetStatistics(c context.Context, request *pro.GetStatisticsRequest) (*pro.GetStatisticsResponse, error) {
 	return p.productRepo.GetStatistics(c, request)
 }
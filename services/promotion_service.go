@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	pro "product-service/genproto/product_service"
+	"product-service/models"
+	"product-service/promotion"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var couponCode = regexp.MustCompile(`^[A-Z0-9_-]{3,64}$`)
+
+// normalizeCouponCode upper-cases a coupon code so codes match regardless of
+// how the buyer typed them.
+func normalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// parsePromotionTime reads an optional RFC 3339 timestamp.
+func parsePromotionTime(name, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be an RFC 3339 timestamp", name)
+	}
+	return &t, nil
+}
+
+// promotionFromRequest validates a new promotion.
+func (p *productService) promotionFromRequest(request *pro.AddPromotionRequest) (*models.Promotion, error) {
+	promo := &models.Promotion{
+		Code:           normalizeCouponCode(request.Code),
+		Name:           strings.TrimSpace(request.Name),
+		Kind:           request.Kind,
+		MinOrderAmount: models.MoneyFromFloat(request.MinOrderAmount),
+		MaxUses:        request.MaxUses,
+		MaxUsesPerUser: request.MaxUsesPerUser,
+		ArtisanId:      request.ArtisanId,
+		CategoryId:     request.CategoryId,
+	}
+	if promo.Code != "" && !couponCode.MatchString(promo.Code) {
+		return nil, fmt.Errorf("coupon code must be 3 to 64 letters, digits, dashes or underscores")
+	}
+	if promo.Name == "" {
+		return nil, fmt.Errorf("promotion name is required")
+	}
+
+	switch promo.Kind {
+	case models.PromotionPercentage:
+		promo.PercentOff = strings.TrimSpace(request.PercentOff)
+		if _, err := promotion.ParsePercent(promo.PercentOff); err != nil {
+			return nil, err
+		}
+	case models.PromotionFixed:
+		promo.AmountOff = models.MoneyFromFloat(request.AmountOff)
+		if promo.AmountOff <= 0 {
+			return nil, fmt.Errorf("amount off must be positive")
+		}
+	default:
+		return nil, fmt.Errorf("promotion kind must be %s or %s", models.PromotionPercentage, models.PromotionFixed)
+	}
+
+	code, err := p.resolveCurrency(request.Currency)
+	if err != nil {
+		return nil, err
+	}
+	promo.Currency = code
+	if promo.MinOrderAmount < 0 {
+		return nil, fmt.Errorf("minimum order amount must not be negative")
+	}
+	if promo.MaxUses < 0 || promo.MaxUsesPerUser < 0 {
+		return nil, fmt.Errorf("usage limits must not be negative")
+	}
+
+	if promo.StartsAt, err = parsePromotionTime("starts_at", request.StartsAt); err != nil {
+		return nil, err
+	}
+	if promo.EndsAt, err = parsePromotionTime("ends_at", request.EndsAt); err != nil {
+		return nil, err
+	}
+	if promo.StartsAt != nil && promo.EndsAt != nil && !promo.EndsAt.After(*promo.StartsAt) {
+		return nil, fmt.Errorf("ends_at must be after starts_at")
+	}
+	return promo, nil
+}
+
+// convertPromotion converts the amounts of a promotion into currency.
+func convertPromotion(promo *models.Promotion, currency string, rates map[string]*models.ExchangeRate) error {
+	if promo.Currency == currency {
+		return nil
+	}
+	rate, ok := rates[promo.Currency]
+	if !ok {
+		return fmt.Errorf("no exchange rate from %s to %s", promo.Currency, currency)
+	}
+	amountOff, err := promo.AmountOff.Convert(rate.Rate)
+	if err != nil {
+		return err
+	}
+	minOrderAmount, err := promo.MinOrderAmount.Convert(rate.Rate)
+	if err != nil {
+		return err
+	}
+	promo.AmountOff = amountOff
+	promo.MinOrderAmount = minOrderAmount
+	promo.Currency = currency
+	return nil
+}
+
+// checkoutPromotions loads the running automatic promotions and the coupon
+// of an order. A coupon code that matches no running coupon is an error.
+func (p *productService) checkoutPromotions(c context.Context, order *pro.PlaceOrderRequest) ([]*models.Promotion, error) {
+	order.CouponCode = normalizeCouponCode(order.CouponCode)
+	promos, err := p.productRepo.GetCheckoutPromotions(c, order.CouponCode, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if order.CouponCode == "" {
+		return promos, nil
+	}
+	for _, promo := range promos {
+		if promo.Code == order.CouponCode {
+			return promos, nil
+		}
+	}
+	return nil, fmt.Errorf("coupon code %s is not valid", order.CouponCode)
+}
+
+func (p *productService) AddPromotion(c context.Context, request *pro.AddPromotionRequest) (*pro.AddPromotionResponse, error) {
+	if err := p.requireAdmin(c, request.UserId); err != nil {
+		return nil, err
+	}
+	promo, err := p.promotionFromRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := p.productRepo.AddPromotion(c, promo)
+	if err != nil {
+		p.log.Errorf("failed to add promotion: %v", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+func (p *productService) GetPromotions(c context.Context, request *pro.GetPromotionsRequest) (*pro.GetPromotionsResponse, error) {
+	res, err := p.productRepo.GetPromotions(c, request, time.Now())
+	if err != nil {
+		p.log.Errorf("failed to get promotions: %v", err)
+		return nil, err
+	}
+	// Coupon codes are secret; only admins get to see them.
+	if !p.isAdmin(c, request.UserId) {
+		for _, promo := range res.Promotions {
+			promo.Code = ""
+		}
+	}
+	return res, nil
+}
+
+func (p *productService) DeletePromotion(c context.Context, request *pro.DeletePromotionRequest) (*pro.DeletePromotionResponse, error) {
+	if err := p.requireAdmin(c, request.UserId); err != nil {
+		return nil, err
+	}
+	res, err := p.productRepo.DeletePromotion(c, request)
+	if err != nil {
+		p.log.Errorf("failed to delete promotion: %v", err)
+		return nil, err
+	}
+	res.Message = "promotion deleted successfully"
+	return res, nil
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pro "product-service/genproto/product_service"
+	"product-service/models"
+	"product-service/storage/postgres"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertPromotion(t *testing.T) {
+	rates := map[string]*models.ExchangeRate{"USD": {Base: "USD", Quote: "EUR", Rate: "0.9"}}
+
+	promo := &models.Promotion{Kind: models.PromotionFixed, AmountOff: 1000, MinOrderAmount: 5000, Currency: "USD"}
+	assert.NoError(t, convertPromotion(promo, "EUR", rates))
+	assert.Equal(t, models.Money(900), promo.AmountOff)
+	assert.Equal(t, models.Money(4500), promo.MinOrderAmount)
+	assert.Equal(t, "EUR", promo.Currency)
+
+	promo = &models.Promotion{Currency: "GBP"}
+	assert.EqualError(t, convertPromotion(promo, "EUR", rates), "no exchange rate from GBP to EUR")
+}
+
+func TestNormalizeCouponCode(t *testing.T) {
+	assert.Equal(t, "SPRING10", normalizeCouponCode("  spring10 "))
+	assert.True(t, couponCode.MatchString("SPRING-10"))
+	assert.False(t, couponCode.MatchString("10 OFF"))
+}
+
+type promotionRepo struct {
+	postgres.ProductRepo
+	deleted []string
+}
+
+func (r *promotionRepo) GetPromotions(c context.Context, request *pro.GetPromotionsRequest, now time.Time) (*pro.GetPromotionsResponse, error) {
+	return &pro.GetPromotionsResponse{Promotions: []*pro.Promotion{{Id: "promo-1", Code: "SPRING10"}, {Id: "promo-2"}}}, nil
+}
+
+func (r *promotionRepo) DeletePromotion(c context.Context, request *pro.DeletePromotionRequest) (*pro.DeletePromotionResponse, error) {
+	r.deleted = append(r.deleted, request.Id)
+	return &pro.DeletePromotionResponse{}, nil
+}
+
+func TestPromotionsNeedAdmin(t *testing.T) {
+	repo := &promotionRepo{}
+	p := newTestService(repo, map[string]string{"admin-1": "admin", "artisan-1": "artisan"})
+	ctx := context.Background()
+
+	_, err := p.AddPromotion(ctx, &pro.AddPromotionRequest{UserId: "artisan-1", Name: "Spring", Kind: "fixed", AmountOff: 5})
+	assert.EqualError(t, err, "user is not an admin")
+	_, err = p.DeletePromotion(ctx, &pro.DeletePromotionRequest{Id: "promo-1"})
+	assert.Error(t, err)
+	_, err = p.DeletePromotion(ctx, &pro.DeletePromotionRequest{Id: "promo-1", UserId: "artisan-1"})
+	assert.EqualError(t, err, "user is not an admin")
+	assert.Empty(t, repo.deleted)
+
+	res, err := p.DeletePromotion(ctx, &pro.DeletePromotionRequest{Id: "promo-1", UserId: "admin-1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "promotion deleted successfully", res.Message)
+	assert.Equal(t, []string{"promo-1"}, repo.deleted)
+}
+
+func TestGetPromotionsHidesCodes(t *testing.T) {
+	p := newTestService(&promotionRepo{}, map[string]string{"admin-1": "admin", "artisan-1": "artisan"})
+	ctx := context.Background()
+
+	for _, userId := range []string{"", "artisan-1", "unknown"} {
+		res, err := p.GetPromotions(ctx, &pro.GetPromotionsRequest{UserId: userId})
+		assert.NoError(t, err)
+		assert.Equal(t, "", res.Promotions[0].Code, userId)
+	}
+
+	res, err := p.GetPromotions(ctx, &pro.GetPromotionsRequest{UserId: "admin-1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "SPRING10", res.Promotions[0].Code)
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	auth "product-service/genproto/authentication_service"
+	pro "product-service/genproto/product_service"
+	"strings"
+	"time"
+)
+
+func (p *productService) RequestReturn(c context.Context, request *pro.RequestReturnRequest) (*pro.RequestReturnResponse, error) {
+	if !p.isValidUser(request.UserId) {
+		p.log.Errorf("user is not valid")
+		return nil, fmt.Errorf("user is not valid")
+	}
+	if len(request.Items) == 0 {
+		return nil, fmt.Errorf("no items to return")
+	}
+	for _, item := range request.Items {
+		if item.Quantity <= 0 {
+			return nil, fmt.Errorf("invalid quantity for order item %s", item.OrderItemId)
+		}
+		item.Reason = strings.TrimSpace(item.Reason)
+		if item.Reason == "" {
+			return nil, fmt.Errorf("a reason is required for order item %s", item.OrderItemId)
+		}
+	}
+
+	deliveredAfter := time.Now().Add(-p.config.OrderConfig.ReturnWindow)
+	res, err := p.productRepo.RequestReturn(c, request, deliveredAfter)
+	if err != nil {
+		p.log.Errorf("failed to request return: %v", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+func (p *productService) ApproveReturn(c context.Context, request *pro.ResolveReturnRequest) (*pro.ResolveReturnResponse, error) {
+	return p.resolveReturn(c, request, true)
+}
+
+func (p *productService) RejectReturn(c context.Context, request *pro.ResolveReturnRequest) (*pro.ResolveReturnResponse, error) {
+	return p.resolveReturn(c, request, false)
+}
+
+func (p *productService) resolveReturn(c context.Context, request *pro.ResolveReturnRequest, approve bool) (*pro.ResolveReturnResponse, error) {
+	res, err := p.authService.GetUserInfo(c, &auth.GetUserInfoRequest{Id: request.ArtisanId})
+	if err != nil {
+		p.log.Errorf("user is not found: %v", err)
+		return nil, fmt.Errorf("user is not found: %v", err)
+	}
+	if res.User == nil || res.User.UserType != "artisan" {
+		p.log.Errorf("user is not an artisan")
+		return nil, fmt.Errorf("user is not an artisan")
+	}
+
+	ret, err := p.productRepo.ResolveReturn(c, request, approve)
+	if err != nil {
+		p.log.Errorf("failed to resolve return: %v", err)
+		return nil, err
+	}
+	if approve && ret.Return != nil {
+		// Approved returns are restocked.
+		p.notifyWishlists(c, ret.Return.ProductId)
+	}
+	return ret, nil
+}
+
+func (p *productService) GetReturns(c context.Context, request *pro.GetReturnsRequest) (*pro.GetReturnsResponse, error) {
+	if request.UserId == "" && request.ArtisanId == "" {
+		return nil, fmt.Errorf("user_id or artisan_id is required")
+	}
+	res, err := p.productRepo.GetReturns(c, request)
+	if err != nil {
+		p.log.Errorf("failed to get returns: %v", err)
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	pro "product-service/genproto/product_service"
+	"product-service/models"
+
+	"github.com/google/uuid"
+)
+
+// photoExtensions are the image types accepted as review photos, with the
+// file extension they are stored under.
+var photoExtensions = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/webp": "webp",
+}
+
+// photoType checks the size of an uploaded photo and detects its type from
+// its content. A content type given by the client must match the content.
+func photoType(data []byte, declared string, maxBytes int64) (string, error) {
+	if len(data) == 0 {
+		return "", fmt.Errorf("photo is empty")
+	}
+	if int64(len(data)) > maxBytes {
+		return "", fmt.Errorf("photo must be at most %d bytes", maxBytes)
+	}
+	contentType := http.DetectContentType(data)
+	if _, ok := photoExtensions[contentType]; !ok {
+		return "", fmt.Errorf("photo must be a JPEG, PNG or WebP image")
+	}
+	if declared != "" && declared != contentType {
+		return "", fmt.Errorf("photo is %s, not %s", contentType, declared)
+	}
+	return contentType, nil
+}
+
+func (p *productService) ratingPhoto(photo *models.RatingPhoto) *pro.RatingPhoto {
+	return &pro.RatingPhoto{
+		Id:          photo.Id,
+		Url:         p.blobs.URL(photo.Key),
+		ContentType: photo.ContentType,
+		SizeBytes:   photo.SizeBytes,
+		CreatedAt:   photo.CreatedAt,
+	}
+}
+
+// AddRatingPhoto attaches a photo to the user's published review. The photo
+// is only stored once the review is known to accept it, and removed again if
+// it cannot be attached after all.
+func (p *productService) AddRatingPhoto(c context.Context, request *pro.AddRatingPhotoRequest) (*pro.AddRatingPhotoResponse, error) {
+	if !p.isValidUser(request.UserId) {
+		p.log.Errorf("user is not valid")
+		return nil, fmt.Errorf("user is not valid")
+	}
+	if _, err := uuid.Parse(request.RatingId); err != nil {
+		return nil, fmt.Errorf("invalid rating id")
+	}
+	contentType, err := photoType(request.Data, request.ContentType, p.config.ReviewConfig.MaxPhotoBytes)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.productRepo.CanAddRatingPhoto(c, request.RatingId, request.UserId, p.config.ReviewConfig.MaxPhotos); err != nil {
+		p.log.Errorf("failed to add rating photo: %v", err)
+		return nil, err
+	}
+
+	photo := &models.RatingPhoto{
+		Id:          uuid.NewString(),
+		RatingId:    request.RatingId,
+		ContentType: contentType,
+		SizeBytes:   int64(len(request.Data)),
+	}
+	photo.Key = fmt.Sprintf("ratings/%s/%s.%s", photo.RatingId, photo.Id, photoExtensions[contentType])
+	if err := p.blobs.Put(c, photo.Key, request.Data); err != nil {
+		p.log.Errorf("failed to store rating photo: %v", err)
+		return nil, fmt.Errorf("failed to store photo")
+	}
+
+	if err := p.productRepo.AddRatingPhoto(c, photo, request.UserId, p.config.ReviewConfig.MaxPhotos); err != nil {
+		p.log.Errorf("failed to add rating photo: %v", err)
+		if err := p.blobs.Delete(c, photo.Key); err != nil {
+			p.log.Errorf("failed to remove stored photo %s: %v", photo.Key, err)
+		}
+		return nil, err
+	}
+	return &pro.AddRatingPhotoResponse{RatingId: photo.RatingId, Photo: p.ratingPhoto(photo)}, nil
+}
+
+// DeleteRatingPhoto removes a photo from the user's review.
+func (p *productService) DeleteRatingPhoto(c context.Context, request *pro.DeleteRatingPhotoRequest) (*pro.DeleteRatingPhotoResponse, error) {
+	if !p.isValidUser(request.UserId) {
+		p.log.Errorf("user is not valid")
+		return nil, fmt.Errorf("user is not valid")
+	}
+
+	key, err := p.productRepo.DeleteRatingPhoto(c, request.PhotoId, request.UserId)
+	if err != nil {
+		p.log.Errorf("failed to delete rating photo: %v", err)
+		return nil, err
+	}
+	// The photo is already detached; a file left behind is only logged.
+	if err := p.blobs.Delete(c, key); err != nil {
+		p.log.Errorf("failed to remove stored photo %s: %v", key, err)
+	}
+	return &pro.DeleteRatingPhotoResponse{Message: "photo deleted"}, nil
+}
+
+// attachRatingPhotos adds the photos of the given reviews with the URLs they
+// are served at.
+func (p *productService) attachRatingPhotos(c context.Context, ratings []*pro.Rating) error {
+	ids := make([]string, 0, len(ratings))
+	for _, rating := range ratings {
+		ids = append(ids, rating.Id)
+	}
+	photos, err := p.productRepo.GetRatingPhotos(c, ids)
+	if err != nil {
+		return err
+	}
+	for _, rating := range ratings {
+		for _, photo := range photos[rating.Id] {
+			rating.Photos = append(rating.Photos, p.ratingPhoto(photo))
+		}
+	}
+	return nil
+}
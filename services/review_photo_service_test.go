@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"product-service/blob"
+	pro "product-service/genproto/product_service"
+	"product-service/models"
+	"product-service/storage/postgres"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	jpegHeader = []byte{0xFF, 0xD8, 0xFF, 0xE0, 0x00, 0x10, 'J', 'F', 'I', 'F', 0x00}
+	pngHeader  = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR")
+	webpHeader = []byte("RIFF\x24\x00\x00\x00WEBPVP8 ")
+)
+
+func TestPhotoType(t *testing.T) {
+	contentType, err := photoType(jpegHeader, "", 1024)
+	assert.NoError(t, err)
+	assert.Equal(t, "image/jpeg", contentType)
+
+	contentType, err = photoType(pngHeader, "image/png", 1024)
+	assert.NoError(t, err)
+	assert.Equal(t, "image/png", contentType)
+
+	contentType, err = photoType(webpHeader, "", 1024)
+	assert.NoError(t, err)
+	assert.Equal(t, "image/webp", contentType)
+}
+
+func TestPhotoTypeRejects(t *testing.T) {
+	_, err := photoType(nil, "", 1024)
+	assert.EqualError(t, err, "photo is empty")
+
+	_, err = photoType(jpegHeader, "", 8)
+	assert.EqualError(t, err, "photo must be at most 8 bytes")
+
+	_, err = photoType([]byte("GIF89a\x01\x00\x01\x00"), "image/gif", 1024)
+	assert.EqualError(t, err, "photo must be a JPEG, PNG or WebP image")
+
+	_, err = photoType([]byte("<svg xmlns='http://www.w3.org/2000/svg'/>"), "image/png", 1024)
+	assert.EqualError(t, err, "photo must be a JPEG, PNG or WebP image")
+
+	_, err = photoType(pngHeader, "image/jpeg", 1024)
+	assert.EqualError(t, err, "photo is image/png, not image/jpeg")
+}
+
+// fakeBlobs records the keys stored and deleted.
+type fakeBlobs struct {
+	blob.Store
+	puts, deletes []string
+}
+
+func (b *fakeBlobs) Put(c context.Context, key string, data []byte) error {
+	b.puts = append(b.puts, key)
+	return nil
+}
+
+func (b *fakeBlobs) Delete(c context.Context, key string) error {
+	b.deletes = append(b.deletes, key)
+	return nil
+}
+
+type ratingPhotoRepo struct {
+	postgres.ProductRepo
+	author, status string
+}
+
+func (r *ratingPhotoRepo) CanAddRatingPhoto(c context.Context, ratingId, userId string, maxPhotos int) error {
+	if r.author != userId {
+		return fmt.Errorf("you can only add photos to your own review")
+	}
+	if r.status != models.RatingVisible {
+		return fmt.Errorf("photos can only be added to published reviews")
+	}
+	return nil
+}
+
+func (r *ratingPhotoRepo) ModerateRating(c context.Context, request *pro.ModerateRatingRequest) (*pro.Rating, []string, error) {
+	return &pro.Rating{Id: request.RatingId, Status: models.RatingHidden}, []string{"ratings/rating-1/photo-1.jpg"}, nil
+}
+
+func TestAddRatingPhotoChecksBeforeStoring(t *testing.T) {
+	blobs := &fakeBlobs{}
+	repo := &ratingPhotoRepo{author: "user-1", status: models.RatingHidden}
+	p := newTestService(repo, map[string]string{"user-1": "customer", "user-2": "customer"})
+	p.blobs = blobs
+	p.config.ReviewConfig.MaxPhotoBytes = 1024
+	request := &pro.AddRatingPhotoRequest{RatingId: "0b0c4cb8-7a5e-4d3e-9a55-7b1f0a3c2d11", Data: jpegHeader}
+
+	request.UserId = "user-2"
+	_, err := p.AddRatingPhoto(context.Background(), request)
+	assert.EqualError(t, err, "you can only add photos to your own review")
+
+	request.UserId = "user-1"
+	_, err = p.AddRatingPhoto(context.Background(), request)
+	assert.EqualError(t, err, "photos can only be added to published reviews")
+	assert.Empty(t, blobs.puts)
+}
+
+func TestModerateRatingDeletesPhotos(t *testing.T) {
+	blobs := &fakeBlobs{}
+	p := newTestService(&ratingPhotoRepo{}, map[string]string{"admin-1": "admin"})
+	p.blobs = blobs
+
+	request := &pro.ModerateRatingRequest{RatingId: "rating-1", ModeratorId: "admin-1", Action: models.ModerationHide, Reason: "Spam"}
+	res, err := p.ModerateRating(context.Background(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, "review hidden", res.Message)
+	assert.Equal(t, []string{"ratings/rating-1/photo-1.jpg"}, blobs.deletes)
+}
@@ -0,0 +1,247 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	auth "product-service/genproto/authentication_service"
+	pro "product-service/genproto/product_service"
+	"product-service/models"
+	"strings"
+	"unicode"
+)
+
+const (
+	// maxReviewLength caps the comment of a review, in characters.
+	maxReviewLength = 2000
+	// maxReportLength caps the reason given when reporting a review.
+	maxReportLength = 500
+)
+
+// normalizeWords lower-cases text and reduces it to its words separated by
+// single spaces, padded with a space on each side.
+func normalizeWords(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return " " + strings.Join(words, " ") + " "
+}
+
+// bannedWord returns the first banned word or phrase the comment contains.
+// Matching ignores case and punctuation and only matches whole words.
+func bannedWord(comment string, banned []string) (string, bool) {
+	text := normalizeWords(comment)
+	for _, word := range banned {
+		phrase := normalizeWords(word)
+		if phrase != "  " && strings.Contains(text, phrase) {
+			return strings.TrimSpace(word), true
+		}
+	}
+	return "", false
+}
+
+// validateRating checks a review: a whole number of stars from 1 to 5 and a
+// comment of reasonable length.
+func validateRating(rating *pro.AddRatingRequest) error {
+	if rating.Rating < 1 || rating.Rating > 5 || rating.Rating != float64(int32(rating.Rating)) {
+		return fmt.Errorf("rating must be a whole number from 1 to 5")
+	}
+	if len([]rune(rating.Comment)) > maxReviewLength {
+		return fmt.Errorf("comment must be at most %d characters", maxReviewLength)
+	}
+	return nil
+}
+
+// AddRating reviews a product. Users have one review per product; reviewing
+// it again edits that review. Comments with banned words are held back for a
+// moderator.
+func (p *productService) AddRating(c context.Context, rating *pro.AddRatingRequest) (*pro.AddRatingResponse, error) {
+	rating.Comment = strings.TrimSpace(rating.Comment)
+	if err := validateRating(rating); err != nil {
+		return nil, err
+	}
+	_, err := p.authService.GetUserInfo(c, &auth.GetUserInfoRequest{Id: rating.UserId})
+	if err != nil {
+		p.log.Errorf("user is not found: %v", err)
+		return nil, fmt.Errorf("user is not found: %v", err)
+	}
+
+	status := models.RatingVisible
+	if word, ok := bannedWord(rating.Comment, p.config.ReviewConfig.BannedWords); ok {
+		p.log.Infof("holding review of product %s by user %s for moderation: contains %q", rating.ProductId, rating.UserId, word)
+		status = models.RatingPending
+	}
+
+	res, err := p.productRepo.AddRating(c, rating, status)
+	if err != nil {
+		p.log.Errorf("failed to add rating: %v", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetRatings pages through a product's reviews with their photos, newest
+// first unless sorted otherwise.
+func (p *productService) GetRatings(c context.Context, request *pro.GetRatingsRequest) (*pro.GetRatingsResponse, error) {
+	if request.SortBy == "" {
+		request.SortBy = models.RatingSortNewest
+	}
+	if request.Limit <= 0 || request.Limit > 100 {
+		request.Limit = 20
+	}
+	if request.Offset < 0 {
+		return nil, fmt.Errorf("offset must not be negative")
+	}
+
+	res, err := p.productRepo.GetRatings(c, request)
+	if err != nil {
+		p.log.Errorf("failed to get ratings: %v", err)
+		return nil, err
+	}
+	if err := p.attachRatingPhotos(c, res.Ratings); err != nil {
+		p.log.Errorf("failed to get rating photos: %v", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+// ReplyToRating posts the artisan's public reply to a review of their
+// product. Replying again edits the reply.
+func (p *productService) ReplyToRating(c context.Context, request *pro.ReplyToRatingRequest) (*pro.ReplyToRatingResponse, error) {
+	res, err := p.authService.GetUserInfo(c, &auth.GetUserInfoRequest{Id: request.ArtisanId})
+	if err != nil {
+		p.log.Errorf("user is not found: %v", err)
+		return nil, fmt.Errorf("user is not found: %v", err)
+	}
+	if res.User == nil || res.User.UserType != "artisan" {
+		p.log.Errorf("user is not an artisan")
+		return nil, fmt.Errorf("user is not an artisan")
+	}
+	request.Body = strings.TrimSpace(request.Body)
+	if request.Body == "" {
+		return nil, fmt.Errorf("reply is required")
+	}
+	if len([]rune(request.Body)) > maxReviewLength {
+		return nil, fmt.Errorf("reply must be at most %d characters", maxReviewLength)
+	}
+
+	reply, err := p.productRepo.ReplyToRating(c, request)
+	if err != nil {
+		p.log.Errorf("failed to reply to rating: %v", err)
+		return nil, err
+	}
+	return &pro.ReplyToRatingResponse{RatingId: request.RatingId, Reply: reply}, nil
+}
+
+// VoteRating marks a review helpful or unhelpful for the user. Voting again
+// changes the vote.
+func (p *productService) VoteRating(c context.Context, request *pro.VoteRatingRequest) (*pro.VoteRatingResponse, error) {
+	if !p.isValidUser(request.UserId) {
+		p.log.Errorf("user is not valid")
+		return nil, fmt.Errorf("user is not valid")
+	}
+
+	res, err := p.productRepo.VoteRating(c, request)
+	if err != nil {
+		p.log.Errorf("failed to vote on rating: %v", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+func (p *productService) ReportRating(c context.Context, request *pro.ReportRatingRequest) (*pro.ReportRatingResponse, error) {
+	if !p.isValidUser(request.UserId) {
+		p.log.Errorf("user is not valid")
+		return nil, fmt.Errorf("user is not valid")
+	}
+	request.Reason = strings.TrimSpace(request.Reason)
+	if len([]rune(request.Reason)) > maxReportLength {
+		return nil, fmt.Errorf("reason must be at most %d characters", maxReportLength)
+	}
+
+	if err := p.productRepo.ReportRating(c, request); err != nil {
+		p.log.Errorf("failed to report rating: %v", err)
+		return nil, err
+	}
+	return &pro.ReportRatingResponse{Message: "review reported"}, nil
+}
+
+// requireAdmin checks that the user is an admin.
+func (p *productService) requireAdmin(c context.Context, userId string) error {
+	res, err := p.authService.GetUserInfo(c, &auth.GetUserInfoRequest{Id: userId})
+	if err != nil {
+		p.log.Errorf("user is not found: %v", err)
+		return fmt.Errorf("user is not found: %v", err)
+	}
+	if res.User == nil || res.User.UserType != "admin" {
+		p.log.Errorf("user is not an admin")
+		return fmt.Errorf("user is not an admin")
+	}
+	return nil
+}
+
+// isAdmin reports whether userId belongs to an admin. Unlike requireAdmin it
+// treats unknown users as non-admins instead of failing.
+func (p *productService) isAdmin(c context.Context, userId string) bool {
+	if userId == "" {
+		return false
+	}
+	res, err := p.authService.GetUserInfo(c, &auth.GetUserInfoRequest{Id: userId})
+	return err == nil && res.User != nil && res.User.UserType == "admin"
+}
+
+func (p *productService) GetModerationQueue(c context.Context, request *pro.GetModerationQueueRequest) (*pro.GetModerationQueueResponse, error) {
+	if err := p.requireAdmin(c, request.ModeratorId); err != nil {
+		return nil, err
+	}
+	if request.Limit <= 0 || request.Limit > 100 {
+		request.Limit = 20
+	}
+	if request.Offset < 0 {
+		return nil, fmt.Errorf("offset must not be negative")
+	}
+
+	items, err := p.productRepo.GetModerationQueue(c, request.Limit, request.Offset)
+	if err != nil {
+		p.log.Errorf("failed to get moderation queue: %v", err)
+		return nil, err
+	}
+	return &pro.GetModerationQueueResponse{Items: items}, nil
+}
+
+var moderatedVerbs = map[string]string{
+	models.ModerationApprove: "approved",
+	models.ModerationHide:    "hidden",
+	models.ModerationDelete:  "deleted",
+}
+
+// ModerateRating approves, hides or deletes a review. Hiding and deleting
+// need a reason, which is kept with the review, and remove the review's
+// photos.
+func (p *productService) ModerateRating(c context.Context, request *pro.ModerateRatingRequest) (*pro.ModerateRatingResponse, error) {
+	if err := p.requireAdmin(c, request.ModeratorId); err != nil {
+		return nil, err
+	}
+	request.Reason = strings.TrimSpace(request.Reason)
+	switch request.Action {
+	case models.ModerationApprove:
+	case models.ModerationHide, models.ModerationDelete:
+		if request.Reason == "" {
+			return nil, fmt.Errorf("a reason is required to %s a review", request.Action)
+		}
+	default:
+		return nil, fmt.Errorf("moderation action must be %s, %s or %s", models.ModerationApprove, models.ModerationHide, models.ModerationDelete)
+	}
+
+	rating, photoKeys, err := p.productRepo.ModerateRating(c, request)
+	if err != nil {
+		p.log.Errorf("failed to moderate rating: %v", err)
+		return nil, err
+	}
+	// The photos are already detached; files left behind are only logged.
+	for _, key := range photoKeys {
+		if err := p.blobs.Delete(c, key); err != nil {
+			p.log.Errorf("failed to remove stored photo %s: %v", key, err)
+		}
+	}
+	return &pro.ModerateRatingResponse{Rating: rating, Message: fmt.Sprintf("review %s", moderatedVerbs[request.Action])}, nil
+}
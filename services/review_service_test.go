@@ -0,0 +1,38 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	pro "product-service/genproto/product_service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateRating(t *testing.T) {
+	assert.NoError(t, validateRating(&pro.AddRatingRequest{Rating: 1}))
+	assert.NoError(t, validateRating(&pro.AddRatingRequest{Rating: 5, Comment: "Perfect"}))
+
+	for _, rating := range []float64{0, 6, 4.5, -1} {
+		assert.EqualError(t, validateRating(&pro.AddRatingRequest{Rating: rating}), "rating must be a whole number from 1 to 5")
+	}
+	err := validateRating(&pro.AddRatingRequest{Rating: 3, Comment: strings.Repeat("a", maxReviewLength+1)})
+	assert.EqualError(t, err, "comment must be at most 2000 characters")
+}
+
+func TestBannedWord(t *testing.T) {
+	banned := []string{"scam", "Buy Now"}
+
+	word, ok := bannedWord("Total SCAM!", banned)
+	assert.True(t, ok)
+	assert.Equal(t, "scam", word)
+
+	word, ok = bannedWord("buy, now: cheap", banned)
+	assert.True(t, ok)
+	assert.Equal(t, "Buy Now", word)
+
+	_, ok = bannedWord("Not a scammer's vase", banned)
+	assert.False(t, ok)
+	_, ok = bannedWord("anything", []string{""})
+	assert.False(t, ok)
+}
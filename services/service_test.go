@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"product-service/configs"
+	auth "product-service/genproto/authentication_service"
+	"product-service/storage/postgres"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+)
+
+// fakeAuth knows the user type of each user id; other ids are not found.
+type fakeAuth struct {
+	auth.AuthenticationServiceClient
+	users map[string]string
+}
+
+func (a *fakeAuth) GetUserInfo(ctx context.Context, in *auth.GetUserInfoRequest, opts ...grpc.CallOption) (*auth.GetUserInfoResponse, error) {
+	userType, ok := a.users[in.Id]
+	if !ok {
+		return nil, fmt.Errorf("user %s not found", in.Id)
+	}
+	return &auth.GetUserInfoResponse{User: &auth.User{Id: in.Id, UserType: userType}}, nil
+}
+
+// newTestService builds a service on top of repo, which only needs to
+// implement the methods the test calls.
+func newTestService(repo postgres.ProductRepo, users map[string]string) *productService {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	return &productService{
+		authService: &fakeAuth{users: users},
+		productRepo: repo,
+		log:         log,
+		config:      &configs.Config{},
+	}
+}
@@ -0,0 +1,243 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"product-service/carrier"
+	pro "product-service/genproto/product_service"
+	"product-service/models"
+	"strings"
+	"time"
+)
+
+// UpdateShippingInfo records the carrier and tracking number an order ships
+// with. The estimated delivery date is a calendar date such as 2024-05-31.
+func (p *productService) UpdateShippingInfo(c context.Context, request *pro.UpdateShippingInfoRequest) (*pro.UpdateShippingInfoResponse, error) {
+	request.TrackingNumber = strings.TrimSpace(request.TrackingNumber)
+	request.Carrier = strings.TrimSpace(request.Carrier)
+	if request.OrderId == "" {
+		return nil, fmt.Errorf("order id is required")
+	}
+	if request.TrackingNumber == "" || request.Carrier == "" {
+		return nil, fmt.Errorf("carrier and tracking number are required")
+	}
+
+	var estimatedDelivery *time.Time
+	if request.EstimatedDeliveryDate != "" {
+		date, err := time.Parse("2006-01-02", request.EstimatedDeliveryDate)
+		if err != nil {
+			return nil, fmt.Errorf("estimated delivery date must be formatted as YYYY-MM-DD")
+		}
+		estimatedDelivery = &date
+	}
+
+	res, err := p.productRepo.UpdateShippingInfo(c, request, estimatedDelivery)
+	if err != nil {
+		p.log.Errorf("failed to update shipping info: %v", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+// AddTrackingEvent adds a step to a shipment's timeline, naming the shipment
+// by id or by carrier and tracking number. Events without a time happened
+// now.
+func (p *productService) AddTrackingEvent(c context.Context, request *pro.AddTrackingEventRequest) (*pro.AddTrackingEventResponse, error) {
+	event := &models.TrackingEvent{
+		ShipmentId:     request.ShipmentId,
+		Carrier:        strings.TrimSpace(request.Carrier),
+		TrackingNumber: strings.TrimSpace(request.TrackingNumber),
+		Status:         request.Status,
+		Location:       strings.TrimSpace(request.Location),
+		Description:    strings.TrimSpace(request.Description),
+		OccurredAt:     time.Now(),
+	}
+	if event.ShipmentId == "" && (event.Carrier == "" || event.TrackingNumber == "") {
+		return nil, fmt.Errorf("shipment id or carrier and tracking number are required")
+	}
+	if !models.TrackingStatuses[event.Status] {
+		return nil, fmt.Errorf("invalid tracking status: %s", event.Status)
+	}
+	if request.OccurredAt != "" {
+		occurredAt, err := time.Parse(time.RFC3339, request.OccurredAt)
+		if err != nil {
+			return nil, fmt.Errorf("occurred_at must be an RFC 3339 timestamp")
+		}
+		event.OccurredAt = occurredAt
+	}
+
+	res, err := p.productRepo.AddTrackingEvent(c, event)
+	if err != nil {
+		p.log.Errorf("failed to add tracking event: %v", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+func (p *productService) GetShipments(c context.Context, request *pro.GetShipmentsRequest) (*pro.GetShipmentsResponse, error) {
+	res, err := p.productRepo.GetShipments(c, request)
+	if err != nil {
+		p.log.Errorf("failed to get shipments: %v", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+// CreateShippingLabel books the order's parcel with an integrated carrier and
+// records the tracking number it issues, as UpdateShippingInfo would. Orders
+// that already have a shipment cannot be booked again.
+func (p *productService) CreateShippingLabel(c context.Context, request *pro.CreateShippingLabelRequest) (*pro.CreateShippingLabelResponse, error) {
+	adapter, err := p.carriers.Get(request.Carrier)
+	if err != nil {
+		return nil, err
+	}
+	res, err := p.productRepo.GetOrder(c, &pro.GetOrderRequest{Id: request.OrderId})
+	if err != nil {
+		p.log.Errorf("failed to get order: %v", err)
+		return nil, err
+	}
+	order := res.Order
+	if order.Status == "canceled" || order.Status == "returned" {
+		return nil, fmt.Errorf("cannot ship a %s order", order.Status)
+	}
+	if order.Address == nil {
+		return nil, fmt.Errorf("order has no shipping address")
+	}
+	// Saves booking a parcel that could not be recorded; AddShipment checks
+	// again while holding the order.
+	if len(order.Shipments) > 0 {
+		shipment := order.Shipments[0]
+		return nil, fmt.Errorf("order already ships with %s under tracking number %s", shipment.Carrier, shipment.TrackingNumber)
+	}
+
+	items := make([]*pro.Item, 0, len(order.Items))
+	for _, item := range order.Items {
+		items = append(items, &pro.Item{ProductId: item.ProductId, Quantity: item.Quantity})
+	}
+	products, err := p.productRepo.GetCheckoutProducts(c, itemProductIds(items))
+	if err != nil {
+		p.log.Errorf("failed to get products: %v", err)
+		return nil, err
+	}
+	box, err := parcel(items, products)
+	if err != nil {
+		return nil, err
+	}
+
+	booked, err := adapter.CreateShipment(c, &carrier.ShipmentRequest{
+		Reference: order.Id,
+		Method:    order.ShippingMethod,
+		Address: &models.ShippingAddress{
+			Street:        order.Address.Street,
+			City:          order.Address.City,
+			Country:       order.Address.Country,
+			ZipCode:       order.Address.ZipCode,
+			Region:        order.Address.Region,
+			RecipientName: order.Address.RecipientName,
+			Phone:         order.Address.Phone,
+		},
+		WeightGrams: box.WeightGrams,
+	})
+	if err != nil {
+		p.log.Errorf("failed to book shipment with %s: %v", adapter.Name(), err)
+		return nil, err
+	}
+
+	info := &pro.UpdateShippingInfoRequest{OrderId: order.Id, TrackingNumber: booked.TrackingNumber, Carrier: adapter.Name()}
+	if booked.EstimatedDelivery != nil {
+		info.EstimatedDeliveryDate = booked.EstimatedDelivery.Format("2006-01-02")
+	}
+	updated, err := p.productRepo.AddShipment(c, info, booked.EstimatedDelivery)
+	if err != nil {
+		p.log.Errorf("failed to record %s shipment %s: %v", adapter.Name(), booked.TrackingNumber, err)
+		return nil, err
+	}
+	shipment, err := p.productRepo.GetShipment(c, updated.ShipmentId)
+	if err != nil {
+		p.log.Errorf("failed to get shipment: %v", err)
+		return nil, err
+	}
+	label, err := shippingLabel(c, adapter, shipment)
+	if err != nil {
+		p.log.Errorf("failed to get shipping label: %v", err)
+		return nil, err
+	}
+
+	return &pro.CreateShippingLabelResponse{Shipment: shipment, OrderStatus: updated.Status, Label: label}, nil
+}
+
+func shippingLabel(c context.Context, adapter carrier.Carrier, shipment *pro.Shipment) (*pro.ShippingLabel, error) {
+	label, err := adapter.GetLabel(c, shipment.TrackingNumber)
+	if err != nil {
+		return nil, err
+	}
+	return &pro.ShippingLabel{
+		ShipmentId:     shipment.Id,
+		Carrier:        shipment.Carrier,
+		TrackingNumber: shipment.TrackingNumber,
+		ContentType:    label.ContentType,
+		Data:           label.Data,
+	}, nil
+}
+
+func (p *productService) GetShippingLabel(c context.Context, request *pro.GetShippingLabelRequest) (*pro.GetShippingLabelResponse, error) {
+	shipment, err := p.productRepo.GetShipment(c, request.ShipmentId)
+	if err != nil {
+		p.log.Errorf("failed to get shipment: %v", err)
+		return nil, err
+	}
+	adapter, err := p.carriers.Get(shipment.Carrier)
+	if err != nil {
+		return nil, err
+	}
+	label, err := shippingLabel(c, adapter, shipment)
+	if err != nil {
+		p.log.Errorf("failed to get shipping label: %v", err)
+		return nil, err
+	}
+	return &pro.GetShippingLabelResponse{Label: label}, nil
+}
+
+// RefreshTracking pulls a shipment's tracking events from its carrier and
+// records them. Events already on the timeline are ignored, so it is safe
+// to call as often as the carrier allows.
+func (p *productService) RefreshTracking(c context.Context, request *pro.RefreshTrackingRequest) (*pro.RefreshTrackingResponse, error) {
+	shipment, err := p.productRepo.GetShipment(c, request.ShipmentId)
+	if err != nil {
+		p.log.Errorf("failed to get shipment: %v", err)
+		return nil, err
+	}
+	adapter, err := p.carriers.Get(shipment.Carrier)
+	if err != nil {
+		return nil, err
+	}
+	events, err := adapter.Track(c, shipment.TrackingNumber)
+	if err != nil {
+		p.log.Errorf("failed to track shipment %s with %s: %v", shipment.Id, adapter.Name(), err)
+		return nil, err
+	}
+
+	res := &pro.RefreshTrackingResponse{Shipment: shipment}
+	for _, event := range events {
+		if !models.TrackingStatuses[event.Status] {
+			p.log.Errorf("skipped %s event with unknown status %s", adapter.Name(), event.Status)
+			continue
+		}
+		event.ShipmentId = shipment.Id
+		added, err := p.productRepo.AddTrackingEvent(c, event)
+		if err != nil {
+			p.log.Errorf("failed to add tracking event: %v", err)
+			return nil, err
+		}
+		res.Shipment, res.OrderStatus = added.Shipment, added.OrderStatus
+	}
+	if res.OrderStatus == "" {
+		order, err := p.productRepo.GetOrder(c, &pro.GetOrderRequest{Id: shipment.OrderId})
+		if err != nil {
+			p.log.Errorf("failed to get order: %v", err)
+			return nil, err
+		}
+		res.OrderStatus = order.Order.Status
+	}
+	return res, nil
+}
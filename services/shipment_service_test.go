@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"product-service/carrier"
+	pro "product-service/genproto/product_service"
+	"product-service/models"
+	"product-service/storage/postgres"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type shipmentRepo struct {
+	postgres.ProductRepo
+	order     *pro.Order
+	shipments map[string]*pro.Shipment
+}
+
+func (r *shipmentRepo) GetOrder(c context.Context, request *pro.GetOrderRequest) (*pro.GetOrderResponse, error) {
+	return &pro.GetOrderResponse{Order: r.order}, nil
+}
+
+func (r *shipmentRepo) GetCheckoutProducts(c context.Context, productIds []string) (map[string]*models.Product, error) {
+	return map[string]*models.Product{"product-1": {WeightGrams: 500}}, nil
+}
+
+func (r *shipmentRepo) AddShipment(c context.Context, request *pro.UpdateShippingInfoRequest, estimatedDelivery *time.Time) (*pro.UpdateShippingInfoResponse, error) {
+	if shipment, ok := r.shipments["shipment-1"]; ok {
+		return nil, fmt.Errorf("order already ships with %s under tracking number %s", shipment.Carrier, shipment.TrackingNumber)
+	}
+	shipment := &pro.Shipment{Id: "shipment-1", Carrier: request.Carrier, TrackingNumber: request.TrackingNumber}
+	r.shipments[shipment.Id] = shipment
+	return &pro.UpdateShippingInfoResponse{ShipmentId: shipment.Id, Status: "shipped"}, nil
+}
+
+func (r *shipmentRepo) GetShipment(c context.Context, id string) (*pro.Shipment, error) {
+	return r.shipments[id], nil
+}
+
+func TestCreateShippingLabelTwice(t *testing.T) {
+	// Both calls read the order before either records its shipment, as
+	// concurrent calls would.
+	repo := &shipmentRepo{
+		order: &pro.Order{
+			Id:      "order-1",
+			Status:  "processing",
+			Address: &pro.Address{Street: "1 Main St", City: "Berlin", Country: "DE", ZipCode: "10115"},
+			Items:   []*pro.OrderItem{{ProductId: "product-1", Quantity: 2}},
+		},
+		shipments: make(map[string]*pro.Shipment),
+	}
+	p := newTestService(repo, nil)
+	p.carriers = carrier.NewRegistry(carrier.NewMockCarrier())
+	ctx := context.Background()
+
+	res, err := p.CreateShippingLabel(ctx, &pro.CreateShippingLabelRequest{OrderId: "order-1", Carrier: "mock"})
+	assert.NoError(t, err)
+	assert.Equal(t, "shipped", res.OrderStatus)
+	tracking := res.Shipment.TrackingNumber
+
+	_, err = p.CreateShippingLabel(ctx, &pro.CreateShippingLabelRequest{OrderId: "order-1", Carrier: "mock"})
+	assert.EqualError(t, err, "order already ships with mock under tracking number "+tracking)
+	assert.Equal(t, tracking, repo.shipments["shipment-1"].TrackingNumber)
+
+	repo.order.Shipments = []*pro.Shipment{repo.shipments["shipment-1"]}
+	_, err = p.CreateShippingLabel(ctx, &pro.CreateShippingLabelRequest{OrderId: "order-1", Carrier: "mock"})
+	assert.EqualError(t, err, "order already ships with mock under tracking number "+tracking)
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	pro "product-service/genproto/product_service"
+	"product-service/models"
+	"product-service/shipping"
+)
+
+func itemProductIds(items []*pro.Item) []string {
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ProductId)
+	}
+	return ids
+}
+
+// parcel works out what the items weigh and how many of them there are.
+func parcel(items []*pro.Item, products map[string]*models.Product) (shipping.Parcel, error) {
+	var parcel shipping.Parcel
+	for _, item := range items {
+		if item.Quantity <= 0 {
+			return parcel, fmt.Errorf("invalid quantity for item %s", item.ProductId)
+		}
+		product, ok := products[item.ProductId]
+		if !ok {
+			return parcel, fmt.Errorf("product %s not found", item.ProductId)
+		}
+		parcel.WeightGrams += int64(product.WeightGrams) * int64(item.Quantity)
+		parcel.Items += item.Quantity
+	}
+	return parcel, nil
+}
+
+// convertQuote converts a shipping quote into currency.
+func convertQuote(quote *models.ShippingQuote, currency string, rates map[string]*models.ExchangeRate) error {
+	if quote.Currency == currency {
+		return nil
+	}
+	rate, ok := rates[quote.Currency]
+	if !ok {
+		return fmt.Errorf("no exchange rate from %s to %s", quote.Currency, currency)
+	}
+	amount, err := quote.Amount.Convert(rate.Rate)
+	if err != nil {
+		return err
+	}
+	quote.Amount = amount
+	quote.Currency = currency
+	return nil
+}
+
+func shippingQuoteToProto(quote *models.ShippingQuote) *pro.ShippingQuote {
+	return &pro.ShippingQuote{
+		Method:   quote.Method,
+		Name:     quote.Name,
+		Amount:   quote.Amount.Float64(),
+		Currency: quote.Currency,
+		MinDays:  quote.MinDays,
+		MaxDays:  quote.MaxDays,
+	}
+}
+
+// prepareCheckout locks the currency and shipping method of an order and
+// works out the exchange rates, shipping cost and promotions it will be placed
+// with. The shipping address must already be resolved.
+func (p *productService) prepareCheckout(c context.Context, order *pro.PlaceOrderRequest) (*models.Checkout, error) {
+	code, err := p.resolveCurrency(order.Currency)
+	if err != nil {
+		return nil, err
+	}
+	order.Currency = code
+	if order.ShippingMethod == "" {
+		order.ShippingMethod = shipping.Standard
+	}
+
+	products, err := p.productRepo.GetCheckoutProducts(c, itemProductIds(order.Items))
+	if err != nil {
+		return nil, err
+	}
+	box, err := parcel(order.Items, products)
+	if err != nil {
+		return nil, err
+	}
+	quote, err := p.shippingRates.Quote(order.Address.Country, order.ShippingMethod, box)
+	if err != nil {
+		return nil, err
+	}
+
+	promos, err := p.checkoutPromotions(c, order)
+	if err != nil {
+		return nil, err
+	}
+
+	bases := []string{quote.Currency}
+	for _, product := range products {
+		bases = append(bases, product.Currency)
+	}
+	for _, promo := range promos {
+		bases = append(bases, promo.Currency)
+	}
+	rates, err := p.exchangeRates(c, bases, order.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if err := convertQuote(quote, order.Currency, rates); err != nil {
+		return nil, err
+	}
+	for _, promo := range promos {
+		if err := convertPromotion(promo, order.Currency, rates); err != nil {
+			return nil, err
+		}
+	}
+
+	return &models.Checkout{Rates: rates, Shipping: quote, Promotions: promos}, nil
+}
+
+// QuoteShipping prices every shipping method available for the items and
+// destination, in the requested currency.
+func (p *productService) QuoteShipping(c context.Context, request *pro.QuoteShippingRequest) (*pro.QuoteShippingResponse, error) {
+	if len(request.Items) == 0 {
+		return nil, fmt.Errorf("items are required")
+	}
+	address, err := p.resolveAddress(c, request.Address, request.AddressId, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+	code, err := p.resolveCurrency(request.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := p.productRepo.GetCheckoutProducts(c, itemProductIds(request.Items))
+	if err != nil {
+		p.log.Errorf("failed to get products: %v", err)
+		return nil, err
+	}
+	box, err := parcel(request.Items, products)
+	if err != nil {
+		return nil, err
+	}
+
+	quotes := p.shippingRates.QuoteAll(address.Country, box)
+	if len(quotes) == 0 {
+		return nil, fmt.Errorf("no shipping method ships these items to %s", address.Country)
+	}
+	rates, err := p.exchangeRates(c, []string{p.shippingRates.Currency}, code)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &pro.QuoteShippingResponse{}
+	for _, quote := range quotes {
+		if err := convertQuote(quote, code, rates); err != nil {
+			return nil, err
+		}
+		res.Quotes = append(res.Quotes, shippingQuoteToProto(quote))
+	}
+	return res, nil
+}
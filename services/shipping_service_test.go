@@ -0,0 +1,39 @@
+package services
+
+import (
+	"testing"
+
+	pro "product-service/genproto/product_service"
+	"product-service/models"
+	"product-service/shipping"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParcel(t *testing.T) {
+	products := map[string]*models.Product{
+		"prod-1": {Id: "prod-1", WeightGrams: 400},
+		"prod-2": {Id: "prod-2", WeightGrams: 1250},
+	}
+
+	box, err := parcel([]*pro.Item{{ProductId: "prod-1", Quantity: 3}, {ProductId: "prod-2", Quantity: 1}}, products)
+	assert.NoError(t, err)
+	assert.Equal(t, shipping.Parcel{WeightGrams: 2450, Items: 4}, box)
+
+	_, err = parcel([]*pro.Item{{ProductId: "prod-3", Quantity: 1}}, products)
+	assert.EqualError(t, err, "product prod-3 not found")
+	_, err = parcel([]*pro.Item{{ProductId: "prod-1", Quantity: 0}}, products)
+	assert.EqualError(t, err, "invalid quantity for item prod-1")
+}
+
+func TestConvertQuote(t *testing.T) {
+	rates := map[string]*models.ExchangeRate{"USD": {Base: "USD", Quote: "EUR", Rate: "0.9"}}
+
+	quote := &models.ShippingQuote{Method: shipping.Express, Amount: 1099, Currency: "USD"}
+	assert.NoError(t, convertQuote(quote, "EUR", rates))
+	assert.Equal(t, models.Money(989), quote.Amount)
+	assert.Equal(t, "EUR", quote.Currency)
+
+	quote = &models.ShippingQuote{Amount: 500, Currency: "GBP"}
+	assert.EqualError(t, convertQuote(quote, "EUR", rates), "no exchange rate from GBP to EUR")
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	pro "product-service/genproto/product_service"
+	"product-service/tax"
+	"strings"
+)
+
+// AddTaxRule adds a tax rule. Only admins manage tax rules, since orders
+// are charged and invoiced with them.
+func (p *productService) AddTaxRule(c context.Context, request *pro.AddTaxRuleRequest) (*pro.AddTaxRuleResponse, error) {
+	if err := p.requireAdmin(c, request.UserId); err != nil {
+		return nil, err
+	}
+	request.Country = strings.ToUpper(strings.TrimSpace(request.Country))
+	request.Region = strings.TrimSpace(request.Region)
+	request.Name = strings.TrimSpace(request.Name)
+	request.Rate = strings.TrimSpace(request.Rate)
+	if !countryCode.MatchString(request.Country) {
+		return nil, fmt.Errorf("country must be a two-letter ISO code")
+	}
+	if request.Name == "" {
+		return nil, fmt.Errorf("tax rule name is required")
+	}
+	if _, err := tax.ParseRate(request.Rate); err != nil {
+		return nil, err
+	}
+
+	res, err := p.productRepo.AddTaxRule(c, request)
+	if err != nil {
+		p.log.Errorf("failed to add tax rule: %v", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+func (p *productService) GetTaxRules(c context.Context, request *pro.GetTaxRulesRequest) (*pro.GetTaxRulesResponse, error) {
+	request.Country = strings.ToUpper(strings.TrimSpace(request.Country))
+	res, err := p.productRepo.GetTaxRules(c, request)
+	if err != nil {
+		p.log.Errorf("failed to get tax rules: %v", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+func (p *productService) DeleteTaxRule(c context.Context, request *pro.DeleteTaxRuleRequest) (*pro.DeleteTaxRuleResponse, error) {
+	if err := p.requireAdmin(c, request.UserId); err != nil {
+		return nil, err
+	}
+	res, err := p.productRepo.DeleteTaxRule(c, request)
+	if err != nil {
+		p.log.Errorf("failed to delete tax rule: %v", err)
+		return nil, err
+	}
+	res.Message = "tax rule deleted successfully"
+	return res, nil
+}
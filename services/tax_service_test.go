@@ -0,0 +1,40 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	pro "product-service/genproto/product_service"
+	"product-service/storage/postgres"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type taxRuleRepo struct {
+	postgres.ProductRepo
+	deleted []string
+}
+
+func (r *taxRuleRepo) DeleteTaxRule(c context.Context, request *pro.DeleteTaxRuleRequest) (*pro.DeleteTaxRuleResponse, error) {
+	r.deleted = append(r.deleted, request.Id)
+	return &pro.DeleteTaxRuleResponse{}, nil
+}
+
+func TestTaxRulesNeedAdmin(t *testing.T) {
+	repo := &taxRuleRepo{}
+	p := newTestService(repo, map[string]string{"admin-1": "admin", "artisan-1": "artisan"})
+	ctx := context.Background()
+
+	_, err := p.AddTaxRule(ctx, &pro.AddTaxRuleRequest{UserId: "artisan-1", Country: "DE", Name: "MwSt", Rate: "0"})
+	assert.EqualError(t, err, "user is not an admin")
+	_, err = p.DeleteTaxRule(ctx, &pro.DeleteTaxRuleRequest{Id: "rule-1"})
+	assert.Error(t, err)
+	_, err = p.DeleteTaxRule(ctx, &pro.DeleteTaxRuleRequest{Id: "rule-1", UserId: "artisan-1"})
+	assert.EqualError(t, err, "user is not an admin")
+	assert.Empty(t, repo.deleted)
+
+	res, err := p.DeleteTaxRule(ctx, &pro.DeleteTaxRuleRequest{Id: "rule-1", UserId: "admin-1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "tax rule deleted successfully", res.Message)
+	assert.Equal(t, []string{"rule-1"}, repo.deleted)
+}
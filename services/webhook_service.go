@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"product-service/gateway"
+	"product-service/models"
+	"strings"
+)
+
+// paymentEventStatuses maps provider event types to the payment status they
+// confirm.
+var paymentEventStatuses = map[string]string{
+	"payment.authorized": models.PaymentAuthorized,
+	"payment.captured":   models.PaymentCaptured,
+	"payment.failed":     models.PaymentFailed,
+	"payment.voided":     models.PaymentVoided,
+}
+
+// HandlePaymentEvent applies a verified webhook event from the payment
+// provider and reports whether it was processed, a duplicate or ignored.
+func (p *productService) HandlePaymentEvent(c context.Context, event *gateway.Event) (string, error) {
+	provider := p.paymentProvider
+	to := paymentEventStatuses[event.Type]
+
+	paymentEvent := &models.PaymentEvent{
+		Provider:      provider.Name(),
+		EventId:       event.Id,
+		Type:          event.Type,
+		TransactionId: event.Data.TransactionId,
+		Payload:       event.Raw,
+		To:            to,
+		Reason:        event.Data.DeclineCode,
+	}
+	if to != "" {
+		paymentEvent.Response = providerResponse(provider, &gateway.Result{
+			Operation:     strings.TrimPrefix(event.Type, "payment."),
+			TransactionId: event.Data.TransactionId,
+			Approved:      to != models.PaymentFailed,
+			Amount:        event.Data.Amount,
+			DeclineCode:   event.Data.DeclineCode,
+			Raw:           event.Raw,
+		})
+	}
+	if to == models.PaymentCaptured {
+		paymentEvent.OrderStatus = "processing"
+	}
+
+	outcome, err := p.productRepo.ApplyPaymentEvent(c, paymentEvent)
+	if err != nil {
+		p.log.Errorf("failed to apply payment event %s: %v", event.Id, err)
+		return "", err
+	}
+	if outcome == models.PaymentEventIgnored {
+		p.log.Warnf("ignored payment event %s (%s) for transaction %s", event.Id, event.Type, event.Data.TransactionId)
+	}
+	return outcome, nil
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	pro "product-service/genproto/product_service"
+	"strings"
+)
+
+// notifyWishlists alerts the buyers who wishlisted a product that just
+// changed. Failures are logged and never fail the change itself.
+func (p *productService) notifyWishlists(c context.Context, productId string) {
+	alerts, err := p.productRepo.WishlistAlerts(c, productId)
+	if err != nil {
+		p.log.Errorf("failed to check wishlists for product %s: %v", productId, err)
+		return
+	}
+	for _, alert := range alerts {
+		if err := p.notifier.WishlistAlert(c, alert); err != nil {
+			p.log.Errorf("failed to send %s alert to user %s: %v", alert.Kind, alert.UserId, err)
+		}
+	}
+}
+
+// notifyWishlistsOf runs notifyWishlists once for each of the products,
+// whose stock an order just took or put back.
+func (p *productService) notifyWishlistsOf(c context.Context, productIds []string) {
+	seen := make(map[string]bool, len(productIds))
+	for _, id := range productIds {
+		if !seen[id] {
+			seen[id] = true
+			p.notifyWishlists(c, id)
+		}
+	}
+}
+
+func (p *productService) CreateWishlist(c context.Context, request *pro.CreateWishlistRequest) (*pro.CreateWishlistResponse, error) {
+	if !p.isValidUser(request.UserId) {
+		p.log.Errorf("user is not valid")
+		return nil, fmt.Errorf("user is not valid")
+	}
+	request.Name = strings.TrimSpace(request.Name)
+	if request.Name == "" {
+		return nil, fmt.Errorf("wishlist name is required")
+	}
+	if len(request.Name) > 100 {
+		return nil, fmt.Errorf("wishlist name must be at most 100 characters")
+	}
+
+	wishlist, err := p.productRepo.CreateWishlist(c, request)
+	if err != nil {
+		p.log.Errorf("failed to create wishlist: %v", err)
+		return nil, err
+	}
+	return &pro.CreateWishlistResponse{Wishlist: wishlist}, nil
+}
+
+func (p *productService) GetWishlists(c context.Context, request *pro.GetWishlistsRequest) (*pro.GetWishlistsResponse, error) {
+	wishlists, err := p.productRepo.GetWishlists(c, request.UserId)
+	if err != nil {
+		p.log.Errorf("failed to get wishlists: %v", err)
+		return nil, err
+	}
+	return &pro.GetWishlistsResponse{Wishlists: wishlists}, nil
+}
+
+func (p *productService) GetWishlist(c context.Context, request *pro.GetWishlistRequest) (*pro.GetWishlistResponse, error) {
+	wishlist, err := p.productRepo.GetWishlist(c, request.Id, request.UserId)
+	if err != nil {
+		p.log.Errorf("failed to get wishlist: %v", err)
+		return nil, err
+	}
+	return &pro.GetWishlistResponse{Wishlist: wishlist}, nil
+}
+
+func (p *productService) DeleteWishlist(c context.Context, request *pro.DeleteWishlistRequest) (*pro.DeleteWishlistResponse, error) {
+	res, err := p.productRepo.DeleteWishlist(c, request)
+	if err != nil {
+		p.log.Errorf("failed to delete wishlist: %v", err)
+		return nil, err
+	}
+	res.Message = "wishlist deleted successfully"
+	return res, nil
+}
+
+func (p *productService) AddWishlistItem(c context.Context, request *pro.AddWishlistItemRequest) (*pro.AddWishlistItemResponse, error) {
+	if err := p.productRepo.AddWishlistItem(c, request); err != nil {
+		p.log.Errorf("failed to add wishlist item: %v", err)
+		return nil, err
+	}
+	wishlist, err := p.productRepo.GetWishlist(c, request.WishlistId, request.UserId)
+	if err != nil {
+		p.log.Errorf("failed to get wishlist: %v", err)
+		return nil, err
+	}
+	return &pro.AddWishlistItemResponse{Wishlist: wishlist}, nil
+}
+
+func (p *productService) RemoveWishlistItem(c context.Context, request *pro.RemoveWishlistItemRequest) (*pro.RemoveWishlistItemResponse, error) {
+	if err := p.productRepo.RemoveWishlistItem(c, request); err != nil {
+		p.log.Errorf("failed to remove wishlist item: %v", err)
+		return nil, err
+	}
+	wishlist, err := p.productRepo.GetWishlist(c, request.WishlistId, request.UserId)
+	if err != nil {
+		p.log.Errorf("failed to get wishlist: %v", err)
+		return nil, err
+	}
+	return &pro.RemoveWishlistItemResponse{Wishlist: wishlist}, nil
+}
@@ -0,0 +1,167 @@
+package shipping
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"product-service/models"
+	"strings"
+)
+
+// Shipping method codes.
+const (
+	Standard = "standard"
+	Express  = "express"
+	Pickup   = "pickup"
+)
+
+// ErrUnavailable is returned when a method does not ship to a destination or
+// a parcel falls outside every rate bracket of it.
+var ErrUnavailable = errors.New("shipping method unavailable")
+
+// Method is a way of getting an order to the buyer.
+type Method struct {
+	Code    string `json:"code"`
+	Name    string `json:"name"`
+	MinDays int32  `json:"min_days"`
+	MaxDays int32  `json:"max_days"`
+}
+
+// Rate prices parcels within a weight and item-count bracket. A zero maximum
+// leaves the bracket open-ended. The cost is the base amount plus a per-item
+// amount for every item and a per-kg amount for every started kilogram.
+type Rate struct {
+	MinWeightGrams int64        `json:"min_weight_grams"`
+	MaxWeightGrams int64        `json:"max_weight_grams"`
+	MinItems       int32        `json:"min_items"`
+	MaxItems       int32        `json:"max_items"`
+	Base           models.Money `json:"base"`
+	PerItem        models.Money `json:"per_item"`
+	PerKg          models.Money `json:"per_kg"`
+}
+
+func (r Rate) covers(parcel Parcel) bool {
+	return parcel.WeightGrams >= r.MinWeightGrams &&
+		(r.MaxWeightGrams == 0 || parcel.WeightGrams <= r.MaxWeightGrams) &&
+		parcel.Items >= r.MinItems &&
+		(r.MaxItems == 0 || parcel.Items <= r.MaxItems)
+}
+
+func (r Rate) price(parcel Parcel) models.Money {
+	kgs := (parcel.WeightGrams + 999) / 1000
+	return r.Base + r.PerItem.Mul(parcel.Items) + r.PerKg*models.Money(kgs)
+}
+
+// Zone groups destination countries that share rates. A zone listing "*"
+// catches every country no other zone lists.
+type Zone struct {
+	Name      string            `json:"name"`
+	Countries []string          `json:"countries"`
+	Rates     map[string][]Rate `json:"rates"`
+}
+
+// Parcel is what an order weighs and how many items it holds.
+type Parcel struct {
+	WeightGrams int64
+	Items       int32
+}
+
+// Table is the rate table of every shipping method, priced in Currency.
+type Table struct {
+	Currency string   `json:"currency"`
+	Methods  []Method `json:"methods"`
+	Zones    []Zone   `json:"zones"`
+}
+
+// LoadTable reads a rate table file.
+func LoadTable(path string) (*Table, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading shipping rates: %v", err)
+	}
+	return ParseTable(data)
+}
+
+// ParseTable decodes the contents of a rate table file.
+func ParseTable(data []byte) (*Table, error) {
+	var table Table
+	if err := json.Unmarshal(data, &table); err != nil {
+		return nil, fmt.Errorf("invalid shipping rates: %v", err)
+	}
+	table.Currency = strings.ToUpper(table.Currency)
+	if len(table.Currency) != 3 {
+		return nil, fmt.Errorf("invalid shipping rates: currency is required")
+	}
+
+	methods := make(map[string]bool, len(table.Methods))
+	for _, method := range table.Methods {
+		methods[method.Code] = true
+	}
+	for i, zone := range table.Zones {
+		for j, country := range zone.Countries {
+			table.Zones[i].Countries[j] = strings.ToUpper(country)
+		}
+		for code := range zone.Rates {
+			if !methods[code] {
+				return nil, fmt.Errorf("invalid shipping rates: zone %s uses unknown method %s", zone.Name, code)
+			}
+		}
+	}
+	return &table, nil
+}
+
+// zone returns the zone that ships to country, if any.
+func (t *Table) zone(country string) *Zone {
+	var fallback *Zone
+	for i, zone := range t.Zones {
+		for _, c := range zone.Countries {
+			if c == country {
+				return &t.Zones[i]
+			}
+			if c == "*" && fallback == nil {
+				fallback = &t.Zones[i]
+			}
+		}
+	}
+	return fallback
+}
+
+// Quote prices a parcel sent to country with one method.
+func (t *Table) Quote(country, method string, parcel Parcel) (*models.ShippingQuote, error) {
+	for _, m := range t.Methods {
+		if m.Code != method {
+			continue
+		}
+		zone := t.zone(strings.ToUpper(country))
+		if zone == nil {
+			return nil, fmt.Errorf("%w: %s does not ship to %s", ErrUnavailable, method, country)
+		}
+		for _, rate := range zone.Rates[method] {
+			if rate.covers(parcel) {
+				return &models.ShippingQuote{
+					Method:   m.Code,
+					Name:     m.Name,
+					Amount:   rate.price(parcel),
+					Currency: t.Currency,
+					MinDays:  m.MinDays,
+					MaxDays:  m.MaxDays,
+				}, nil
+			}
+		}
+		return nil, fmt.Errorf("%w: %s does not ship this parcel to %s", ErrUnavailable, method, country)
+	}
+	return nil, fmt.Errorf("unknown shipping method: %s", method)
+}
+
+// QuoteAll prices a parcel sent to country with every method available for
+// it, in the order the methods are listed.
+func (t *Table) QuoteAll(country string, parcel Parcel) []*models.ShippingQuote {
+	var quotes []*models.ShippingQuote
+	for _, m := range t.Methods {
+		if quote, err := t.Quote(country, m.Code, parcel); err == nil {
+			quotes = append(quotes, quote)
+		}
+	}
+	return quotes
+}
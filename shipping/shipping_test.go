@@ -0,0 +1,62 @@
+package shipping
+
+import (
+	"errors"
+	"testing"
+
+	"product-service/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuote(t *testing.T) {
+	table, err := LoadTable("testdata/rates.json")
+	assert.NoError(t, err)
+
+	quote, err := table.Quote("uz", Standard, Parcel{WeightGrams: 1500, Items: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, models.Money(300), quote.Amount)
+	assert.Equal(t, "USD", quote.Currency)
+	assert.Equal(t, int32(10), quote.MaxDays)
+
+	// 3.00 base plus 1.50 for each of the 3 started kilograms.
+	quote, err = table.Quote("UZ", Standard, Parcel{WeightGrams: 2500, Items: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, models.Money(750), quote.Amount)
+
+	quote, err = table.Quote("UZ", Express, Parcel{WeightGrams: 1000, Items: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, models.Money(950), quote.Amount)
+
+	// Other countries fall back to the "*" zone.
+	quote, err = table.Quote("DE", Standard, Parcel{WeightGrams: 1200, Items: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, models.Money(2000), quote.Amount)
+
+	_, err = table.Quote("DE", Express, Parcel{WeightGrams: 1200, Items: 1})
+	assert.True(t, errors.Is(err, ErrUnavailable))
+	_, err = table.Quote("UZ", Express, Parcel{WeightGrams: 6000, Items: 1})
+	assert.True(t, errors.Is(err, ErrUnavailable))
+	_, err = table.Quote("UZ", "drone", Parcel{})
+	assert.EqualError(t, err, "unknown shipping method: drone")
+}
+
+func TestQuoteAll(t *testing.T) {
+	table, err := LoadTable("testdata/rates.json")
+	assert.NoError(t, err)
+
+	quotes := table.QuoteAll("UZ", Parcel{WeightGrams: 500, Items: 1})
+	assert.Equal(t, 3, len(quotes))
+	assert.Equal(t, Pickup, quotes[2].Method)
+	assert.Equal(t, models.Money(0), quotes[2].Amount)
+
+	quotes = table.QuoteAll("FR", Parcel{WeightGrams: 500, Items: 11})
+	assert.Empty(t, quotes)
+}
+
+func TestParseTable(t *testing.T) {
+	_, err := ParseTable([]byte(`{"methods": []}`))
+	assert.Error(t, err)
+	_, err = ParseTable([]byte(`{"currency": "USD", "methods": [], "zones": [{"name": "x", "rates": {"standard": []}}]}`))
+	assert.EqualError(t, err, "invalid shipping rates: zone x uses unknown method standard")
+}
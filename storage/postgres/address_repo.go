@@ -0,0 +1,198 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	pro "product-service/genproto/product_service"
+	"product-service/models"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+const savedAddressColumns = `id, user_id, COALESCE(label, ''), COALESCE(recipient_name, ''), COALESCE(phone, ''),
+		street, city, COALESCE(region, ''), country, COALESCE(zip_code, ''), is_default, created_at, updated_at`
+
+func scanSavedAddress(row rowScanner) (*pro.SavedAddress, error) {
+	address := pro.SavedAddress{Address: &pro.Address{}}
+	err := row.Scan(
+		&address.Id,
+		&address.UserId,
+		&address.Label,
+		&address.Address.RecipientName,
+		&address.Address.Phone,
+		&address.Address.Street,
+		&address.Address.City,
+		&address.Address.Region,
+		&address.Address.Country,
+		&address.Address.ZipCode,
+		&address.IsDefault,
+		&address.CreatedAt,
+		&address.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &address, nil
+}
+
+func shippingAddressFromProto(address *pro.Address) models.ShippingAddress {
+	if address == nil {
+		return models.ShippingAddress{}
+	}
+	return models.ShippingAddress{
+		Street:        address.Street,
+		City:          address.City,
+		Country:       address.Country,
+		ZipCode:       address.ZipCode,
+		Region:        address.Region,
+		RecipientName: address.RecipientName,
+		Phone:         address.Phone,
+	}
+}
+
+func shippingAddressToProto(address models.ShippingAddress) *pro.Address {
+	return &pro.Address{
+		Street:        address.Street,
+		City:          address.City,
+		Country:       address.Country,
+		ZipCode:       address.ZipCode,
+		Region:        address.Region,
+		RecipientName: address.RecipientName,
+		Phone:         address.Phone,
+	}
+}
+
+// clearDefaultAddress unsets the default flag on every other address of the user.
+func clearDefaultAddress(c context.Context, tx *sqlx.Tx, userId, exceptId string) error {
+	_, err := tx.ExecContext(c, `
+		UPDATE addresses SET is_default = FALSE, updated_at = now()
+		WHERE user_id = $1 AND id <> $2 AND is_default AND deleted_at IS NULL
+	`, userId, exceptId)
+	return err
+}
+
+func (r *productRepo) AddAddress(c context.Context, request *pro.AddAddressRequest) (*pro.AddAddressResponse, error) {
+	tx, err := r.db.BeginTxx(c, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	newId := uuid.NewString()
+	if request.IsDefault {
+		if err := clearDefaultAddress(c, tx, request.UserId, newId); err != nil {
+			return nil, fmt.Errorf("error updating default address: %v", err)
+		}
+	}
+
+	a := request.Address
+	row := tx.QueryRowContext(c, `
+		INSERT INTO addresses (id, user_id, label, recipient_name, phone, street, city, region, country, zip_code, is_default)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING `+savedAddressColumns,
+		newId, request.UserId, request.Label, a.RecipientName, a.Phone, a.Street, a.City, a.Region, a.Country, a.ZipCode, request.IsDefault)
+	address, err := scanSavedAddress(row)
+	if err != nil {
+		return nil, fmt.Errorf("error inserting address: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %v", err)
+	}
+	return &pro.AddAddressResponse{Address: address}, nil
+}
+
+func (r *productRepo) GetAddresses(c context.Context, request *pro.GetAddressesRequest) (*pro.GetAddressesResponse, error) {
+	rows, err := r.db.QueryContext(c, `
+		SELECT `+savedAddressColumns+`
+		FROM addresses
+		WHERE user_id = $1 AND deleted_at IS NULL
+		ORDER BY is_default DESC, created_at
+	`, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var addresses []*pro.SavedAddress
+	for rows.Next() {
+		address, err := scanSavedAddress(rows)
+		if err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, address)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &pro.GetAddressesResponse{Addresses: addresses}, nil
+}
+
+// GetAddress returns a saved address of the user, or an error when it does not
+// exist or belongs to someone else.
+func (r *productRepo) GetAddress(c context.Context, id, userId string) (*pro.SavedAddress, error) {
+	row := r.db.QueryRowContext(c, `
+		SELECT `+savedAddressColumns+`
+		FROM addresses
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+	`, id, userId)
+	address, err := scanSavedAddress(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("address not found")
+	}
+	return address, err
+}
+
+func (r *productRepo) UpdateAddress(c context.Context, request *pro.UpdateAddressRequest) (*pro.UpdateAddressResponse, error) {
+	tx, err := r.db.BeginTxx(c, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if request.IsDefault {
+		if err := clearDefaultAddress(c, tx, request.UserId, request.Id); err != nil {
+			return nil, fmt.Errorf("error updating default address: %v", err)
+		}
+	}
+
+	a := request.Address
+	row := tx.QueryRowContext(c, `
+		UPDATE addresses SET
+		label = $1, recipient_name = $2, phone = $3, street = $4, city = $5,
+		region = $6, country = $7, zip_code = $8, is_default = $9, updated_at = now()
+		WHERE id = $10 AND user_id = $11 AND deleted_at IS NULL
+		RETURNING `+savedAddressColumns,
+		request.Label, a.RecipientName, a.Phone, a.Street, a.City, a.Region, a.Country, a.ZipCode, request.IsDefault,
+		request.Id, request.UserId)
+	address, err := scanSavedAddress(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("address not found")
+		}
+		return nil, fmt.Errorf("error updating address: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %v", err)
+	}
+	return &pro.UpdateAddressResponse{Address: address}, nil
+}
+
+func (r *productRepo) DeleteAddress(c context.Context, request *pro.DeleteAddressRequest) (*pro.DeleteAddressResponse, error) {
+	res, err := r.db.ExecContext(c, `
+		UPDATE addresses SET deleted_at = now(), is_default = FALSE
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+	`, request.Id, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if n, err := res.RowsAffected(); n == 0 || err != nil {
+		return nil, fmt.Errorf("address not found")
+	}
+
+	return &pro.DeleteAddressResponse{}, nil
+}
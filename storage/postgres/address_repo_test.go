@@ -0,0 +1,124 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pro "product-service/genproto/product_service"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var savedAddressRowColumns = []string{"id", "user_id", "label", "recipient_name", "phone", "street", "city", "region", "country", "zip_code", "is_default", "created_at", "updated_at"}
+
+func TestAddDefaultAddress(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+	request := &pro.AddAddressRequest{
+		UserId:    "user-1",
+		Label:     "Home",
+		Address:   &pro.Address{Street: "Amir Temur 1", City: "Tashkent", Country: "UZ", ZipCode: "100000"},
+		IsDefault: true,
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE addresses SET is_default = FALSE").
+		WithArgs("user-1", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("INSERT INTO addresses (.+) RETURNING").
+		WithArgs(sqlmock.AnyArg(), "user-1", "Home", "", "", "Amir Temur 1", "Tashkent", "", "UZ", "100000", true).
+		WillReturnRows(sqlmock.NewRows(savedAddressRowColumns).
+			AddRow("addr-1", "user-1", "Home", "", "", "Amir Temur 1", "Tashkent", "", "UZ", "100000", true, now, now))
+	mock.ExpectCommit()
+
+	res, err := repo.AddAddress(context.Background(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, "addr-1", res.Address.Id)
+	assert.Equal(t, "Tashkent", res.Address.Address.City)
+	assert.True(t, res.Address.IsDefault)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAddresses(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+
+	now := time.Now().Format(time.RFC3339)
+	mock.ExpectQuery("SELECT (.+) FROM addresses WHERE user_id = (.+) ORDER BY is_default DESC").
+		WithArgs("user-1").
+		WillReturnRows(sqlmock.NewRows(savedAddressRowColumns).
+			AddRow("addr-1", "user-1", "Home", "Aziz", "+998901234567", "Amir Temur 1", "Tashkent", "", "UZ", "100000", true, now, now).
+			AddRow("addr-2", "user-1", "Office", "", "", "Navoi 5", "Samarkand", "", "UZ", "140100", false, now, now))
+
+	res, err := repo.GetAddresses(context.Background(), &pro.GetAddressesRequest{UserId: "user-1"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(res.Addresses))
+	assert.Equal(t, "Aziz", res.Addresses[0].Address.RecipientName)
+	assert.Equal(t, "Samarkand", res.Addresses[1].Address.City)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAddressOfOtherUser(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+
+	mock.ExpectQuery("SELECT (.+) FROM addresses WHERE id = (.+) AND user_id = (.+)").
+		WithArgs("addr-1", "user-2").
+		WillReturnRows(sqlmock.NewRows(savedAddressRowColumns))
+
+	_, err := repo.GetAddress(context.Background(), "addr-1", "user-2")
+	assert.EqualError(t, err, "address not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateAddressNotFound(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+	request := &pro.UpdateAddressRequest{
+		Id:      "addr-1",
+		UserId:  "user-2",
+		Label:   "Home",
+		Address: &pro.Address{Street: "Amir Temur 1", City: "Tashkent", Country: "UZ", ZipCode: "100000"},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("UPDATE addresses SET (.+) WHERE id = (.+) AND user_id = (.+) RETURNING").
+		WithArgs("Home", "", "", "Amir Temur 1", "Tashkent", "", "UZ", "100000", false, "addr-1", "user-2").
+		WillReturnRows(sqlmock.NewRows(savedAddressRowColumns))
+	mock.ExpectRollback()
+
+	_, err := repo.UpdateAddress(context.Background(), request)
+	assert.EqualError(t, err, "address not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteAddress(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+
+	mock.ExpectExec("UPDATE addresses SET deleted_at = now\\(\\), is_default = FALSE").
+		WithArgs("addr-1", "user-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	_, err := repo.DeleteAddress(context.Background(), &pro.DeleteAddressRequest{Id: "addr-1", UserId: "user-1"})
+	assert.NoError(t, err)
+
+	mock.ExpectExec("UPDATE addresses SET deleted_at = now\\(\\), is_default = FALSE").
+		WithArgs("addr-1", "user-2").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	_, err = repo.DeleteAddress(context.Background(), &pro.DeleteAddressRequest{Id: "addr-1", UserId: "user-2"})
+	assert.EqualError(t, err, "address not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"product-service/models"
+
+	"github.com/lib/pq"
+)
+
+// AddCartItem adds quantity of a product to the user's cart, on top of what
+// is already there.
+func (r *productRepo) AddCartItem(c context.Context, userId, productId string, quantity int32) error {
+	query := `
+		INSERT INTO cart_items (user_id, product_id, quantity)
+		SELECT $1, id, $3 FROM products WHERE id = $2 AND deleted_at IS NULL
+		ON CONFLICT (user_id, product_id) DO UPDATE
+		SET quantity = cart_items.quantity + EXCLUDED.quantity, updated_at = now()
+	`
+	res, err := r.db.ExecContext(c, query, userId, productId, quantity)
+	if err != nil {
+		return fmt.Errorf("error adding cart item: %v", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("error adding cart item: %v", err)
+	} else if n == 0 {
+		return fmt.Errorf("product not found")
+	}
+	return nil
+}
+
+// UpdateCartItem sets the quantity of a product already in the user's cart.
+func (r *productRepo) UpdateCartItem(c context.Context, userId, productId string, quantity int32) error {
+	query := `
+		UPDATE cart_items SET quantity = $1, updated_at = now()
+		WHERE user_id = $2 AND product_id = $3
+	`
+	res, err := r.db.ExecContext(c, query, quantity, userId, productId)
+	if err != nil {
+		return fmt.Errorf("error updating cart item: %v", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("error updating cart item: %v", err)
+	} else if n == 0 {
+		return fmt.Errorf("product is not in the cart")
+	}
+	return nil
+}
+
+// RemoveCartItem takes a product out of the user's cart.
+func (r *productRepo) RemoveCartItem(c context.Context, userId, productId string) error {
+	res, err := r.db.ExecContext(c, `DELETE FROM cart_items WHERE user_id = $1 AND product_id = $2`, userId, productId)
+	if err != nil {
+		return fmt.Errorf("error removing cart item: %v", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("error removing cart item: %v", err)
+	} else if n == 0 {
+		return fmt.Errorf("product is not in the cart")
+	}
+	return nil
+}
+
+// GetCartItems returns the user's cart with the current name, price and
+// stock of every product, oldest item first.
+func (r *productRepo) GetCartItems(c context.Context, userId string) ([]*models.CartItem, error) {
+	query := `
+		SELECT ci.product_id, p.name, ci.quantity, p.price, p.currency, p.quantity,
+		p.deleted_at IS NOT NULL, ci.created_at, ci.updated_at
+		FROM cart_items ci
+		JOIN products p ON p.id = ci.product_id
+		WHERE ci.user_id = $1
+		ORDER BY ci.created_at, ci.product_id
+	`
+	rows, err := r.db.QueryContext(c, query, userId)
+	if err != nil {
+		return nil, fmt.Errorf("error getting cart: %v", err)
+	}
+	defer rows.Close()
+
+	var items []*models.CartItem
+	for rows.Next() {
+		var item models.CartItem
+		err := rows.Scan(
+			&item.ProductId,
+			&item.ProductName,
+			&item.Quantity,
+			&item.Price,
+			&item.Currency,
+			&item.Available,
+			&item.Deleted,
+			&item.CreatedAt,
+			&item.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning cart item: %v", err)
+		}
+		items = append(items, &item)
+	}
+	return items, rows.Err()
+}
+
+// clearCartItems removes ordered products from the buyer's cart as part of
+// placing the order.
+func clearCartItems(c context.Context, tx *sql.Tx, userId string, productIds []string) error {
+	_, err := tx.ExecContext(c, `DELETE FROM cart_items WHERE user_id = $1 AND product_id = ANY($2)`, userId, pq.Array(productIds))
+	if err != nil {
+		return fmt.Errorf("error clearing cart: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pro "product-service/genproto/product_service"
+	"product-service/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddCartItem(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+	ctx := context.Background()
+
+	mock.ExpectExec("INSERT INTO cart_items (.+) ON CONFLICT").
+		WithArgs("user-1", "prod-1", int32(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.AddCartItem(ctx, "user-1", "prod-1", 2))
+
+	mock.ExpectExec("INSERT INTO cart_items").
+		WithArgs("user-1", "prod-gone", int32(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.EqualError(t, repo.AddCartItem(ctx, "user-1", "prod-gone", 1), "product not found")
+
+	mock.ExpectExec("UPDATE cart_items SET quantity").
+		WithArgs(int32(3), "user-1", "prod-2").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.EqualError(t, repo.UpdateCartItem(ctx, "user-1", "prod-2", 3), "product is not in the cart")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetCartItems(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT (.+) FROM cart_items ci JOIN products p").
+		WithArgs("user-1").
+		WillReturnRows(sqlmock.NewRows([]string{"product_id", "name", "quantity", "price", "currency", "available", "deleted", "created_at", "updated_at"}).
+			AddRow("prod-1", "Vase", 2, "19.99", "USD", 5, false, now, now).
+			AddRow("prod-2", "Rug", 3, "120.00", "EUR", 1, false, now, now).
+			AddRow("prod-3", "Bowl", 1, "9.00", "USD", 4, true, now, now))
+
+	items, err := repo.GetCartItems(ctx, "user-1")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(items))
+	assert.Equal(t, models.Money(1999), items[0].Price)
+	assert.Equal(t, "", items[0].Warning())
+	assert.Equal(t, models.CartWarningInsufficientStock, items[1].Warning())
+	assert.Equal(t, models.CartWarningUnavailable, items[2].Warning())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPlaceOrderFromCart(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+	ctx := context.Background()
+	order := &pro.PlaceOrderRequest{
+		UserId:   "user-1",
+		Status:   "pending",
+		Currency: "USD",
+		Address:  &pro.Address{Street: "1 Main St", City: "Austin", Region: "TX", Country: "US", ZipCode: "73301"},
+		Items:    []*pro.Item{{ProductId: "prod-1", Quantity: 2}},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO orders").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("SELECT name, description, price, currency, quantity, (.+) FROM products").
+		WithArgs("prod-1").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "description", "price", "currency", "quantity", "category_id", "artisan_id"}).
+			AddRow("Vase", "Clay vase", "19.99", "USD", 5, "", "artisan-1"))
+	mock.ExpectExec("INSERT INTO order_items").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE products SET quantity = quantity -").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT (.+) FROM tax_rules").
+		WithArgs("US").
+		WillReturnRows(sqlmock.NewRows(taxRuleRowColumns))
+	mock.ExpectExec("UPDATE orders SET total_amount").
+		WithArgs("44.98", "39.98", "0.00", "standard", "5.00", "0.00", "", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("DELETE FROM cart_items").
+		WithArgs("user-1", pq.Array([]string{"prod-1"})).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	checkout := &models.Checkout{
+		Shipping: &models.ShippingQuote{Method: "standard", Amount: 500, Currency: "USD"},
+		FromCart: true,
+	}
+	response, err := repo.PlaceOrder(ctx, order, checkout)
+	assert.NoError(t, err)
+	assert.Equal(t, 44.98, response.TotalPrice)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
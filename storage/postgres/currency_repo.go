@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	pro "product-service/genproto/product_service"
+	"product-service/models"
+	"sort"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// sortedExchangeRates lists checkout rates by base currency.
+func sortedExchangeRates(rates map[string]*models.ExchangeRate) []*models.ExchangeRate {
+	res := make([]*models.ExchangeRate, 0, len(rates))
+	for _, rate := range rates {
+		res = append(res, rate)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Base < res[j].Base })
+	return res
+}
+
+func exchangeRatesToProto(rates []*models.ExchangeRate) []*pro.ExchangeRate {
+	res := make([]*pro.ExchangeRate, 0, len(rates))
+	for _, rate := range rates {
+		res = append(res, &pro.ExchangeRate{
+			BaseCurrency:  rate.Base,
+			QuoteCurrency: rate.Quote,
+			Rate:          rate.Rate,
+			Source:        rate.Source,
+			AsOf:          rate.AsOf.Format(time.RFC3339),
+		})
+	}
+	return res
+}
+
+// insertOrderExchangeRates stores the rates an order was converted with.
+func insertOrderExchangeRates(c context.Context, tx *sql.Tx, orderId string, rates []*models.ExchangeRate) error {
+	for _, rate := range rates {
+		_, err := tx.ExecContext(c, `
+			INSERT INTO order_exchange_rates (order_id, base_currency, quote_currency, rate, source, as_of)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, orderId, rate.Base, rate.Quote, rate.Rate, rate.Source, rate.AsOf)
+		if err != nil {
+			return fmt.Errorf("error inserting exchange rate: %v", err)
+		}
+	}
+	return nil
+}
+
+// attachOrderExchangeRates loads the rates the given orders were converted
+// with at checkout.
+func (r *productRepo) attachOrderExchangeRates(c context.Context, orders []*pro.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+	byId := make(map[string]*pro.Order, len(orders))
+	orderIds := make([]string, 0, len(orders))
+	for _, order := range orders {
+		byId[order.Id] = order
+		orderIds = append(orderIds, order.Id)
+	}
+
+	rows, err := r.db.QueryContext(c, `
+		SELECT order_id, base_currency, quote_currency, rate::text, source, as_of
+		FROM order_exchange_rates
+		WHERE order_id = ANY($1)
+		ORDER BY base_currency
+	`, pq.Array(orderIds))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var orderId string
+		var rate models.ExchangeRate
+		if err := rows.Scan(&orderId, &rate.Base, &rate.Quote, &rate.Rate, &rate.Source, &rate.AsOf); err != nil {
+			return err
+		}
+		if order, ok := byId[orderId]; ok {
+			order.ExchangeRates = append(order.ExchangeRates, exchangeRatesToProto([]*models.ExchangeRate{&rate})...)
+		}
+	}
+	return rows.Err()
+}
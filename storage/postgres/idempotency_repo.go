@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"product-service/models"
+	"time"
+)
+
+// ReserveIdempotencyKey claims an idempotency key for a request. A key whose
+// reservation is older than expiredBefore is treated as free and taken over.
+// When the key is already held, the existing record is returned with
+// reserved set to false.
+func (r *productRepo) ReserveIdempotencyKey(c context.Context, key *models.IdempotencyKey, expiredBefore time.Time) (*models.IdempotencyKey, bool, error) {
+	query := `
+		INSERT INTO idempotency_keys (scope, key, request_hash, created_at)
+		VALUES ($1, $2, $3, now())
+		ON CONFLICT (scope, key) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash, response = NULL, created_at = now()
+		WHERE idempotency_keys.created_at < $4
+		RETURNING scope, key, request_hash, response
+	`
+	var reserved models.IdempotencyKey
+	err := r.db.GetContext(c, &reserved, query, key.Scope, key.Key, key.RequestHash, expiredBefore)
+	if err == nil {
+		return &reserved, true, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, false, err
+	}
+
+	var existing models.IdempotencyKey
+	err = r.db.GetContext(c, &existing, `
+		SELECT scope, key, request_hash, response
+		FROM idempotency_keys
+		WHERE scope = $1 AND key = $2
+	`, key.Scope, key.Key)
+	if err != nil {
+		return nil, false, err
+	}
+	return &existing, false, nil
+}
+
+// CompleteIdempotencyKey stores the serialized response of a finished request.
+func (r *productRepo) CompleteIdempotencyKey(c context.Context, key *models.IdempotencyKey) error {
+	_, err := r.db.ExecContext(c, `
+		UPDATE idempotency_keys SET response = $1 WHERE scope = $2 AND key = $3
+	`, key.Response, key.Scope, key.Key)
+	return err
+}
+
+// ReleaseIdempotencyKey removes a reservation whose request failed, so the
+// client can retry with the same key.
+func (r *productRepo) ReleaseIdempotencyKey(c context.Context, key *models.IdempotencyKey) error {
+	_, err := r.db.ExecContext(c, `
+		DELETE FROM idempotency_keys WHERE scope = $1 AND key = $2 AND response IS NULL
+	`, key.Scope, key.Key)
+	return err
+}
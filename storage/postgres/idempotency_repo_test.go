@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"product-service/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReserveIdempotencyKey(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+	ctx := context.Background()
+	key := &models.IdempotencyKey{Scope: "place_order", Key: "key-1", RequestHash: "hash-1"}
+	expiredBefore := time.Now().Add(-24 * time.Hour)
+
+	mock.ExpectQuery("INSERT INTO idempotency_keys").
+		WithArgs(key.Scope, key.Key, key.RequestHash, expiredBefore).
+		WillReturnRows(sqlmock.NewRows([]string{"scope", "key", "request_hash", "response"}).
+			AddRow(key.Scope, key.Key, key.RequestHash, nil))
+
+	record, reserved, err := repo.ReserveIdempotencyKey(ctx, key, expiredBefore)
+	assert.NoError(t, err)
+	assert.True(t, reserved)
+	assert.Equal(t, key.RequestHash, record.RequestHash)
+	assert.Nil(t, record.Response)
+
+	mock.ExpectQuery("INSERT INTO idempotency_keys").
+		WithArgs(key.Scope, key.Key, key.RequestHash, expiredBefore).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("SELECT scope, key, request_hash, response FROM idempotency_keys").
+		WithArgs(key.Scope, key.Key).
+		WillReturnRows(sqlmock.NewRows([]string{"scope", "key", "request_hash", "response"}).
+			AddRow(key.Scope, key.Key, key.RequestHash, []byte("stored")))
+
+	record, reserved, err = repo.ReserveIdempotencyKey(ctx, key, expiredBefore)
+	assert.NoError(t, err)
+	assert.False(t, reserved)
+	assert.Equal(t, []byte("stored"), record.Response)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
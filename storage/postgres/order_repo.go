@@ -0,0 +1,291 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	pro "product-service/genproto/product_service"
+	"product-service/models"
+	"strconv"
+
+	"github.com/lib/pq"
+)
+
+// orderColumns is the column list scanned by scanOrder. It must be selected
+// from orders without an alias.
+const orderColumns = `id, user_id, total_amount, subtotal_amount, tax_amount,
+		COALESCE(shipping_method, ''), shipping_amount, discount_amount, COALESCE(coupon_code, ''),
+		currency, status, shipping_address,
+		COALESCE(tracking_number, ''), COALESCE(carrier, ''), COALESCE(estimated_delivery_date::text, ''),
+		created_at, updated_at,
+		(SELECT COALESCE(SUM(rf.amount), 0) FROM refunds rf
+			WHERE rf.order_id = orders.id AND rf.status = 'completed' AND rf.deleted_at IS NULL)`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanOrder(row rowScanner) (*pro.Order, error) {
+	var order pro.Order
+	var shippingAddress []byte
+	err := row.Scan(
+		&order.Id,
+		&order.UserId,
+		&order.TotalAmount,
+		&order.SubtotalAmount,
+		&order.TaxAmount,
+		&order.ShippingMethod,
+		&order.ShippingAmount,
+		&order.DiscountAmount,
+		&order.CouponCode,
+		&order.Currency,
+		&order.Status,
+		&shippingAddress,
+		&order.TrackingNumber,
+		&order.Carrier,
+		&order.EstimatedDeliveryDate,
+		&order.CreatedAt,
+		&order.UpdatedAt,
+		&order.RefundedAmount,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	order.ShippingAddress = string(shippingAddress)
+	var address models.ShippingAddress
+	if err := json.Unmarshal(shippingAddress, &address); err != nil {
+		return nil, fmt.Errorf("error parsing shipping address: %v", err)
+	}
+	order.Address = shippingAddressToProto(address)
+	return &order, nil
+}
+
+// attachOrderItems loads the line items of the given orders, using the product
+// snapshot taken at checkout when it is available.
+func (r *productRepo) attachOrderItems(c context.Context, orders []*pro.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+	byId := make(map[string]*pro.Order, len(orders))
+	orderIds := make([]string, 0, len(orders))
+	for _, order := range orders {
+		byId[order.Id] = order
+		orderIds = append(orderIds, order.Id)
+	}
+
+	rows, err := r.db.QueryContext(c, `
+		SELECT oi.id, oi.order_id, oi.product_id,
+		COALESCE(oi.product_name, p.name, ''), COALESCE(oi.product_description, p.description, ''),
+		oi.quantity, oi.price, COALESCE(oi.original_price, oi.price), COALESCE(oi.original_currency, '')
+		FROM order_items oi
+		LEFT JOIN products p ON p.id = oi.product_id
+		WHERE oi.order_id = ANY($1) AND oi.deleted_at IS NULL
+		ORDER BY oi.created_at
+	`, pq.Array(orderIds))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item pro.OrderItem
+		var orderId string
+		if err := rows.Scan(&item.Id, &orderId, &item.ProductId, &item.ProductName, &item.ProductDescription, &item.Quantity, &item.Price,
+			&item.OriginalPrice, &item.OriginalCurrency); err != nil {
+			return err
+		}
+		if order, ok := byId[orderId]; ok {
+			order.Items = append(order.Items, &item)
+		}
+	}
+	return rows.Err()
+}
+
+// attachOrderPayments loads the payments made against the given orders.
+func (r *productRepo) attachOrderPayments(c context.Context, orders []*pro.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+	byId := make(map[string]*pro.Order, len(orders))
+	orderIds := make([]string, 0, len(orders))
+	for _, order := range orders {
+		byId[order.Id] = order
+		orderIds = append(orderIds, order.Id)
+	}
+
+	rows, err := r.db.QueryContext(c, `
+		SELECT `+paymentColumns+`
+		FROM payments
+		WHERE order_id = ANY($1) AND deleted_at IS NULL
+		ORDER BY created_at
+	`, pq.Array(orderIds))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		payment, err := scanPayment(rows)
+		if err != nil {
+			return err
+		}
+		if order, ok := byId[payment.OrderId]; ok {
+			order.Payments = append(order.Payments, payment)
+		}
+	}
+	return rows.Err()
+}
+
+// ListArtisanOrders returns the orders that contain at least one product of the
+// given artisan. Only the artisan's own line items are attached to each order.
+func (r *productRepo) ListArtisanOrders(c context.Context, request *pro.ListArtisanOrdersRequest) (*pro.ListArtisanOrdersResponse, error) {
+	query := `
+		SELECT DISTINCT o.id, o.user_id, o.status, o.shipping_address, o.currency, o.created_at, o.updated_at
+		FROM orders o
+		JOIN order_items oi ON oi.order_id = o.id AND oi.deleted_at IS NULL
+		JOIN products p ON p.id = oi.product_id
+		WHERE p.artisan_id = $1 AND o.deleted_at IS NULL
+	`
+	args := []interface{}{request.ArtisanId}
+	if request.Status != "" {
+		args = append(args, request.Status)
+		query += fmt.Sprintf(" AND o.status = $%d", len(args))
+	}
+	if request.FromDate != "" {
+		args = append(args, request.FromDate)
+		query += fmt.Sprintf(" AND o.created_at >= $%d::date", len(args))
+	}
+	if request.ToDate != "" {
+		args = append(args, request.ToDate)
+		query += fmt.Sprintf(" AND o.created_at < $%d::date + INTERVAL '1 day'", len(args))
+	}
+
+	limit, _ := strconv.Atoi(request.Limit)
+	page, _ := strconv.Atoi(request.Page)
+	offset := (page - 1) * limit
+	args = append(args, limit, offset)
+	query += fmt.Sprintf(" ORDER BY o.created_at DESC LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(c, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orders []*pro.ArtisanOrder
+	byId := make(map[string]*pro.ArtisanOrder)
+	var orderIds []string
+	for rows.Next() {
+		var order pro.ArtisanOrder
+		if err := rows.Scan(&order.Id, &order.UserId, &order.Status, &order.ShippingAddress, &order.Currency, &order.CreatedAt, &order.UpdatedAt); err != nil {
+			return nil, err
+		}
+		orders = append(orders, &order)
+		byId[order.Id] = &order
+		orderIds = append(orderIds, order.Id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(orders) == 0 {
+		return &pro.ListArtisanOrdersResponse{}, nil
+	}
+
+	itemRows, err := r.db.QueryContext(c, `
+		SELECT oi.id, oi.order_id, oi.product_id, COALESCE(oi.product_name, p.name), oi.quantity, oi.price
+		FROM order_items oi
+		JOIN products p ON p.id = oi.product_id
+		WHERE oi.order_id = ANY($1) AND p.artisan_id = $2 AND oi.deleted_at IS NULL
+	`, pq.Array(orderIds), request.ArtisanId)
+	if err != nil {
+		return nil, err
+	}
+	defer itemRows.Close()
+
+	totals := make(map[string]models.Money, len(orders))
+
+	for itemRows.Next() {
+		var item pro.OrderItem
+		var orderId string
+		var price models.Money
+		if err := itemRows.Scan(&item.Id, &orderId, &item.ProductId, &item.ProductName, &item.Quantity, &price); err != nil {
+			return nil, err
+		}
+		order, ok := byId[orderId]
+		if !ok {
+			continue
+		}
+		item.Price = price.Float64()
+		order.Items = append(order.Items, &item)
+		totals[orderId] += price.Mul(item.Quantity)
+	}
+	if err := itemRows.Err(); err != nil {
+		return nil, err
+	}
+	for id, total := range totals {
+		byId[id].TotalAmount = total.Float64()
+	}
+
+	return &pro.ListArtisanOrdersResponse{Orders: orders}, nil
+}
+
+// ListMyOrders returns the order history of a buyer, newest first, with line
+// items, payments, checkout exchange rates and tax lines attached.
+func (r *productRepo) ListMyOrders(c context.Context, request *pro.ListMyOrdersRequest) (*pro.ListMyOrdersResponse, error) {
+	query := `
+		SELECT ` + orderColumns + `
+		FROM orders
+		WHERE user_id = $1 AND deleted_at IS NULL
+	`
+	args := []interface{}{request.UserId}
+	if request.Status != "" {
+		args = append(args, request.Status)
+		query += fmt.Sprintf(" AND status = $%d", len(args))
+	}
+
+	limit, _ := strconv.Atoi(request.Limit)
+	page, _ := strconv.Atoi(request.Page)
+	offset := (page - 1) * limit
+	args = append(args, limit, offset)
+	query += fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(c, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orders []*pro.Order
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.attachOrderItems(c, orders); err != nil {
+		return nil, err
+	}
+	if err := r.attachOrderPayments(c, orders); err != nil {
+		return nil, err
+	}
+	if err := r.attachOrderExchangeRates(c, orders); err != nil {
+		return nil, err
+	}
+	if err := r.attachOrderTaxLines(c, orders); err != nil {
+		return nil, err
+	}
+	if err := r.attachOrderDiscountLines(c, orders); err != nil {
+		return nil, err
+	}
+	if err := r.attachOrderShipments(c, orders); err != nil {
+		return nil, err
+	}
+
+	return &pro.ListMyOrdersResponse{Orders: orders}, nil
+}
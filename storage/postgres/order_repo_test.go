@@ -0,0 +1,249 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pro "product-service/genproto/product_service"
+	"product-service/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListArtisanOrders(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+	ctx := context.Background()
+	request := &pro.ListArtisanOrdersRequest{
+		ArtisanId: "art-1",
+		Status:    "pending",
+		FromDate:  "2024-07-01",
+		Limit:     "10",
+		Page:      "1",
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	mock.ExpectQuery("SELECT DISTINCT o.id, o.user_id, o.status, o.shipping_address, o.currency, o.created_at, o.updated_at FROM orders o").
+		WithArgs(request.ArtisanId, request.Status, request.FromDate, 10, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "status", "shipping_address", "currency", "created_at", "updated_at"}).
+			AddRow("order-1", "user-1", "pending", `{"street":"Main"}`, "EUR", now, now))
+
+	mock.ExpectQuery("SELECT oi.id, oi.order_id, oi.product_id, (.+), oi.quantity, oi.price FROM order_items oi").
+		WithArgs(sqlmock.AnyArg(), request.ArtisanId).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "product_id", "name", "quantity", "price"}).
+			AddRow("item-1", "order-1", "prod-1", "Vase", 2, 10.5).
+			AddRow("item-2", "order-1", "prod-2", "Bowl", 1, 4.0))
+
+	response, err := repo.ListArtisanOrders(ctx, request)
+	assert.NoError(t, err)
+	assert.NotNil(t, response)
+	assert.Equal(t, 1, len(response.Orders))
+	assert.Equal(t, "order-1", response.Orders[0].Id)
+	assert.Equal(t, 2, len(response.Orders[0].Items))
+	assert.Equal(t, "Vase", response.Orders[0].Items[0].ProductName)
+	assert.Equal(t, 25.0, response.Orders[0].TotalAmount)
+	assert.Equal(t, "EUR", response.Orders[0].Currency)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetOrder(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+	ctx := context.Background()
+	request := &pro.GetOrderRequest{Id: "order-1", UserId: "user-1"}
+
+	now := time.Now().Format(time.RFC3339)
+	mock.ExpectQuery("SELECT (.+) FROM orders WHERE id = (.+) AND user_id = (.+)").
+		WithArgs(request.Id, request.UserId).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "total_amount", "subtotal_amount", "tax_amount", "shipping_method", "shipping_amount", "discount_amount", "coupon_code", "currency", "status", "shipping_address", "tracking_number", "carrier", "estimated_delivery_date", "created_at", "updated_at", "refunded_amount"}).
+			AddRow("order-1", "user-1", 25.0, 20.0, 3.99, "express", 5.0, 0.0, "", "EUR", "shipped", `{"street":"Main 1","city":"Tashkent","country":"UZ","zip_code":"100000"}`, "TRK-1", "dhl", "", now, now, 5.0))
+
+	mock.ExpectQuery("SELECT oi.id, oi.order_id, oi.product_id, (.+) FROM order_items oi").
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "product_id", "product_name", "product_description", "quantity", "price", "original_price", "original_currency"}).
+			AddRow("item-1", "order-1", "prod-1", "Vase", "Clay vase", 2, 12.5, 1000, "USD"))
+
+	mock.ExpectQuery("SELECT id, order_id, amount, status, (.+) FROM payments").
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "amount", "status", "transaction_id", "payment_method", "created_at", "provider", "updated_at", "currency"}).
+			AddRow("pay-1", "order-1", 25.0, "captured", "tx-1", "credit-card", now, "fake", now, "EUR"))
+
+	mock.ExpectQuery("SELECT order_id, base_currency, quote_currency, (.+) FROM order_exchange_rates").
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"order_id", "base_currency", "quote_currency", "rate", "source", "as_of"}).
+			AddRow("order-1", "USD", "EUR", "1.25", "static", time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)))
+
+	mock.ExpectQuery("SELECT tl.order_id, tl.order_item_id, (.+) FROM order_tax_lines tl").
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"order_id", "order_item_id", "product_id", "rule_id", "name", "rate", "inclusive", "taxable_amount", "amount"}).
+			AddRow("order-1", "item-1", "prod-1", "rule-1", "MwSt", "0.190000", true, "21.01", "3.99"))
+
+	mock.ExpectQuery("SELECT dl.order_id, dl.order_item_id, (.+) FROM order_discount_lines dl").
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"order_id", "order_item_id", "product_id", "promotion_id", "code", "name", "amount"}))
+
+	mock.ExpectQuery("SELECT id, order_id, carrier, (.+) FROM shipments").
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "carrier", "tracking_number", "status", "estimated_delivery_date", "delivered_at", "created_at", "updated_at"}).
+			AddRow("ship-1", "order-1", "dhl", "TRK-1", "in_transit", "", nil, now, now))
+
+	mock.ExpectQuery("SELECT id, shipment_id, status, (.+) FROM tracking_events").
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "shipment_id", "status", "location", "description", "occurred_at", "created_at"}).
+			AddRow("event-1", "ship-1", "in_transit", "Leipzig", "Departed hub", time.Date(2024, 7, 2, 8, 0, 0, 0, time.UTC), now))
+
+	response, err := repo.GetOrder(ctx, request)
+	assert.NoError(t, err)
+	assert.NotNil(t, response)
+	assert.Equal(t, "order-1", response.Order.Id)
+	assert.Equal(t, "Tashkent", response.Order.Address.City)
+	assert.Equal(t, "100000", response.Order.Address.ZipCode)
+	assert.Equal(t, "TRK-1", response.Order.TrackingNumber)
+	assert.Equal(t, 5.0, response.Order.RefundedAmount)
+	assert.Equal(t, 1, len(response.Order.Items))
+	assert.Equal(t, "Clay vase", response.Order.Items[0].ProductDescription)
+	assert.Equal(t, "EUR", response.Order.Currency)
+	assert.Equal(t, 1000.0, response.Order.Items[0].OriginalPrice)
+	assert.Equal(t, "USD", response.Order.Items[0].OriginalCurrency)
+	assert.Equal(t, 1, len(response.Order.ExchangeRates))
+	assert.Equal(t, "1.25", response.Order.ExchangeRates[0].Rate)
+	assert.Equal(t, "2024-07-01T00:00:00Z", response.Order.ExchangeRates[0].AsOf)
+	assert.Equal(t, 3.99, response.Order.TaxAmount)
+	assert.Equal(t, "express", response.Order.ShippingMethod)
+	assert.Equal(t, 5.0, response.Order.ShippingAmount)
+	assert.Equal(t, 1, len(response.Order.TaxLines))
+	assert.Equal(t, "MwSt", response.Order.TaxLines[0].Name)
+	assert.Equal(t, 1, len(response.Order.Shipments))
+	assert.Equal(t, 1, len(response.Order.Shipments[0].Events))
+	assert.Equal(t, "2024-07-02T08:00:00Z", response.Order.Shipments[0].Events[0].OccurredAt)
+	assert.Equal(t, 21.01, response.Order.TaxLines[0].TaxableAmount)
+	assert.Equal(t, 1, len(response.Order.Payments))
+	assert.Equal(t, "pay-1", response.Order.Payments[0].Id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetOrderOfOtherUser(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+
+	mock.ExpectQuery("SELECT (.+) FROM orders WHERE id = (.+) AND user_id = (.+)").
+		WithArgs("order-1", "user-2").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := repo.GetOrder(context.Background(), &pro.GetOrderRequest{Id: "order-1", UserId: "user-2"})
+	assert.EqualError(t, err, "order not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListMyOrders(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+	request := &pro.ListMyOrdersRequest{UserId: "user-1", Status: "delivered", Limit: "10", Page: "2"}
+
+	now := time.Now().Format(time.RFC3339)
+	mock.ExpectQuery("SELECT (.+) FROM orders WHERE user_id = (.+) AND status = (.+) ORDER BY created_at DESC LIMIT (.+) OFFSET").
+		WithArgs("user-1", "delivered", 10, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "total_amount", "subtotal_amount", "tax_amount", "shipping_method", "shipping_amount", "discount_amount", "coupon_code", "currency", "status", "shipping_address", "tracking_number", "carrier", "estimated_delivery_date", "created_at", "updated_at", "refunded_amount"}).
+			AddRow("order-1", "user-1", 25.0, 20.0, 0.0, "standard", 5.0, 0.0, "", "EUR", "delivered", `{"city":"Tashkent"}`, "TRK-1", "dhl", "", now, now, 0.0))
+
+	mock.ExpectQuery("SELECT oi.id, oi.order_id, oi.product_id, (.+) FROM order_items oi").
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "product_id", "product_name", "product_description", "quantity", "price", "original_price", "original_currency"}).
+			AddRow("item-1", "order-1", "prod-1", "Vase", "Clay vase", 2, 10.0, 10.0, "EUR"))
+	mock.ExpectQuery("SELECT id, order_id, amount, status, (.+) FROM payments").
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "amount", "status", "transaction_id", "payment_method", "created_at", "provider", "updated_at", "currency"}))
+	mock.ExpectQuery("SELECT order_id, base_currency, quote_currency, (.+) FROM order_exchange_rates").
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"order_id", "base_currency", "quote_currency", "rate", "source", "as_of"}))
+	mock.ExpectQuery("SELECT tl.order_id, tl.order_item_id, (.+) FROM order_tax_lines tl").
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"order_id", "order_item_id", "product_id", "rule_id", "name", "rate", "inclusive", "taxable_amount", "amount"}))
+	mock.ExpectQuery("SELECT dl.order_id, dl.order_item_id, (.+) FROM order_discount_lines dl").
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"order_id", "order_item_id", "product_id", "promotion_id", "code", "name", "amount"}))
+	mock.ExpectQuery("SELECT id, order_id, carrier, (.+) FROM shipments").
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "carrier", "tracking_number", "status", "estimated_delivery_date", "delivered_at", "created_at", "updated_at"}))
+
+	response, err := repo.ListMyOrders(context.Background(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(response.Orders))
+	assert.Equal(t, "order-1", response.Orders[0].Id)
+	assert.Equal(t, "Tashkent", response.Orders[0].Address.City)
+	assert.Equal(t, 1, len(response.Orders[0].Items))
+	assert.Equal(t, "Vase", response.Orders[0].Items[0].ProductName)
+	assert.Equal(t, 0, len(response.Orders[0].Shipments))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPlaceOrderStockTakenConcurrently(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+	order := &pro.PlaceOrderRequest{
+		UserId:   "user-1",
+		Currency: "USD",
+		Items:    []*pro.Item{{ProductId: "prod-1", Quantity: 2}},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO orders").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("SELECT name, description, price, currency, quantity, (.+) FROM products").
+		WithArgs("prod-1").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "description", "price", "currency", "quantity", "category_id", "artisan_id"}).
+			AddRow("Vase", "Clay vase", "19.99", "USD", 2, "cat-pottery", "artisan-1"))
+	mock.ExpectExec("INSERT INTO order_items").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE products SET quantity = quantity - (.+) WHERE id = (.+) AND quantity >= ").
+		WithArgs(int32(2), "prod-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	_, err := repo.PlaceOrder(context.Background(), order, &models.Checkout{})
+	assert.EqualError(t, err, "not enough stock for item prod-1")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCancelOrderRestocks(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE orders SET status = 'canceled'").
+		WithArgs("order-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE products p SET quantity = p.quantity \\+ oi.quantity").
+		WithArgs("order-1").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("UPDATE promotions SET used_count = used_count - 1(.+) FROM order_discount_lines WHERE order_id = \\$1").
+		WithArgs("order-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	res, err := repo.CancelOrder(context.Background(), &pro.CancelOrderRequest{Id: "order-1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "canceled", res.Status)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE orders SET status = 'canceled'").
+		WithArgs("order-2").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	_, err = repo.CancelOrder(context.Background(), &pro.CancelOrderRequest{Id: "order-2"})
+	assert.EqualError(t, err, "order not found or no longer pending")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,127 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	pro "product-service/genproto/product_service"
+	"product-service/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const paymentMethodColumns = `id, user_id, provider, COALESCE(brand, ''), COALESCE(last4, ''),
+		COALESCE(exp_month, 0), COALESCE(exp_year, 0), is_default, created_at`
+
+func scanPaymentMethod(row rowScanner) (*pro.PaymentMethod, error) {
+	var method pro.PaymentMethod
+	err := row.Scan(
+		&method.Id,
+		&method.UserId,
+		&method.Provider,
+		&method.Brand,
+		&method.Last4,
+		&method.ExpMonth,
+		&method.ExpYear,
+		&method.IsDefault,
+		&method.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &method, nil
+}
+
+func (r *productRepo) AddPaymentMethod(c context.Context, method *models.PaymentMethod) (*pro.AddPaymentMethodResponse, error) {
+	tx, err := r.db.BeginTxx(c, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	newId := uuid.NewString()
+	if method.IsDefault {
+		_, err := tx.ExecContext(c, `
+			UPDATE payment_methods SET is_default = FALSE, updated_at = now()
+			WHERE user_id = $1 AND is_default AND deleted_at IS NULL
+		`, method.UserId)
+		if err != nil {
+			return nil, fmt.Errorf("error updating default payment method: %v", err)
+		}
+	}
+
+	row := tx.QueryRowContext(c, `
+		INSERT INTO payment_methods (id, user_id, provider, token, brand, last4, exp_month, exp_year, is_default)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING `+paymentMethodColumns,
+		newId, method.UserId, method.Provider, method.Token, method.Brand, method.Last4, method.ExpMonth, method.ExpYear, method.IsDefault)
+	created, err := scanPaymentMethod(row)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return nil, fmt.Errorf("payment method is already saved")
+		}
+		return nil, fmt.Errorf("error inserting payment method: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %v", err)
+	}
+	return &pro.AddPaymentMethodResponse{PaymentMethod: created}, nil
+}
+
+func (r *productRepo) GetPaymentMethods(c context.Context, request *pro.GetPaymentMethodsRequest) (*pro.GetPaymentMethodsResponse, error) {
+	rows, err := r.db.QueryContext(c, `
+		SELECT `+paymentMethodColumns+`
+		FROM payment_methods
+		WHERE user_id = $1 AND deleted_at IS NULL
+		ORDER BY is_default DESC, created_at
+	`, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var methods []*pro.PaymentMethod
+	for rows.Next() {
+		method, err := scanPaymentMethod(rows)
+		if err != nil {
+			return nil, err
+		}
+		methods = append(methods, method)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &pro.GetPaymentMethodsResponse{PaymentMethods: methods}, nil
+}
+
+// GetPaymentMethodToken returns the provider token of a saved payment method
+// of the user, or an error when it does not exist or belongs to someone else.
+func (r *productRepo) GetPaymentMethodToken(c context.Context, id, userId string) (string, error) {
+	var token string
+	err := r.db.QueryRowContext(c, `
+		SELECT token FROM payment_methods
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+	`, id, userId).Scan(&token)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("payment method not found")
+	}
+	return token, err
+}
+
+func (r *productRepo) DeletePaymentMethod(c context.Context, request *pro.DeletePaymentMethodRequest) (*pro.DeletePaymentMethodResponse, error) {
+	res, err := r.db.ExecContext(c, `
+		UPDATE payment_methods SET deleted_at = now(), is_default = FALSE
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+	`, request.Id, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if n, err := res.RowsAffected(); n == 0 || err != nil {
+		return nil, fmt.Errorf("payment method not found")
+	}
+
+	return &pro.DeletePaymentMethodResponse{}, nil
+}
@@ -0,0 +1,298 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	pro "product-service/genproto/product_service"
+	"product-service/models"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const paymentColumns = `id, order_id, amount, status, COALESCE(transaction_id, ''), payment_method,
+		created_at, COALESCE(provider, ''), updated_at, currency`
+
+func scanPayment(row rowScanner) (*pro.Payment, error) {
+	var payment pro.Payment
+	err := row.Scan(
+		&payment.Id,
+		&payment.OrderId,
+		&payment.Amount,
+		&payment.Status,
+		&payment.TransactionId,
+		&payment.PaymentMethod,
+		&payment.CreatedAt,
+		&payment.Provider,
+		&payment.UpdatedAt,
+		&payment.Currency,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// insertProviderResponses stores the raw gateway responses of a payment.
+func insertProviderResponses(c context.Context, tx *sqlx.Tx, paymentId string, responses []*models.ProviderResponse) error {
+	for _, res := range responses {
+		var raw interface{}
+		if len(res.Response) > 0 {
+			raw = res.Response
+		}
+		_, err := tx.ExecContext(c, `
+			INSERT INTO payment_provider_responses (
+			id, payment_id, provider, operation, approved, transaction_id, amount, decline_code, response)
+			VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, NULLIF($8, ''), $9)
+		`,
+			uuid.NewString(),
+			paymentId,
+			res.Provider,
+			res.Operation,
+			res.Approved,
+			res.TransactionId,
+			res.Amount,
+			res.DeclineCode,
+			raw,
+		)
+		if err != nil {
+			return fmt.Errorf("error inserting provider response: %v", err)
+		}
+	}
+	return nil
+}
+
+func insertPaymentHistory(c context.Context, tx *sqlx.Tx, paymentId, from, to, reason string) error {
+	_, err := tx.ExecContext(c, `
+		INSERT INTO payment_status_history (id, payment_id, from_status, to_status, reason)
+		VALUES ($1, $2, NULLIF($3, ''), $4, NULLIF($5, ''))
+	`, uuid.NewString(), paymentId, from, to, reason)
+	if err != nil {
+		return fmt.Errorf("error inserting payment history: %v", err)
+	}
+	return nil
+}
+
+// activePaymentStatuses are the statuses of a payment that has not failed.
+// An order can have at most one payment in one of them.
+var activePaymentStatuses = []string{
+	models.PaymentPending,
+	models.PaymentAuthorized,
+	models.PaymentCaptured,
+	models.PaymentPartiallyRefunded,
+	models.PaymentRefunded,
+}
+
+// CreatePayment records a new pending payment for the order total, in the
+// order currency. The order must still be pending and have no other payment
+// that has not failed. A payment still pending from before staleBefore never
+// heard back from the provider; it is failed so the order can be paid again.
+// When payment.Amount is set it must match the order total.
+func (r *productRepo) CreatePayment(c context.Context, payment *models.Payment, staleBefore time.Time) (*pro.Payment, error) {
+	tx, err := r.db.BeginTxx(c, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var total models.Money
+	var currency, status string
+	err = tx.QueryRowContext(c, `
+		SELECT total_amount, currency, status FROM orders WHERE id = $1 AND deleted_at IS NULL FOR UPDATE
+	`, payment.OrderId).Scan(&total, &currency, &status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("order not found")
+		}
+		return nil, err
+	}
+	if status != "pending" {
+		return nil, fmt.Errorf("order is %s and cannot be paid", status)
+	}
+	if payment.Amount != 0 && payment.Amount != total {
+		return nil, fmt.Errorf("payment amount %s does not match order total %s", payment.Amount, total)
+	}
+	if err := r.expirePendingPayments(c, tx, payment.OrderId, staleBefore); err != nil {
+		return nil, err
+	}
+
+	var existing string
+	err = tx.QueryRowContext(c, `
+		SELECT status FROM payments
+		WHERE order_id = $1 AND status = ANY($2) AND deleted_at IS NULL
+		LIMIT 1
+	`, payment.OrderId, pq.Array(activePaymentStatuses)).Scan(&existing)
+	if err == nil {
+		return nil, fmt.Errorf("order already has a %s payment", existing)
+	}
+	if err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	newPaymentId := uuid.NewString()
+	created, err := scanPayment(tx.QueryRowContext(c, `
+		INSERT INTO payments (id, order_id, amount, currency, status, payment_method, provider)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING `+paymentColumns,
+		newPaymentId,
+		payment.OrderId,
+		total,
+		currency,
+		models.PaymentPending,
+		payment.PaymentMethod,
+		payment.Provider,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("error inserting payment: %v", err)
+	}
+
+	if err := insertPaymentHistory(c, tx, newPaymentId, "", models.PaymentPending, ""); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %v", err)
+	}
+	return created, nil
+}
+
+// expirePendingPayments fails the payments of an order that are still
+// pending from before staleBefore.
+func (r *productRepo) expirePendingPayments(c context.Context, tx *sqlx.Tx, orderId string, staleBefore time.Time) error {
+	var stale []string
+	err := tx.SelectContext(c, &stale, `
+		SELECT id FROM payments
+		WHERE order_id = $1 AND status = 'pending' AND updated_at < $2 AND deleted_at IS NULL
+		FOR UPDATE
+	`, orderId, staleBefore)
+	if err != nil {
+		return fmt.Errorf("error loading pending payments: %v", err)
+	}
+	for _, id := range stale {
+		_, err := r.transitionPayment(c, tx, &models.PaymentTransition{
+			PaymentId: id,
+			To:        models.PaymentFailed,
+			Reason:    "pending payment expired",
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TransitionPayment moves a payment to a new status. The move is rejected
+// unless models.ValidatePaymentTransition allows it from the current status.
+func (r *productRepo) TransitionPayment(c context.Context, transition *models.PaymentTransition) (*pro.Payment, error) {
+	tx, err := r.db.BeginTxx(c, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	payment, err := r.transitionPayment(c, tx, transition)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %v", err)
+	}
+	return payment, nil
+}
+
+func (r *productRepo) transitionPayment(c context.Context, tx *sqlx.Tx, transition *models.PaymentTransition) (*pro.Payment, error) {
+	var from string
+	err := tx.QueryRowContext(c, `
+		SELECT status FROM payments WHERE id = $1 AND deleted_at IS NULL FOR UPDATE
+	`, transition.PaymentId).Scan(&from)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("payment not found")
+		}
+		return nil, err
+	}
+	if err := models.ValidatePaymentTransition(from, transition.To); err != nil {
+		return nil, err
+	}
+
+	payment, err := scanPayment(tx.QueryRowContext(c, `
+		UPDATE payments
+		SET status = $1, transaction_id = COALESCE(NULLIF($2, ''), transaction_id), updated_at = now()
+		WHERE id = $3
+		RETURNING `+paymentColumns,
+		transition.To, transition.TransactionId, transition.PaymentId))
+	if err != nil {
+		return nil, fmt.Errorf("error updating payment: %v", err)
+	}
+
+	if err := insertPaymentHistory(c, tx, transition.PaymentId, from, transition.To, transition.Reason); err != nil {
+		return nil, err
+	}
+	if err := insertProviderResponses(c, tx, transition.PaymentId, transition.Responses); err != nil {
+		return nil, err
+	}
+
+	if transition.OrderStatus != "" {
+		res, err := tx.ExecContext(c, `
+			UPDATE orders SET status = $1, updated_at = now()
+			WHERE id = $2 AND status = 'pending' AND deleted_at IS NULL
+		`, transition.OrderStatus, payment.OrderId)
+		if err != nil {
+			return nil, fmt.Errorf("error updating order status: %v", err)
+		}
+		if n, err := res.RowsAffected(); n == 0 || err != nil {
+			return nil, fmt.Errorf("order %s is no longer pending", payment.OrderId)
+		}
+	}
+	return payment, nil
+}
+
+func (r *productRepo) getPaymentHistory(c context.Context, paymentId string) ([]*pro.PaymentStatusChange, error) {
+	rows, err := r.db.QueryContext(c, `
+		SELECT COALESCE(from_status, ''), to_status, COALESCE(reason, ''), created_at
+		FROM payment_status_history
+		WHERE payment_id = $1
+		ORDER BY created_at
+	`, paymentId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []*pro.PaymentStatusChange
+	for rows.Next() {
+		var change pro.PaymentStatusChange
+		if err := rows.Scan(&change.FromStatus, &change.ToStatus, &change.Reason, &change.CreatedAt); err != nil {
+			return nil, err
+		}
+		history = append(history, &change)
+	}
+	return history, rows.Err()
+}
+
+func (r *productRepo) getPaymentAttempts(c context.Context, paymentId string) ([]*pro.PaymentAttempt, error) {
+	rows, err := r.db.QueryContext(c, `
+		SELECT operation, approved, COALESCE(transaction_id, ''), amount, COALESCE(decline_code, ''), created_at
+		FROM payment_provider_responses
+		WHERE payment_id = $1
+		ORDER BY created_at
+	`, paymentId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attempts []*pro.PaymentAttempt
+	for rows.Next() {
+		var attempt pro.PaymentAttempt
+		if err := rows.Scan(&attempt.Operation, &attempt.Approved, &attempt.TransactionId, &attempt.Amount, &attempt.DeclineCode, &attempt.CreatedAt); err != nil {
+			return nil, err
+		}
+		attempts = append(attempts, &attempt)
+	}
+	return attempts, rows.Err()
+}
@@ -0,0 +1,187 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pro "product-service/genproto/product_service"
+	"product-service/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransitionPayment(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+	ctx := context.Background()
+	now := time.Now().Format(time.RFC3339)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT status FROM payments").
+		WithArgs("pay-1").
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("authorized"))
+	mock.ExpectQuery("UPDATE payments").
+		WithArgs(models.PaymentCaptured, "", "pay-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "amount", "status", "transaction_id", "payment_method", "created_at", "provider", "updated_at", "currency"}).
+			AddRow("pay-1", "order-1", 20.0, "captured", "fake_000001", "credit-card", now, "fake", now, "USD"))
+	mock.ExpectExec("INSERT INTO payment_status_history").
+		WithArgs(sqlmock.AnyArg(), "pay-1", "authorized", "captured", "").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO payment_provider_responses").
+		WithArgs(sqlmock.AnyArg(), "pay-1", "fake", "capture", true, "fake_000001", "20.00", "", nil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	payment, err := repo.TransitionPayment(ctx, &models.PaymentTransition{
+		PaymentId: "pay-1",
+		To:        models.PaymentCaptured,
+		Responses: []*models.ProviderResponse{{Provider: "fake", Operation: "capture", Approved: true, TransactionId: "fake_000001", Amount: 2000}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "captured", payment.Status)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT status FROM payments").
+		WithArgs("pay-1").
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("captured"))
+	mock.ExpectRollback()
+
+	_, err = repo.TransitionPayment(ctx, &models.PaymentTransition{PaymentId: "pay-1", To: models.PaymentAuthorized})
+	assert.EqualError(t, err, "invalid payment transition from captured to authorized")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCheckPaymentStatus(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+	ctx := context.Background()
+	request := &pro.CheckPaymentStatusRequest{PaymentId: "pay-1", OrderId: "order-1"}
+	now := time.Now().Format(time.RFC3339)
+
+	mock.ExpectQuery("SELECT (.+) FROM payments").
+		WithArgs(request.PaymentId, request.OrderId).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "amount", "status", "transaction_id", "payment_method", "created_at", "provider", "updated_at", "currency"}).
+			AddRow("pay-1", "order-1", 20.0, "captured", "fake_000001", "credit-card", now, "fake", now, "USD"))
+	mock.ExpectQuery("SELECT (.+) FROM payment_status_history").
+		WithArgs("pay-1").
+		WillReturnRows(sqlmock.NewRows([]string{"from_status", "to_status", "reason", "created_at"}).
+			AddRow("", "pending", "", now).
+			AddRow("pending", "authorized", "", now).
+			AddRow("authorized", "captured", "", now))
+	mock.ExpectQuery("SELECT (.+) FROM payment_provider_responses").
+		WithArgs("pay-1").
+		WillReturnRows(sqlmock.NewRows([]string{"operation", "approved", "transaction_id", "amount", "decline_code", "created_at"}).
+			AddRow("authorize", true, "fake_000001", 20.0, "", now).
+			AddRow("capture", true, "fake_000001", 20.0, "", now))
+	mock.ExpectQuery("SELECT (.+) FROM refunds").
+		WithArgs("pay-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "payment_id", "order_id", "return_id", "amount", "status", "reason", "transaction_id", "created_at", "updated_at"}).
+			AddRow("ref-1", "pay-1", "order-1", "", 5.0, "completed", "damaged", "fake_000001", now, now))
+	mock.ExpectQuery("SELECT (.+) FROM refund_items").
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"refund_id", "order_item_id", "quantity", "amount"}).
+			AddRow("ref-1", "item-1", 1, 5.0))
+
+	response, err := repo.CheckPaymentStatus(ctx, request)
+	assert.NoError(t, err)
+	assert.Equal(t, "captured", response.Payment.Status)
+	assert.Equal(t, 3, len(response.History))
+	assert.Equal(t, 2, len(response.Attempts))
+	assert.Equal(t, 1, len(response.Refunds))
+	assert.Equal(t, "item-1", response.Refunds[0].Items[0].OrderItemId)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreatePaymentGuards(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+	ctx := context.Background()
+	staleBefore := time.Now().Add(-15 * time.Minute)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT total_amount, currency, status FROM orders").
+		WithArgs("order-1").
+		WillReturnRows(sqlmock.NewRows([]string{"total_amount", "currency", "status"}).AddRow(50.0, "USD", "pending"))
+	mock.ExpectRollback()
+
+	_, err := repo.CreatePayment(ctx, &models.Payment{OrderId: "order-1", Amount: models.MoneyFromFloat(49.99)}, staleBefore)
+	assert.EqualError(t, err, "payment amount 49.99 does not match order total 50.00")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT total_amount, currency, status FROM orders").
+		WithArgs("order-1").
+		WillReturnRows(sqlmock.NewRows([]string{"total_amount", "currency", "status"}).AddRow(50.0, "USD", "pending"))
+	mock.ExpectQuery("SELECT id FROM payments (.+) FOR UPDATE").
+		WithArgs("order-1", staleBefore).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery("SELECT status FROM payments").
+		WithArgs("order-1", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("captured"))
+	mock.ExpectRollback()
+
+	_, err = repo.CreatePayment(ctx, &models.Payment{OrderId: "order-1", Amount: 5000}, staleBefore)
+	assert.EqualError(t, err, "order already has a captured payment")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT total_amount, currency, status FROM orders").
+		WithArgs("order-1").
+		WillReturnRows(sqlmock.NewRows([]string{"total_amount", "currency", "status"}).AddRow(50.0, "USD", "processing"))
+	mock.ExpectRollback()
+
+	_, err = repo.CreatePayment(ctx, &models.Payment{OrderId: "order-1"}, staleBefore)
+	assert.EqualError(t, err, "order is processing and cannot be paid")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreatePaymentExpiresStalePending(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+	ctx := context.Background()
+	staleBefore := time.Now().Add(-15 * time.Minute)
+	now := time.Now().Format(time.RFC3339)
+	paymentRow := []string{"id", "order_id", "amount", "status", "transaction_id", "payment_method", "created_at", "provider", "updated_at", "currency"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT total_amount, currency, status FROM orders").
+		WithArgs("order-1").
+		WillReturnRows(sqlmock.NewRows([]string{"total_amount", "currency", "status"}).AddRow(50.0, "USD", "pending"))
+	mock.ExpectQuery("SELECT id FROM payments (.+) status = 'pending' AND updated_at < (.+) FOR UPDATE").
+		WithArgs("order-1", staleBefore).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("pay-1"))
+	mock.ExpectQuery("SELECT status FROM payments WHERE id = (.+) FOR UPDATE").
+		WithArgs("pay-1").
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("pending"))
+	mock.ExpectQuery("UPDATE payments").
+		WithArgs(models.PaymentFailed, "", "pay-1").
+		WillReturnRows(sqlmock.NewRows(paymentRow).
+			AddRow("pay-1", "order-1", 50.0, "failed", "", "credit-card", now, "fake", now, "USD"))
+	mock.ExpectExec("INSERT INTO payment_status_history").
+		WithArgs(sqlmock.AnyArg(), "pay-1", "pending", "failed", "pending payment expired").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT status FROM payments (.+) status = ANY").
+		WithArgs("order-1", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"status"}))
+	mock.ExpectQuery("INSERT INTO payments").
+		WithArgs(sqlmock.AnyArg(), "order-1", sqlmock.AnyArg(), "USD", models.PaymentPending, "credit-card", "fake").
+		WillReturnRows(sqlmock.NewRows(paymentRow).
+			AddRow("pay-2", "order-1", 50.0, "pending", "", "credit-card", now, "fake", now, "USD"))
+	mock.ExpectExec("INSERT INTO payment_status_history").
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), "", "pending", "").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	payment, err := repo.CreatePayment(ctx, &models.Payment{OrderId: "order-1", PaymentMethod: "credit-card", Provider: "fake"}, staleBefore)
+	assert.NoError(t, err)
+	assert.Equal(t, "pay-2", payment.Id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -8,11 +8,14 @@ import (
 	"log"
 	pro "product-service/genproto/product_service"
 	"product-service/models"
+	"product-service/promotion"
+	"product-service/tax"
 	"strconv"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type ProductRepo interface {
@@ -22,22 +25,75 @@ type ProductRepo interface {
 	GetProducts(c context.Context, request *pro.GetProductsRequest) (*pro.GetProductsResponse, error)
 	GetProduct(c context.Context, request *pro.GetProductRequest) (*pro.GetProductResponse, error)
 	SearchProducts(c context.Context, request *pro.SearchProductsRequest) (*pro.SearchProductsResponse, error)
-	AddRating(c context.Context, rating *pro.AddRatingRequest) (*pro.AddRatingResponse, error)
+	AddRating(c context.Context, rating *pro.AddRatingRequest, status string) (*pro.AddRatingResponse, error)
 	GetRatings(c context.Context, request *pro.GetRatingsRequest) (*pro.GetRatingsResponse, error)
-	PlaceOrder(c context.Context, order *pro.PlaceOrderRequest) (*pro.PlaceOrderResponse, error)
+	ReportRating(c context.Context, request *pro.ReportRatingRequest) error
+	GetModerationQueue(c context.Context, limit, offset int32) ([]*pro.ModerationItem, error)
+	ModerateRating(c context.Context, request *pro.ModerateRatingRequest) (*pro.Rating, []string, error)
+	ReplyToRating(c context.Context, request *pro.ReplyToRatingRequest) (*pro.RatingReply, error)
+	VoteRating(c context.Context, request *pro.VoteRatingRequest) (*pro.VoteRatingResponse, error)
+	CanAddRatingPhoto(c context.Context, ratingId, userId string, maxPhotos int) error
+	AddRatingPhoto(c context.Context, photo *models.RatingPhoto, userId string, maxPhotos int) error
+	DeleteRatingPhoto(c context.Context, photoId, userId string) (string, error)
+	GetRatingPhotos(c context.Context, ratingIds []string) (map[string][]*models.RatingPhoto, error)
+	PlaceOrder(c context.Context, order *pro.PlaceOrderRequest, checkout *models.Checkout) (*pro.PlaceOrderResponse, error)
+	GetCheckoutProducts(c context.Context, productIds []string) (map[string]*models.Product, error)
+	AddCartItem(c context.Context, userId, productId string, quantity int32) error
+	UpdateCartItem(c context.Context, userId, productId string, quantity int32) error
+	RemoveCartItem(c context.Context, userId, productId string) error
+	GetCartItems(c context.Context, userId string) ([]*models.CartItem, error)
+	CreateWishlist(c context.Context, request *pro.CreateWishlistRequest) (*pro.Wishlist, error)
+	GetWishlists(c context.Context, userId string) ([]*pro.Wishlist, error)
+	GetWishlist(c context.Context, id, userId string) (*pro.Wishlist, error)
+	DeleteWishlist(c context.Context, request *pro.DeleteWishlistRequest) (*pro.DeleteWishlistResponse, error)
+	AddWishlistItem(c context.Context, request *pro.AddWishlistItemRequest) error
+	RemoveWishlistItem(c context.Context, request *pro.RemoveWishlistItemRequest) error
+	WishlistAlerts(c context.Context, productId string) ([]*models.WishlistAlert, error)
+	AddTaxRule(c context.Context, request *pro.AddTaxRuleRequest) (*pro.AddTaxRuleResponse, error)
+	GetTaxRules(c context.Context, request *pro.GetTaxRulesRequest) (*pro.GetTaxRulesResponse, error)
+	DeleteTaxRule(c context.Context, request *pro.DeleteTaxRuleRequest) (*pro.DeleteTaxRuleResponse, error)
+	GetCheckoutPromotions(c context.Context, code string, now time.Time) ([]*models.Promotion, error)
+	AddPromotion(c context.Context, promo *models.Promotion) (*pro.AddPromotionResponse, error)
+	GetPromotions(c context.Context, request *pro.GetPromotionsRequest, now time.Time) (*pro.GetPromotionsResponse, error)
+	DeletePromotion(c context.Context, request *pro.DeletePromotionRequest) (*pro.DeletePromotionResponse, error)
 	CancelOrder(c context.Context, request *pro.CancelOrderRequest) (*pro.CancelOrderResponse, error)
 	UpdateOrderStatus(c context.Context, request *pro.UpdateOrderStatusRequest) (*pro.UpdateOrderStatusResponse, error)
 	GetOrders(c context.Context, request *pro.GetOrdersRequest) (*pro.GetOrdersResponse, error)
 	GetOrder(c context.Context, request *pro.GetOrderRequest) (*pro.GetOrderResponse, error)
-	PayOrder(c context.Context, request *pro.PayOrderRequest) (*pro.PayOrderResponse, error)
+	CreatePayment(c context.Context, payment *models.Payment, staleBefore time.Time) (*pro.Payment, error)
+	TransitionPayment(c context.Context, transition *models.PaymentTransition) (*pro.Payment, error)
 	CheckPaymentStatus(c context.Context, request *pro.CheckPaymentStatusRequest) (*pro.CheckPaymentStatusResponse, error)
-	UpdateShippingInfo(c context.Context, request *pro.UpdateShippingInfoRequest) (*pro.UpdateShippingInfoResponse, error)
+	UpdateShippingInfo(c context.Context, request *pro.UpdateShippingInfoRequest, estimatedDelivery *time.Time) (*pro.UpdateShippingInfoResponse, error)
+	AddShipment(c context.Context, request *pro.UpdateShippingInfoRequest, estimatedDelivery *time.Time) (*pro.UpdateShippingInfoResponse, error)
+	AddTrackingEvent(c context.Context, event *models.TrackingEvent) (*pro.AddTrackingEventResponse, error)
+	GetShipments(c context.Context, request *pro.GetShipmentsRequest) (*pro.GetShipmentsResponse, error)
+	GetShipment(c context.Context, id string) (*pro.Shipment, error)
 	AddArtisanCategory(c context.Context, request *pro.AddArtisanCategoryRequest) (*pro.AddArtisanCategoryResponse, error)
 	AddProductCategory(c context.Context, request *pro.AddProductCategoryRequest) (*pro.AddProductCategoryResponse, error)
 	GetStatistics(c context.Context, request *pro.GetStatisticsRequest) (*pro.GetStatisticsResponse, error)
 	GetUserActivity(c context.Context, request *pro.GetUserActivityRequest) (*pro.GetUserActivityResponse, error)
 	GetRecommendations(c context.Context, request *pro.GetRecommendationsRequest) (*pro.GetRecommendationsResponse, error)
 	GetArtisanRankings(c context.Context, request *pro.GetArtisanRankingsRequest) (*pro.GetArtisanRankingsResponse, error)
+	ListArtisanOrders(c context.Context, request *pro.ListArtisanOrdersRequest) (*pro.ListArtisanOrdersResponse, error)
+	ListMyOrders(c context.Context, request *pro.ListMyOrdersRequest) (*pro.ListMyOrdersResponse, error)
+	ReserveIdempotencyKey(c context.Context, key *models.IdempotencyKey, expiredBefore time.Time) (*models.IdempotencyKey, bool, error)
+	CompleteIdempotencyKey(c context.Context, key *models.IdempotencyKey) error
+	ReleaseIdempotencyKey(c context.Context, key *models.IdempotencyKey) error
+	AddAddress(c context.Context, request *pro.AddAddressRequest) (*pro.AddAddressResponse, error)
+	GetAddresses(c context.Context, request *pro.GetAddressesRequest) (*pro.GetAddressesResponse, error)
+	GetAddress(c context.Context, id, userId string) (*pro.SavedAddress, error)
+	UpdateAddress(c context.Context, request *pro.UpdateAddressRequest) (*pro.UpdateAddressResponse, error)
+	DeleteAddress(c context.Context, request *pro.DeleteAddressRequest) (*pro.DeleteAddressResponse, error)
+	RequestReturn(c context.Context, request *pro.RequestReturnRequest, deliveredAfter time.Time) (*pro.RequestReturnResponse, error)
+	ResolveReturn(c context.Context, request *pro.ResolveReturnRequest, approve bool) (*pro.ResolveReturnResponse, error)
+	GetReturns(c context.Context, request *pro.GetReturnsRequest) (*pro.GetReturnsResponse, error)
+	CreateRefund(c context.Context, refund *models.Refund) (*pro.Refund, *pro.Payment, error)
+	SettleRefund(c context.Context, refundId string, res *models.ProviderResponse) (*pro.RefundPaymentResponse, error)
+	AddPaymentMethod(c context.Context, method *models.PaymentMethod) (*pro.AddPaymentMethodResponse, error)
+	GetPaymentMethods(c context.Context, request *pro.GetPaymentMethodsRequest) (*pro.GetPaymentMethodsResponse, error)
+	GetPaymentMethodToken(c context.Context, id, userId string) (string, error)
+	DeletePaymentMethod(c context.Context, request *pro.DeletePaymentMethodRequest) (*pro.DeletePaymentMethodResponse, error)
+	ApplyPaymentEvent(c context.Context, event *models.PaymentEvent) (string, error)
 }
 
 type productRepo struct {
@@ -52,8 +108,8 @@ func (r *productRepo) AddProduct(c context.Context, product *pro.AddProductReque
 	newId := uuid.NewString()
 
 	query := `
-		INSERT INTO products (id, name, description, price, category_id, artisan_id, quantity)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO products (id, name, description, price, category_id, artisan_id, quantity, currency, weight_grams)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		RETURNING 
 		id,
 		name,
@@ -62,44 +118,63 @@ func (r *productRepo) AddProduct(c context.Context, product *pro.AddProductReque
         category_id,
         artisan_id,
 		quantity,
-        created_at
+        created_at,
+		currency,
+		weight_grams
 	`
 
 	row := r.db.QueryRowContext(c, query,
 		newId,
 		product.Name,
 		product.Description,
-		product.Price,
+		models.MoneyFromFloat(product.Price),
 		product.CategoryId,
 		product.ArtisanId,
 		product.Quantity,
+		product.Currency,
+		product.WeightGrams,
 	)
 
 	if row.Err() != nil {
 		return nil, row.Err()
 	}
 	newProduct := pro.AddProductResponse{}
-	err := row.Scan(&newProduct.Id, &newProduct.Name, &newProduct.Description, &newProduct.Price, &newProduct.CategoryId, &newProduct.ArtisanId, &newProduct.Quantity, &newProduct.CreatedAt)
+	var price models.Money
+	err := row.Scan(&newProduct.Id, &newProduct.Name, &newProduct.Description, &price, &newProduct.CategoryId, &newProduct.ArtisanId, &newProduct.Quantity, &newProduct.CreatedAt, &newProduct.Currency, &newProduct.WeightGrams)
+	newProduct.Price = price.Float64()
 	return &newProduct, err
 }
 
+// EditProduct replaces the product's fields. Clients that predate the
+// currency and weight fields send neither, which keeps the stored values.
 func (r *productRepo) EditProduct(c context.Context, product *pro.EditProductRequest) (*pro.EditProductResponse, error) {
 	query := `
-		UPDATE products SET name = $1, description = $2, price = $3, category_id = $4, artisan_id = $5, quantity = $6
-		WHERE id = $7 AND deleted_at IS NULL
+		UPDATE products SET name = $1, description = $2, price = $3, category_id = $4, artisan_id = $5, quantity = $6,
+		currency = COALESCE(NULLIF($7, ''), currency),
+		weight_grams = COALESCE(NULLIF($8, 0), weight_grams)
+		WHERE id = $9 AND deleted_at IS NULL
+		RETURNING currency, weight_grams
 	`
 
-	res, err := r.db.ExecContext(c, query,
+	price := models.MoneyFromFloat(product.Price)
+	var currency string
+	var weightGrams int32
+	err := r.db.QueryRowContext(c, query,
 		product.Name,
 		product.Description,
-		product.Price,
+		price,
 		product.CategoryId,
 		product.ArtisanId,
 		product.Quantity,
-		product.Id)
+		product.Currency,
+		product.WeightGrams,
+		product.Id).Scan(&currency, &weightGrams)
 
-	log.Println(product, res)
+	log.Println(product, err)
 
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("product not found")
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -108,7 +183,9 @@ func (r *productRepo) EditProduct(c context.Context, product *pro.EditProductReq
 		Id:          product.Id,
 		Name:        product.Name,
 		Description: product.Description,
-		Price:       product.Price,
+		Price:       price.Float64(),
+		Currency:    currency,
+		WeightGrams: weightGrams,
 		CategoryId:  product.CategoryId,
 		ArtisanId:   product.ArtisanId,
 	}, nil
@@ -126,42 +203,117 @@ func (r *productRepo) DeleteProduct(c context.Context, request *pro.DeleteProduc
 	return &pro.DeleteProductResponse{}, nil
 }
 
+func productToProto(product *models.Product) *pro.Product {
+	return &pro.Product{
+		Id:          product.Id,
+		Name:        product.Name,
+		Description: product.Description,
+		Price:       product.Price.Float64(),
+		Currency:    product.Currency,
+		CategoryId:  product.CategoryId,
+		ArtisanId:   product.ArtisanId,
+		Quantity:    product.Quantity,
+		WeightGrams: product.WeightGrams,
+		CreatedAt:   product.CreatedAt,
+		UpdatedAt:   product.UpdatedAt,
+	}
+}
+
+// productColumns selects a product from productsWithRatings along with its
+// rating summary, see scanProduct. Nullable columns read as empty strings.
+const productColumns = `p.id, p.name, COALESCE(p.description, ''), p.price, p.currency, COALESCE(p.category_id::text, ''), COALESCE(p.artisan_id::text, ''), p.quantity, p.weight_grams, p.created_at, p.updated_at,
+	COALESCE(s.rating_count, 0), COALESCE(s.rating_average, 0),
+	COALESCE(s.stars_1, 0), COALESCE(s.stars_2, 0), COALESCE(s.stars_3, 0), COALESCE(s.stars_4, 0), COALESCE(s.stars_5, 0)`
+
+const productsWithRatings = `products p LEFT JOIN product_rating_summaries s ON s.product_id = p.id`
+
+func scanProduct(row rowScanner) (*pro.Product, error) {
+	var product models.Product
+	summary := pro.RatingSummary{Histogram: make([]int32, 5)}
+	err := row.Scan(
+		&product.Id,
+		&product.Name,
+		&product.Description,
+		&product.Price,
+		&product.Currency,
+		&product.CategoryId,
+		&product.ArtisanId,
+		&product.Quantity,
+		&product.WeightGrams,
+		&product.CreatedAt,
+		&product.UpdatedAt,
+		&summary.Count,
+		&summary.Average,
+		&summary.Histogram[0],
+		&summary.Histogram[1],
+		&summary.Histogram[2],
+		&summary.Histogram[3],
+		&summary.Histogram[4],
+	)
+	if err != nil {
+		return nil, err
+	}
+	res := productToProto(&product)
+	res.Rating = &summary
+	return res, nil
+}
+
+// productSortOrders are the ORDER BY clauses of the sort_by options of
+// product listings. Unsorted listings keep the database order.
+var productSortOrders = map[string]string{
+	"":                        "",
+	models.ProductSortRating:  "ORDER BY COALESCE(s.rating_average, 0) DESC, COALESCE(s.rating_count, 0) DESC, p.id",
+	models.ProductSortReviews: "ORDER BY COALESCE(s.rating_count, 0) DESC, COALESCE(s.rating_average, 0) DESC, p.id",
+}
+
+func productSortOrder(sortBy string) (string, error) {
+	order, ok := productSortOrders[sortBy]
+	if !ok {
+		return "", fmt.Errorf("invalid sort: %s", sortBy)
+	}
+	return order, nil
+}
+
 func (r *productRepo) GetProducts(c context.Context, request *pro.GetProductsRequest) (*pro.GetProductsResponse, error) {
-	var products []*pro.Product
+	order, err := productSortOrder(request.SortBy)
+	if err != nil {
+		return nil, err
+	}
 	query := `
-		SELECT id, name, description, price, category_id, artisan_id, quantity, created_at, updated_at 
-		FROM products 
-		WHERE deleted_at IS NULL 
+		SELECT ` + productColumns + `
+		FROM ` + productsWithRatings + `
+		WHERE p.deleted_at IS NULL
+		` + order + `
 		LIMIT $1 OFFSET $2
 	`
-	err := r.db.SelectContext(c, &products, query, request.Limit, request.Page)
+	rows, err := r.db.QueryContext(c, query, request.Limit, request.Page)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
+	products := []*pro.Product{}
+	for rows.Next() {
+		product, err := scanProduct(rows)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, product)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 	return &pro.GetProductsResponse{Products: products}, nil
 }
 
 func (r *productRepo) GetProduct(c context.Context, request *pro.GetProductRequest) (*pro.GetProductResponse, error) {
-	var product pro.Product
-	var pr float64
 	query := `
-		SELECT id, name, description, price, category_id, artisan_id, quantity, created_at, updated_at 
-		FROM products 
-		WHERE id = $1 AND deleted_at IS NULL
+		SELECT ` + productColumns + `
+		FROM ` + productsWithRatings + `
+		WHERE p.id = $1 AND p.deleted_at IS NULL
 	`
 
-	err := r.db.QueryRowContext(c, query, request.Id).Scan(
-		&product.Id,
-		&product.Name,
-		&product.Description,
-		&pr,
-		&product.CategoryId,
-		&product.ArtisanId,
-		&product.Quantity,
-		&product.CreatedAt,
-		&product.UpdatedAt,
-	)
+	product, err := scanProduct(r.db.QueryRowContext(c, query, request.Id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil // Product not found
@@ -169,32 +321,52 @@ func (r *productRepo) GetProduct(c context.Context, request *pro.GetProductReque
 		return nil, err
 	}
 
-	// productRes := &pro.Product{
-	// 	Id:          product.Id,
-	// 	Name:        product.Name,
-	// 	Description: product.Description,
-	// 	Price:       pr,
-	// 	CategoryId:  product.CategoryId,
-	// 	ArtisanId:   product.ArtisanId,
-	// 	Quantity:    product.Quantity,
-	// 	CreatedAt:   product.CreatedAt,
-	// 	UpdatedAt:   product.UpdatedAt,
-	// }
+	return &pro.GetProductResponse{Product: product}, nil
+}
+
+// GetCheckoutProducts returns the price, currency, weight and category of the
+// given products, keyed by product id, for working out an order before it is
+// placed.
+func (r *productRepo) GetCheckoutProducts(c context.Context, productIds []string) (map[string]*models.Product, error) {
+	rows, err := r.db.QueryContext(c, `
+		SELECT id, price, currency, weight_grams, COALESCE(category_id::text, '')
+		FROM products
+		WHERE id = ANY($1) AND deleted_at IS NULL
+	`, pq.Array(productIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-	return &pro.GetProductResponse{Product: &product}, nil
+	products := make(map[string]*models.Product, len(productIds))
+	for rows.Next() {
+		var product models.Product
+		if err := rows.Scan(&product.Id, &product.Price, &product.Currency, &product.WeightGrams, &product.CategoryId); err != nil {
+			return nil, err
+		}
+		products[product.Id] = &product
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return products, nil
 }
 
 func (r *productRepo) SearchProducts(ctx context.Context, req *pro.SearchProductsRequest) (*pro.SearchProductsResponse, error) {
-	var products []*pro.Product
+	order, err := productSortOrder(req.SortBy)
+	if err != nil {
+		return nil, err
+	}
 	query := `
-        SELECT p.id, p.name, p.price, p.category_id
-        FROM products p
+        SELECT ` + productColumns + `
+        FROM ` + productsWithRatings + `
         JOIN product_categories c ON p.category_id = c.id
         WHERE c.name LIKE $1
         AND p.name LIKE $2
         AND p.price BETWEEN $3 AND $4
         AND p.deleted_at IS NULL
         AND c.deleted_at IS NULL
+		` + order + `
 		LIMIT $5
 		OFFSET $6
     `
@@ -202,18 +374,20 @@ func (r *productRepo) SearchProducts(ctx context.Context, req *pro.SearchProduct
 	page, _ := strconv.Atoi(req.Page)
 	offset := (page - 1) * limit
 
-	rows, err := r.db.QueryContext(ctx, query, "%"+req.Category+"%", "%"+req.Query+"%", req.MinPrice, req.MaxPrice, limit, offset)
+	rows, err := r.db.QueryContext(ctx, query, "%"+req.Category+"%", "%"+req.Query+"%",
+		models.MoneyFromFloat(req.MinPrice), models.MoneyFromFloat(req.MaxPrice), limit, offset)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
+	var products []*pro.Product
 	for rows.Next() {
-		var product pro.Product
-		if err := rows.Scan(&product.Id, &product.Name, &product.Price, &product.CategoryId); err != nil {
+		product, err := scanProduct(rows)
+		if err != nil {
 			return nil, err
 		}
-		products = append(products, &product)
+		products = append(products, product)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
@@ -222,44 +396,14 @@ func (r *productRepo) SearchProducts(ctx context.Context, req *pro.SearchProduct
 	return &pro.SearchProductsResponse{Products: products}, nil
 }
 
-func (r *productRepo) AddRating(c context.Context, rating *pro.AddRatingRequest) (*pro.AddRatingResponse, error) {
-	newId := uuid.NewString()
-	query := `
-		INSERT INTO ratings (id, product_id, user_id, rating, comment, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
-	`
-	_, err := r.db.ExecContext(c, query,
-		newId,
-		rating.ProductId,
-		rating.UserId,
-		rating.Rating,
-		rating.Comment,
-		time.Now())
-
-	if err != nil {
-		return nil, err
-	}
-
-	return &pro.AddRatingResponse{Id: newId}, nil
-}
-
-func (r *productRepo) GetRatings(c context.Context, request *pro.GetRatingsRequest) (*pro.GetRatingsResponse, error) {
-
-	var ratings []*pro.Rating
-	query := `
-		SELECT id, product_id, user_id, rating, comment, created_at 
-		FROM ratings 
-		WHERE product_id = $1
-	`
-	err := r.db.SelectContext(c, &ratings, query, request.ProductId)
-	if err != nil {
-		return nil, err
-	}
-
-	return &pro.GetRatingsResponse{Ratings: ratings}, nil
-}
-
-func (r *productRepo) PlaceOrder(c context.Context, order *pro.PlaceOrderRequest) (*pro.PlaceOrderResponse, error) {
+// PlaceOrder creates the order in order.Currency. Products priced in another
+// currency are converted with the checkout rates, which are stored with the
+// order so its totals can be reproduced later. Taxes follow the tax rules of
+// the destination and are stored as tax lines, after the discounts of the
+// checkout promotions; the shipping quote is stored and added to the total. A
+// checkout from the cart also empties the ordered products from the cart, in
+// the same transaction.
+func (r *productRepo) PlaceOrder(c context.Context, order *pro.PlaceOrderRequest, checkout *models.Checkout) (*pro.PlaceOrderResponse, error) {
 	tx, err := r.db.Begin()
 	if err != nil {
 		return nil, err
@@ -267,24 +411,15 @@ func (r *productRepo) PlaceOrder(c context.Context, order *pro.PlaceOrderRequest
 	defer tx.Rollback()
 	newOrderId := uuid.NewString()
 
-	query := `INSERT INTO orders(id, user_id, total_amount, status, shipping_address) VALUES($1, $2, $3, $4, $5)`
-	type shipping struct {
-		Street  string `json:"street"`
-		City    string `json:"city"`
-		Country string `json:"country"`
-		ZipCode string `json:"zip_code"`
-	}
-
-	shippingAddress := shipping{
-		Street: order.ShippingAddress,
-	}
+	query := `INSERT INTO orders(id, user_id, total_amount, status, shipping_address, currency) VALUES($1, $2, $3, $4, $5, $6)`
+	shippingAddress := shippingAddressFromProto(order.Address)
 
 	shippingAddressJSON, err := json.Marshal(shippingAddress)
 	if err != nil {
 		tx.Rollback()
 		return nil, fmt.Errorf("error marshaling shipping address: %v", err)
 	}
-	res, err := tx.ExecContext(c, query, newOrderId, order.UserId, 0, order.Status, shippingAddressJSON)
+	res, err := tx.ExecContext(c, query, newOrderId, order.UserId, 0, order.Status, shippingAddressJSON, order.Currency)
 	if err != nil {
 		return nil, fmt.Errorf("error inserting order: %v", err)
 	}
@@ -296,19 +431,24 @@ func (r *productRepo) PlaceOrder(c context.Context, order *pro.PlaceOrderRequest
 		return nil, fmt.Errorf("empty order")
 	}
 	query = `
-		SELECT price, quantity FROM products WHERE id = $1 AND deleted_at IS NULL
+		SELECT name, description, price, currency, quantity, COALESCE(category_id::text, ''), COALESCE(artisan_id::text, '')
+		FROM products WHERE id = $1 AND deleted_at IS NULL
 	`
 
-	var total_amount float64
+	var lines []promotion.Line
+	var productIds []string
 	for i, item := range items {
 		var quantity int
-		var price float64
+		var price models.Money
+		var currency, categoryId, artisanId string
+		var name string
+		var description sql.NullString
 		row := tx.QueryRowContext(c, query, item.ProductId)
 		if row.Err() == sql.ErrNoRows {
 			return nil, fmt.Errorf("product not found")
 		}
 
-		err := row.Scan(&price, &quantity)
+		err := row.Scan(&name, &description, &price, &currency, &quantity, &categoryId, &artisanId)
 
 		if err != nil {
 			return nil, fmt.Errorf("error scanning product: %v", err)
@@ -316,21 +456,115 @@ func (r *productRepo) PlaceOrder(c context.Context, order *pro.PlaceOrderRequest
 		if quantity < int(item.Quantity) {
 			return nil, fmt.Errorf("not enough stock for item %s", item.ProductId)
 		}
-		items[i].Price = float32(price)
+
+		originalPrice := price
+		if currency != order.Currency {
+			rate, ok := checkout.Rates[currency]
+			if !ok {
+				return nil, fmt.Errorf("no exchange rate from %s to %s", currency, order.Currency)
+			}
+			price, err = originalPrice.Convert(rate.Rate)
+			if err != nil {
+				return nil, err
+			}
+		}
+		items[i].Price = price.Float64()
+		items[i].OriginalPrice = originalPrice.Float64()
+		items[i].OriginalCurrency = currency
 		newOrderItemsId := uuid.NewString()
-		res, err := tx.ExecContext(c, "INSERT INTO order_items(id, order_id, product_id, quantity, price) VALUES($1, $2, $3, $4, $5)", newOrderItemsId, newOrderId, item.ProductId, item.Quantity, price)
+		res, err := tx.ExecContext(c, `
+			INSERT INTO order_items(id, order_id, product_id, quantity, price, product_name, product_description, original_price, original_currency)
+			VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`, newOrderItemsId, newOrderId, item.ProductId, item.Quantity, price, name, description.String, originalPrice, currency)
 		if err != nil {
 			return nil, fmt.Errorf("error inserting order item: %v", err)
 		}
 		if n, err := res.RowsAffected(); n == 0 || err != nil {
 			return nil, fmt.Errorf("error inserting order item: %v", err)
 		}
+		// Stock is taken when the order is placed and put back when it is
+		// canceled or returned. The guard fails the order when a concurrent
+		// order took the stock since it was read above.
+		res, err = tx.ExecContext(c, `
+			UPDATE products SET quantity = quantity - $1, updated_at = now() WHERE id = $2 AND quantity >= $1
+		`, item.Quantity, item.ProductId)
+		if err != nil {
+			return nil, fmt.Errorf("error updating stock: %v", err)
+		}
+		if n, err := res.RowsAffected(); n == 0 || err != nil {
+			return nil, fmt.Errorf("not enough stock for item %s", item.ProductId)
+		}
+
+		lines = append(lines, promotion.Line{
+			OrderItemId: newOrderItemsId,
+			ProductId:   item.ProductId,
+			ArtisanId:   artisanId,
+			CategoryId:  categoryId,
+			Amount:      price.Mul(item.Quantity),
+		})
+		productIds = append(productIds, item.ProductId)
+	}
+
+	exchangeRates := sortedExchangeRates(checkout.Rates)
+	if err := insertOrderExchangeRates(c, tx, newOrderId, exchangeRates); err != nil {
+		return nil, err
+	}
 
-		total_amount += price * float64(item.Quantity)
+	promos, err := usablePromotions(c, tx, order.UserId, checkout.Promotions)
+	if err != nil {
+		return nil, err
+	}
+	discounts, err := promotion.Apply(promos, lines, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	for _, promo := range promos {
+		if promo.Code != "" && !discounts.IsApplied(promo.Id) {
+			return nil, fmt.Errorf("coupon %s does not apply to this order", promo.Code)
+		}
+	}
+	if err := redeemPromotions(c, tx, newOrderId, discounts.Applied, discounts.Lines); err != nil {
+		return nil, err
 	}
 
-	query = `UPDATE orders SET total_amount = $1 WHERE id = $2`
-	res, err = tx.ExecContext(c, query, total_amount, newOrderId)
+	taxLines := make([]tax.Line, 0, len(lines))
+	for _, line := range lines {
+		taxLines = append(taxLines, tax.Line{
+			OrderItemId: line.OrderItemId,
+			ProductId:   line.ProductId,
+			CategoryId:  line.CategoryId,
+			Amount:      line.Amount - discounts.ItemDiscount(line.OrderItemId),
+		})
+	}
+	rules, err := loadTaxRules(c, tx, shippingAddress.Country)
+	if err != nil {
+		return nil, err
+	}
+	taxes, err := tax.Calculate(rules, shippingAddress.Region, taxLines)
+	if err != nil {
+		return nil, err
+	}
+	if err := insertOrderTaxLines(c, tx, newOrderId, taxes.Lines); err != nil {
+		return nil, err
+	}
+
+	var shipping models.ShippingQuote
+	if checkout.Shipping != nil {
+		shipping = *checkout.Shipping
+	}
+	// Taxes were worked out on the discounted amounts, so the subtotal of
+	// listed prices has the discount added back.
+	subtotal := taxes.Subtotal + discounts.Discount
+	total := taxes.Total + shipping.Amount
+
+	query = `
+		UPDATE orders SET total_amount = $1, subtotal_amount = $2, tax_amount = $3,
+		shipping_method = NULLIF($4, ''), shipping_amount = $5,
+		discount_amount = $6, coupon_code = NULLIF($7, '')
+		WHERE id = $8
+	`
+	res, err = tx.ExecContext(c, query, total, subtotal, taxes.Tax, shipping.Method, shipping.Amount,
+		discounts.Discount, order.CouponCode, newOrderId)
 	if err != nil {
 		tx.Rollback()
 		return nil, fmt.Errorf("error inserting order: %v", err)
@@ -339,6 +573,11 @@ func (r *productRepo) PlaceOrder(c context.Context, order *pro.PlaceOrderRequest
 		tx.Rollback()
 		return nil, fmt.Errorf("error inserting order: %v", err)
 	}
+	if checkout.FromCart {
+		if err := clearCartItems(c, tx, order.UserId, productIds); err != nil {
+			return nil, err
+		}
+	}
 	err = tx.Commit()
 	if err != nil {
 		return nil, fmt.Errorf("error committing transaction: %v", err)
@@ -346,20 +585,59 @@ func (r *productRepo) PlaceOrder(c context.Context, order *pro.PlaceOrderRequest
 	return &pro.PlaceOrderResponse{
 		Id:              newOrderId,
 		UserId:          order.UserId,
-		TotalPrice:      float32(total_amount),
+		TotalPrice:      total.Float64(),
+		SubtotalAmount:  subtotal.Float64(),
+		DiscountAmount:  discounts.Discount.Float64(),
+		DiscountLines:   discountLinesToProto(discounts.Lines),
+		CouponCode:      order.CouponCode,
+		TaxAmount:       taxes.Tax.Float64(),
+		TaxLines:        taxLinesToProto(taxes.Lines),
+		ShippingMethod:  shipping.Method,
+		ShippingAmount:  shipping.Amount.Float64(),
+		Currency:        order.Currency,
+		ExchangeRates:   exchangeRatesToProto(exchangeRates),
 		Status:          order.Status,
 		ShippingAddress: order.ShippingAddress,
+		Address:         order.Address,
 		Items:           items,
 		CreatedAt:       time.Now().Format("2006-01-02T15:04:05Z"),
 	}, nil
 }
 
+// CancelOrder cancels a pending order and puts its items back in stock.
 func (r *productRepo) CancelOrder(c context.Context, request *pro.CancelOrderRequest) (*pro.CancelOrderResponse, error) {
-	query := `
-		UPDATE orders SET status = 'canceled', updated_at = now() WHERE id = $1 AND status = 'pending'
-	`
-	_, err := r.db.ExecContext(c, query, request.Id)
+	tx, err := r.db.BeginTxx(c, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(c, `
+		UPDATE orders SET status = 'canceled', updated_at = now() WHERE id = $1 AND status = 'pending' AND deleted_at IS NULL
+	`, request.Id)
+	if err != nil {
+		return nil, err
+	}
+	if n, err := res.RowsAffected(); n == 0 || err != nil {
+		return nil, fmt.Errorf("order not found or no longer pending")
+	}
+
+	_, err = tx.ExecContext(c, `
+		UPDATE products p SET quantity = p.quantity + oi.quantity, updated_at = now()
+		FROM (
+			SELECT product_id, SUM(quantity) AS quantity FROM order_items
+			WHERE order_id = $1 AND deleted_at IS NULL
+			GROUP BY product_id
+		) oi
+		WHERE p.id = oi.product_id
+	`, request.Id)
 	if err != nil {
+		return nil, fmt.Errorf("error restocking products: %v", err)
+	}
+	if err := releasePromotions(c, tx.Tx, request.Id); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
 
@@ -372,7 +650,9 @@ func (r *productRepo) CancelOrder(c context.Context, request *pro.CancelOrderReq
 
 func (r *productRepo) UpdateOrderStatus(c context.Context, request *pro.UpdateOrderStatusRequest) (*pro.UpdateOrderStatusResponse, error) {
 	query := `
-		UPDATE orders SET status = $1, updated_at = now() WHERE id = $2
+		UPDATE orders SET status = $1, updated_at = now(),
+		delivered_at = CASE WHEN $1 = 'delivered' THEN now() ELSE delivered_at END
+		WHERE id = $2
 	`
 	_, err := r.db.ExecContext(c, query, request.Status, request.Id)
 	if err != nil {
@@ -390,7 +670,7 @@ func (r *productRepo) UpdateOrderStatus(c context.Context, request *pro.UpdateOr
 func (r *productRepo) GetOrders(c context.Context, request *pro.GetOrdersRequest) (*pro.GetOrdersResponse, error) {
 	var orders []*models.Order
 	query := `
-        SELECT id, user_id, status, total_amount, created_at, updated_at 
+        SELECT id, user_id, status, total_amount, currency, created_at, updated_at 
         FROM orders 
         WHERE deleted_at IS NULL
         LIMIT $1 OFFSET $2
@@ -405,7 +685,8 @@ func (r *productRepo) GetOrders(c context.Context, request *pro.GetOrdersRequest
 		protoOrder := &pro.Order{
 			Id:              order.Id,
 			UserId:          order.UserId,
-			TotalAmount:     order.TotalAmount,
+			TotalAmount:     order.TotalAmount.Float64(),
+			Currency:        order.Currency,
 			Status:          order.Status,
 			ShippingAddress: order.ShippingAddress,
 			CreatedAt:       order.CreatedAt,
@@ -417,123 +698,85 @@ func (r *productRepo) GetOrders(c context.Context, request *pro.GetOrdersRequest
 	return &pro.GetOrdersResponse{Orders: protoOrders}, nil
 }
 
+// GetOrder returns an order with its details. A user id limits it to that
+// buyer's order; without one any order is returned, for internal callers.
 func (r *productRepo) GetOrder(c context.Context, request *pro.GetOrderRequest) (*pro.GetOrderResponse, error) {
-	var order pro.Order
-	err := r.db.GetContext(c, &order, `
-		SELECT id, user_id, status, total_amount, created_at, updated_at 
+	query := `
+		SELECT ` + orderColumns + `
 		FROM orders 
 		WHERE id = $1 AND deleted_at IS NULL
-	`, request.Id)
+	`
+	args := []interface{}{request.Id}
+	if request.UserId != "" {
+		query += " AND user_id = $2"
+		args = append(args, request.UserId)
+	}
 
+	order, err := scanOrder(r.db.QueryRowContext(c, query, args...))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, nil // Order not found
+			return nil, fmt.Errorf("order not found")
 		}
 		return nil, err
 	}
 
-	return &pro.GetOrderResponse{Order: &order}, nil
-}
-
-func (r *productRepo) PayOrder(c context.Context, request *pro.PayOrderRequest) (*pro.PayOrderResponse, error) {
-	var amount float32
-	row := r.db.QueryRowContext(c, "SELECT total_amount FROM orders WHERE id = $1 AND deleted_at IS NULL AND status = 'pending'", request.OrderId)
-	if row == nil {
-		return nil, fmt.Errorf("order not found")
+	orders := []*pro.Order{order}
+	if err := r.attachOrderItems(c, orders); err != nil {
+		return nil, err
 	}
-	err := row.Scan(&amount)
-	if err != nil {
+	if err := r.attachOrderPayments(c, orders); err != nil {
 		return nil, err
 	}
-	transacId := uuid.NewString()
-	newPaymentId := uuid.NewString()
-	query := `
-			INSERT INTO payments (
-			id, 
-			order_id, 
-			amount, 
-			status,
-			transaction_id,
-			payment_method)
-			VALUES (
-			$1, 
-			$2, 
-			$3,
-			'paid', 
-			$4,
-            $5)
-	`
-	_, err = r.db.ExecContext(c, query,
-		newPaymentId,
-		request.OrderId,
-		amount,
-		transacId,
-		request.PaymentMethod,
-	)
-	if err != nil {
+	if err := r.attachOrderExchangeRates(c, orders); err != nil {
+		return nil, err
+	}
+	if err := r.attachOrderTaxLines(c, orders); err != nil {
+		return nil, err
+	}
+	if err := r.attachOrderDiscountLines(c, orders); err != nil {
+		return nil, err
+	}
+	if err := r.attachOrderShipments(c, orders); err != nil {
 		return nil, err
 	}
 
-	return &pro.PayOrderResponse{
-		Id:            newPaymentId,
-		OrderId:       request.OrderId,
-		Amount:        float64(amount),
-		PaymentMethod: request.PaymentMethod,
-		Status:        "paid",
-		TransactionId: transacId,
-		CreatedAt:     time.Now().Format("2006-01-02 15:04:05"),
-	}, nil
+	return &pro.GetOrderResponse{Order: order}, nil
 }
 
 func (r *productRepo) CheckPaymentStatus(c context.Context, request *pro.CheckPaymentStatusRequest) (*pro.CheckPaymentStatusResponse, error) {
 
 	qury := `
-		SELECT id, order_id, amount, status, transaction_id, payment_method, created_at
+		SELECT ` + paymentColumns + `
         FROM payments 
         WHERE id = $1 AND order_id = $2 AND deleted_at IS NULL
 	`
 
-	var payment pro.Payment
-	row := r.db.QueryRowContext(c, qury, request.PaymentId, request.OrderId)
-	if row == nil {
-		return nil, fmt.Errorf("payment not found %v", row.Err())
-	}
-	err := row.Scan(&payment.Id, &payment.OrderId, &payment.Amount, &payment.Status, &payment.TransactionId, &payment.PaymentMethod, &payment.CreatedAt)
+	payment, err := scanPayment(r.db.QueryRowContext(c, qury, request.PaymentId, request.OrderId))
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("payment not found")
+		}
 		return nil, err
 	}
-	return &pro.CheckPaymentStatusResponse{
-		Payment: &payment,
-	}, nil
-}
 
-func (r *productRepo) UpdateShippingInfo(c context.Context, request *pro.UpdateShippingInfoRequest) (*pro.UpdateShippingInfoResponse, error) {
-	updatedAt := time.Now()
-	query := `
-		UPDATE orders SET 
-		tracking_number = $1, 
-		carrier = $2, 
-		estimated_delivery_date = $3, 
-		updated_at = $4
-		WHERE id = $5
-	`
-	_, err := r.db.ExecContext(c, query,
-		request.TrackingNumber,
-		request.Carrier,
-		request.EstimatedDeliveryDate,
-		updatedAt,
-		request.OrderId,
-	)
+	history, err := r.getPaymentHistory(c, payment.Id)
+	if err != nil {
+		return nil, err
+	}
+	attempts, err := r.getPaymentAttempts(c, payment.Id)
+	if err != nil {
+		return nil, err
+	}
+	refunds, err := r.getRefunds(c, payment.Id)
 	if err != nil {
 		return nil, err
 	}
 
-	return &pro.UpdateShippingInfoResponse{
-		OrderId:               request.OrderId,
-		TrackingNumber:        request.TrackingNumber,
-		Carrier:               request.Carrier,
-		EstimatedDeliveryDate: request.EstimatedDeliveryDate,
-		UpdatedAt:             updatedAt.Format("2006-01-02 15:04:05"),
+	return &pro.CheckPaymentStatusResponse{
+		Payment:  payment,
+		History:  history,
+		Attempts: attempts,
+		Refunds:  refunds,
 	}, nil
 }
 
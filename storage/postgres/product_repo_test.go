@@ -42,12 +42,14 @@ func TestAddProduct(t *testing.T) {
 		CategoryId:  "cat-123",
 		ArtisanId:   "art-123",
 		Quantity:    int32(q),
+		Currency:    "EUR",
+		WeightGrams: 750,
 	}
 
 	mock.ExpectQuery("INSERT INTO products").
-		WithArgs(sqlmock.AnyArg(), product.Name, product.Description, product.Price, product.CategoryId, product.ArtisanId, product.Quantity).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "price", "category_id", "artisan_id", "quantity", "created_at"}).
-			AddRow(newId, product.Name, product.Description, product.Price, product.CategoryId, product.ArtisanId, product.Quantity, time.Now()))
+		WithArgs(sqlmock.AnyArg(), product.Name, product.Description, "99.99", product.CategoryId, product.ArtisanId, product.Quantity, product.Currency, product.WeightGrams).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "price", "category_id", "artisan_id", "quantity", "created_at", "currency", "weight_grams"}).
+			AddRow(newId, product.Name, product.Description, "99.99", product.CategoryId, product.ArtisanId, product.Quantity, time.Now(), product.Currency, product.WeightGrams))
 
 	response, err := repo.AddProduct(ctx, product)
 	assert.NoError(t, err)
@@ -59,6 +61,8 @@ func TestAddProduct(t *testing.T) {
 	assert.Equal(t, product.CategoryId, response.CategoryId)
 	assert.Equal(t, product.ArtisanId, response.ArtisanId)
 	assert.Equal(t, product.Quantity, response.Quantity)
+	assert.Equal(t, "EUR", response.Currency)
+	assert.Equal(t, int32(750), response.WeightGrams)
 }
 
 func TestEditProduct(t *testing.T) {
@@ -75,11 +79,12 @@ func TestEditProduct(t *testing.T) {
 		CategoryId:  "cat-456",
 		ArtisanId:   "art-456",
 		Quantity:    5,
+		Currency:    "USD",
 	}
 
-	mock.ExpectExec("UPDATE products").
-		WithArgs(product.Name, product.Description, product.Price, product.CategoryId, product.ArtisanId, product.Quantity, product.Id).
-		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("UPDATE products").
+		WithArgs(product.Name, product.Description, "49.99", product.CategoryId, product.ArtisanId, product.Quantity, product.Currency, product.WeightGrams, product.Id).
+		WillReturnRows(sqlmock.NewRows([]string{"currency", "weight_grams"}).AddRow("USD", 0))
 
 	response, err := repo.EditProduct(ctx, product)
 	assert.NoError(t, err)
@@ -92,6 +97,24 @@ func TestEditProduct(t *testing.T) {
 	assert.Equal(t, product.ArtisanId, response.ArtisanId)
 }
 
+func TestEditProductKeepsCurrencyAndWeight(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+	product := &pro.EditProductRequest{Id: "prod-123", Name: "Updated Product", Price: 49.99, ArtisanId: "art-456", Quantity: 5}
+
+	mock.ExpectQuery("UPDATE products SET (.+) currency = COALESCE\\(NULLIF\\(\\$7, ''\\), currency\\), weight_grams = COALESCE\\(NULLIF\\(\\$8, 0\\), weight_grams\\)").
+		WithArgs(product.Name, product.Description, "49.99", product.CategoryId, product.ArtisanId, product.Quantity, "", product.WeightGrams, product.Id).
+		WillReturnRows(sqlmock.NewRows([]string{"currency", "weight_grams"}).AddRow("EUR", 750))
+
+	response, err := repo.EditProduct(context.Background(), product)
+	assert.NoError(t, err)
+	assert.Equal(t, "EUR", response.Currency)
+	assert.Equal(t, int32(750), response.WeightGrams)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestDeleteProduct(t *testing.T) {
 	db, mock, teardown := setupMockDB(t)
 	defer teardown()
@@ -109,6 +132,9 @@ func TestDeleteProduct(t *testing.T) {
 	assert.NotNil(t, response)
 }
 
+var productRowColumns = []string{"id", "name", "description", "price", "currency", "category_id", "artisan_id", "quantity", "weight_grams", "created_at", "updated_at",
+	"rating_count", "rating_average", "stars_1", "stars_2", "stars_3", "stars_4", "stars_5"}
+
 func TestGetProducts(t *testing.T) {
 	db, mock, teardown := setupMockDB(t)
 	defer teardown()
@@ -140,11 +166,11 @@ func TestGetProducts(t *testing.T) {
 		},
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "name", "description", "price", "category_id", "artisan_id", "quantity", "created_at"}).
-		AddRow(mockProducts[0].Id, mockProducts[0].Name, mockProducts[0].Description, mockProducts[0].Price, mockProducts[0].CategoryId, mockProducts[0].ArtisanId, mockProducts[0].Quantity, mockProducts[0].CreatedAt).
-		AddRow(mockProducts[1].Id, mockProducts[1].Name, mockProducts[1].Description, mockProducts[1].Price, mockProducts[1].CategoryId, mockProducts[1].ArtisanId, mockProducts[1].Quantity, mockProducts[1].CreatedAt)
+	rows := sqlmock.NewRows(productRowColumns).
+		AddRow(mockProducts[0].Id, mockProducts[0].Name, mockProducts[0].Description, "100.00", "USD", mockProducts[0].CategoryId, mockProducts[0].ArtisanId, mockProducts[0].Quantity, 0, mockProducts[0].CreatedAt, mockProducts[0].CreatedAt, 0, 0, 0, 0, 0, 0, 0).
+		AddRow(mockProducts[1].Id, mockProducts[1].Name, mockProducts[1].Description, "200.00", "USD", mockProducts[1].CategoryId, mockProducts[1].ArtisanId, mockProducts[1].Quantity, 0, mockProducts[1].CreatedAt, mockProducts[1].CreatedAt, 0, 0, 0, 0, 0, 0, 0)
 
-	mock.ExpectQuery("SELECT id, name, description, price, category_id, artisan_id, quantity, created_at, updated_at FROM products").
+	mock.ExpectQuery("SELECT p.id, p.name, COALESCE\\(p.description, ''\\), p.price, p.currency, COALESCE\\(p.category_id::text, ''\\), COALESCE\\(p.artisan_id::text, ''\\), p.quantity, p.weight_grams, p.created_at, p.updated_at, (.+) FROM products p LEFT JOIN product_rating_summaries s ON s.product_id = p.id WHERE p.deleted_at IS NULL LIMIT \\$1 OFFSET \\$2").
 		WithArgs(request.Limit, request.Page).
 		WillReturnRows(rows)
 
@@ -156,6 +182,36 @@ func TestGetProducts(t *testing.T) {
 	assert.Equal(t, mockProducts[1].Id, response.Products[1].Id)
 }
 
+func TestGetProductsSortedByRating(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+	ctx := context.Background()
+	request := &pro.GetProductsRequest{Limit: "10", Page: "0", SortBy: models.ProductSortRating}
+
+	createdAt := time.Now().Format(time.RFC3339)
+	rows := sqlmock.NewRows(productRowColumns).
+		AddRow("prod-1", "Product 1", "Description 1", "100.00", "USD", "cat-1", "art-1", 10, 0, createdAt, createdAt, 4, 4.75, 0, 0, 0, 1, 3).
+		AddRow("prod-2", "Product 2", "Description 2", "200.00", "USD", "cat-2", "art-2", 20, 0, createdAt, createdAt, 0, 0, 0, 0, 0, 0, 0)
+
+	mock.ExpectQuery("SELECT p.id, p.name, (.+) FROM products p LEFT JOIN product_rating_summaries s (.+) ORDER BY COALESCE\\(s.rating_average, 0\\) DESC").
+		WithArgs(request.Limit, request.Page).
+		WillReturnRows(rows)
+
+	response, err := repo.GetProducts(ctx, request)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(response.Products))
+	assert.Equal(t, "prod-1", response.Products[0].Id)
+	assert.Equal(t, 4.75, response.Products[0].Rating.Average)
+	assert.Equal(t, []int32{0, 0, 0, 1, 3}, response.Products[0].Rating.Histogram)
+	assert.Equal(t, int32(0), response.Products[1].Rating.Count)
+
+	_, err = repo.GetProducts(ctx, &pro.GetProductsRequest{SortBy: "cheapest"})
+	assert.EqualError(t, err, "invalid sort: cheapest")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestGetProduct(t *testing.T) {
 	db, mock, teardown := setupMockDB(t)
 	defer teardown()
@@ -168,18 +224,20 @@ func TestGetProduct(t *testing.T) {
 		Id:          "prod-123",
 		Name:        "Test Product",
 		Description: "This is a test product",
-		Price:       99.99,
+		Price:       9999,
+		Currency:    "UZS",
 		CategoryId:  "cat-123",
 		ArtisanId:   "art-123",
 		Quantity:    10,
+		WeightGrams: 1200,
 		CreatedAt:   time.Now().String(),
 		UpdatedAt:   time.Now().String(),
 	}
 
-	row := sqlmock.NewRows([]string{"id", "name", "description", "price", "category_id", "artisan_id", "quantity", "created_at", "updated_at"}).
-		AddRow(mockProduct.Id, mockProduct.Name, mockProduct.Description, mockProduct.Price, mockProduct.CategoryId, mockProduct.ArtisanId, mockProduct.Quantity, mockProduct.CreatedAt, mockProduct.UpdatedAt)
+	row := sqlmock.NewRows(productRowColumns).
+		AddRow(mockProduct.Id, mockProduct.Name, mockProduct.Description, mockProduct.Price.String(), mockProduct.Currency, mockProduct.CategoryId, mockProduct.ArtisanId, mockProduct.Quantity, mockProduct.WeightGrams, mockProduct.CreatedAt, mockProduct.UpdatedAt, 2, 3.5, 0, 0, 1, 1, 0)
 
-	mock.ExpectQuery("SELECT id, name, description, price, category_id, artisan_id, quantity, created_at, updated_at FROM products").
+	mock.ExpectQuery("SELECT p.id, p.name, (.+) FROM products p LEFT JOIN product_rating_summaries s ON s.product_id = p.id WHERE p.id = (.+)").
 		WithArgs(request.Id).
 		WillReturnRows(row)
 
@@ -189,10 +247,162 @@ func TestGetProduct(t *testing.T) {
 	assert.Equal(t, mockProduct.Id, response.Product.Id)
 	assert.Equal(t, mockProduct.Name, response.Product.Name)
 	assert.Equal(t, mockProduct.Description, response.Product.Description)
-	assert.Equal(t, mockProduct.Price, response.Product.Price)
+	assert.Equal(t, 99.99, response.Product.Price)
+	assert.Equal(t, "UZS", response.Product.Currency)
 	assert.Equal(t, mockProduct.CategoryId, response.Product.CategoryId)
 	assert.Equal(t, mockProduct.ArtisanId, response.Product.ArtisanId)
 	assert.Equal(t, mockProduct.Quantity, response.Product.Quantity)
+	assert.Equal(t, mockProduct.WeightGrams, response.Product.WeightGrams)
 	assert.Equal(t, mockProduct.CreatedAt, response.Product.CreatedAt)
 	assert.Equal(t, mockProduct.UpdatedAt, response.Product.UpdatedAt)
+	assert.Equal(t, int32(2), response.Product.Rating.Count)
+	assert.Equal(t, 3.5, response.Product.Rating.Average)
+}
+
+func TestPlaceOrderConvertsCurrency(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+	ctx := context.Background()
+	order := &pro.PlaceOrderRequest{
+		UserId:   "user-1",
+		Status:   "pending",
+		Currency: "EUR",
+		Address:  &pro.Address{Street: "Main 1", City: "Berlin", Country: "DE", ZipCode: "10115"},
+		Items: []*pro.Item{
+			{ProductId: "prod-1", Quantity: 2},
+			{ProductId: "prod-2", Quantity: 1},
+		},
+	}
+	asOf := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	checkout := &models.Checkout{
+		Rates: map[string]*models.ExchangeRate{
+			"USD": {Base: "USD", Quote: "EUR", Rate: "0.8", Source: "static", AsOf: asOf},
+		},
+		Shipping: &models.ShippingQuote{Method: "standard", Amount: 960, Currency: "EUR"},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO orders").
+		WithArgs(sqlmock.AnyArg(), order.UserId, 0, order.Status, sqlmock.AnyArg(), "EUR").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("SELECT name, description, price, currency, quantity, (.+) FROM products").
+		WithArgs("prod-1").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "description", "price", "currency", "quantity", "category_id", "artisan_id"}).
+			AddRow("Vase", "Clay vase", "19.99", "USD", 5, "cat-pottery", "artisan-1"))
+	mock.ExpectExec("INSERT INTO order_items").
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), "prod-1", int32(2), "15.99", "Vase", "Clay vase", "19.99", "USD").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE products SET quantity = quantity -").
+		WithArgs(int32(2), "prod-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT name, description, price, currency, quantity, (.+) FROM products").
+		WithArgs("prod-2").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "description", "price", "currency", "quantity", "category_id", "artisan_id"}).
+			AddRow("Bowl", "Oak bowl", "10.00", "EUR", 3, "cat-kitchen", "artisan-1"))
+	mock.ExpectExec("INSERT INTO order_items").
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), "prod-2", int32(1), "10.00", "Bowl", "Oak bowl", "10.00", "EUR").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE products SET quantity = quantity -").
+		WithArgs(int32(1), "prod-2").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO order_exchange_rates").
+		WithArgs(sqlmock.AnyArg(), "USD", "EUR", "0.8", "static", asOf).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("SELECT (.+) FROM tax_rules").
+		WithArgs("DE").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "country", "region", "category_id", "name", "rate", "inclusive", "created_at"}))
+	mock.ExpectExec("UPDATE orders SET total_amount").
+		WithArgs("51.58", "41.98", "0.00", "standard", "9.60", "0.00", "", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	response, err := repo.PlaceOrder(ctx, order, checkout)
+	assert.NoError(t, err)
+	assert.NotNil(t, response)
+	assert.Equal(t, "EUR", response.Currency)
+	assert.Equal(t, 41.98, response.SubtotalAmount)
+	assert.Equal(t, 9.6, response.ShippingAmount)
+	assert.Equal(t, 51.58, response.TotalPrice)
+	assert.Equal(t, 15.99, response.Items[0].Price)
+	assert.Equal(t, 19.99, response.Items[0].OriginalPrice)
+	assert.Equal(t, "USD", response.Items[0].OriginalCurrency)
+	assert.Equal(t, 1, len(response.ExchangeRates))
+	assert.Equal(t, "0.8", response.ExchangeRates[0].Rate)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// A product in a currency without a rate cannot be ordered.
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO orders").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("SELECT name, description, price, currency, quantity, (.+) FROM products").
+		WithArgs("prod-1").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "description", "price", "currency", "quantity", "category_id", "artisan_id"}).
+			AddRow("Vase", "Clay vase", "19.99", "GBP", 5, "cat-pottery", "artisan-1"))
+	mock.ExpectRollback()
+
+	_, err = repo.PlaceOrder(ctx, &pro.PlaceOrderRequest{UserId: "user-1", Currency: "EUR", Items: order.Items[:1]}, checkout)
+	assert.EqualError(t, err, "no exchange rate from GBP to EUR")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPlaceOrderAppliesTax(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+	ctx := context.Background()
+	order := &pro.PlaceOrderRequest{
+		UserId:   "user-1",
+		Status:   "pending",
+		Currency: "USD",
+		Address:  &pro.Address{Street: "1 Main St", City: "Albany", Region: "NY", Country: "US", ZipCode: "12207"},
+		Items: []*pro.Item{
+			{ProductId: "prod-1", Quantity: 1},
+			{ProductId: "prod-2", Quantity: 2},
+		},
+	}
+	now := time.Now()
+	taxRuleColumns := []string{"id", "country", "region", "category_id", "name", "rate", "inclusive", "created_at"}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO orders").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("SELECT name, description, price, currency, quantity, (.+) FROM products").
+		WithArgs("prod-1").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "description", "price", "currency", "quantity", "category_id", "artisan_id"}).
+			AddRow("Vase", "Clay vase", "19.99", "USD", 5, "cat-pottery", "artisan-1"))
+	mock.ExpectExec("INSERT INTO order_items").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE products SET quantity = quantity -").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT name, description, price, currency, quantity, (.+) FROM products").
+		WithArgs("prod-2").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "description", "price", "currency", "quantity", "category_id", "artisan_id"}).
+			AddRow("Jam", "Apricot jam", "5.00", "USD", 9, "cat-food", "artisan-1"))
+	mock.ExpectExec("INSERT INTO order_items").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE products SET quantity = quantity -").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT (.+) FROM tax_rules").
+		WithArgs("US").
+		WillReturnRows(sqlmock.NewRows(taxRuleColumns).
+			AddRow("rule-ny", "US", "NY", "", "NY sales tax", "0.040000", false, now).
+			AddRow("rule-ny-food", "US", "NY", "cat-food", "NY food", "0.000000", false, now).
+			AddRow("rule-ca", "US", "CA", "", "CA sales tax", "0.072500", false, now))
+	mock.ExpectExec("INSERT INTO order_tax_lines").
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "rule-ny", "NY sales tax", "0.040000", false, "19.99", "0.80").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO order_tax_lines").
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "rule-ny-food", "NY food", "0.000000", false, "10.00", "0.00").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE orders SET total_amount").
+		WithArgs("30.79", "29.99", "0.80", "pickup", "0.00", "0.00", "", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	response, err := repo.PlaceOrder(ctx, order, &models.Checkout{Shipping: &models.ShippingQuote{Method: "pickup", Currency: "USD"}})
+	assert.NoError(t, err)
+	assert.NotNil(t, response)
+	assert.Equal(t, 29.99, response.SubtotalAmount)
+	assert.Equal(t, 0.8, response.TaxAmount)
+	assert.Equal(t, 30.79, response.TotalPrice)
+	assert.Equal(t, 2, len(response.TaxLines))
+	assert.Equal(t, "prod-1", response.TaxLines[0].ProductId)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -0,0 +1,320 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	pro "product-service/genproto/product_service"
+	"product-service/models"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const promotionColumns = `id, COALESCE(code, ''), name, kind, COALESCE(percent_off::text, ''), amount_off, currency,
+		min_order_amount, max_uses, max_uses_per_user, used_count,
+		COALESCE(artisan_id::text, ''), COALESCE(category_id::text, ''), starts_at, ends_at, created_at`
+
+type promotionRow struct {
+	models.Promotion
+	CreatedAt string
+}
+
+func scanPromotion(row rowScanner) (*promotionRow, error) {
+	var promo promotionRow
+	var startsAt, endsAt sql.NullTime
+	err := row.Scan(
+		&promo.Id,
+		&promo.Code,
+		&promo.Name,
+		&promo.Kind,
+		&promo.PercentOff,
+		&promo.AmountOff,
+		&promo.Currency,
+		&promo.MinOrderAmount,
+		&promo.MaxUses,
+		&promo.MaxUsesPerUser,
+		&promo.UsedCount,
+		&promo.ArtisanId,
+		&promo.CategoryId,
+		&startsAt,
+		&endsAt,
+		&promo.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if startsAt.Valid {
+		promo.StartsAt = &startsAt.Time
+	}
+	if endsAt.Valid {
+		promo.EndsAt = &endsAt.Time
+	}
+	return &promo, nil
+}
+
+func (promo *promotionRow) toProto() *pro.Promotion {
+	res := &pro.Promotion{
+		Id:             promo.Id,
+		Code:           promo.Code,
+		Name:           promo.Name,
+		Kind:           promo.Kind,
+		PercentOff:     promo.PercentOff,
+		AmountOff:      promo.AmountOff.Float64(),
+		Currency:       promo.Currency,
+		MinOrderAmount: promo.MinOrderAmount.Float64(),
+		MaxUses:        promo.MaxUses,
+		MaxUsesPerUser: promo.MaxUsesPerUser,
+		UsedCount:      promo.UsedCount,
+		ArtisanId:      promo.ArtisanId,
+		CategoryId:     promo.CategoryId,
+		CreatedAt:      promo.CreatedAt,
+	}
+	if promo.StartsAt != nil {
+		res.StartsAt = promo.StartsAt.Format(time.RFC3339)
+	}
+	if promo.EndsAt != nil {
+		res.EndsAt = promo.EndsAt.Format(time.RFC3339)
+	}
+	return res
+}
+
+func discountLinesToProto(lines []*models.DiscountLine) []*pro.DiscountLine {
+	res := make([]*pro.DiscountLine, 0, len(lines))
+	for _, line := range lines {
+		res = append(res, &pro.DiscountLine{
+			OrderItemId: line.OrderItemId,
+			ProductId:   line.ProductId,
+			PromotionId: line.PromotionId,
+			Code:        line.Code,
+			Name:        line.Name,
+			Amount:      line.Amount.Float64(),
+		})
+	}
+	return res
+}
+
+// GetCheckoutPromotions returns the automatic promotions running at now and,
+// when code is given, the coupon with that code if it is running too.
+func (r *productRepo) GetCheckoutPromotions(c context.Context, code string, now time.Time) ([]*models.Promotion, error) {
+	rows, err := r.db.QueryContext(c, `
+		SELECT `+promotionColumns+`
+		FROM promotions
+		WHERE deleted_at IS NULL
+		AND (starts_at IS NULL OR starts_at <= $1) AND (ends_at IS NULL OR ends_at > $1)
+		AND (code IS NULL OR upper(code) = $2)
+		ORDER BY created_at, id
+	`, now, code)
+	if err != nil {
+		return nil, fmt.Errorf("error loading promotions: %v", err)
+	}
+	defer rows.Close()
+
+	var promos []*models.Promotion
+	for rows.Next() {
+		promo, err := scanPromotion(rows)
+		if err != nil {
+			return nil, err
+		}
+		promos = append(promos, &promo.Promotion)
+	}
+	return promos, rows.Err()
+}
+
+// usablePromotions locks the promotions of a checkout and drops those that
+// reached their usage limits, overall or for the buyer. A coupon the buyer
+// asked for that reached its limits is an error rather than being dropped.
+func usablePromotions(c context.Context, tx *sql.Tx, userId string, promos []*models.Promotion) ([]*models.Promotion, error) {
+	var usable []*models.Promotion
+	for _, promo := range promos {
+		var used, maxUses, maxUsesPerUser int32
+		err := tx.QueryRowContext(c, `
+			SELECT used_count, max_uses, max_uses_per_user FROM promotions
+			WHERE id = $1 AND deleted_at IS NULL
+			FOR UPDATE
+		`, promo.Id).Scan(&used, &maxUses, &maxUsesPerUser)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error locking promotion: %v", err)
+		}
+
+		limited := maxUses > 0 && used >= maxUses
+		if !limited && maxUsesPerUser > 0 {
+			var usedByUser int32
+			err := tx.QueryRowContext(c, `
+				SELECT COUNT(DISTINCT dl.order_id) FROM order_discount_lines dl
+				JOIN orders o ON o.id = dl.order_id
+				WHERE dl.promotion_id = $1 AND o.user_id = $2 AND o.status <> 'canceled'
+			`, promo.Id, userId).Scan(&usedByUser)
+			if err != nil {
+				return nil, fmt.Errorf("error counting promotion uses: %v", err)
+			}
+			limited = usedByUser >= maxUsesPerUser
+		}
+		if limited {
+			if promo.Code != "" {
+				return nil, fmt.Errorf("coupon %s has reached its usage limit", promo.Code)
+			}
+			continue
+		}
+		usable = append(usable, promo)
+	}
+	return usable, nil
+}
+
+// redeemPromotions counts one use of every promotion applied to an order and
+// stores its discount lines.
+func redeemPromotions(c context.Context, tx *sql.Tx, orderId string, applied []*models.Promotion, lines []*models.DiscountLine) error {
+	for _, promo := range applied {
+		_, err := tx.ExecContext(c, `UPDATE promotions SET used_count = used_count + 1, updated_at = now() WHERE id = $1`, promo.Id)
+		if err != nil {
+			return fmt.Errorf("error redeeming promotion: %v", err)
+		}
+	}
+	for _, line := range lines {
+		_, err := tx.ExecContext(c, `
+			INSERT INTO order_discount_lines (id, order_id, order_item_id, promotion_id, code, name, amount)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`, uuid.NewString(), orderId, line.OrderItemId, line.PromotionId, line.Code, line.Name, line.Amount)
+		if err != nil {
+			return fmt.Errorf("error inserting discount line: %v", err)
+		}
+	}
+	return nil
+}
+
+// releasePromotions gives back the uses counted by redeemPromotions when an
+// order is canceled. The discount lines are kept for the order history; uses
+// per buyer skip canceled orders.
+func releasePromotions(c context.Context, tx *sql.Tx, orderId string) error {
+	_, err := tx.ExecContext(c, `
+		UPDATE promotions SET used_count = used_count - 1, updated_at = now()
+		WHERE used_count > 0 AND id IN (
+			SELECT DISTINCT promotion_id FROM order_discount_lines WHERE order_id = $1
+		)
+	`, orderId)
+	if err != nil {
+		return fmt.Errorf("error releasing promotions: %v", err)
+	}
+	return nil
+}
+
+// attachOrderDiscountLines loads the discount lines recorded for the given
+// orders.
+func (r *productRepo) attachOrderDiscountLines(c context.Context, orders []*pro.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+	byId := make(map[string]*pro.Order, len(orders))
+	orderIds := make([]string, 0, len(orders))
+	for _, order := range orders {
+		byId[order.Id] = order
+		orderIds = append(orderIds, order.Id)
+	}
+
+	rows, err := r.db.QueryContext(c, `
+		SELECT dl.order_id, dl.order_item_id, COALESCE(oi.product_id::text, ''), dl.promotion_id,
+		dl.code, dl.name, dl.amount
+		FROM order_discount_lines dl
+		LEFT JOIN order_items oi ON oi.id = dl.order_item_id
+		WHERE dl.order_id = ANY($1)
+		ORDER BY dl.created_at
+	`, pq.Array(orderIds))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var orderId string
+		var line models.DiscountLine
+		err := rows.Scan(&orderId, &line.OrderItemId, &line.ProductId, &line.PromotionId,
+			&line.Code, &line.Name, &line.Amount)
+		if err != nil {
+			return err
+		}
+		if order, ok := byId[orderId]; ok {
+			order.DiscountLines = append(order.DiscountLines, discountLinesToProto([]*models.DiscountLine{&line})...)
+		}
+	}
+	return rows.Err()
+}
+
+func (r *productRepo) AddPromotion(c context.Context, promo *models.Promotion) (*pro.AddPromotionResponse, error) {
+	var startsAt, endsAt sql.NullTime
+	if promo.StartsAt != nil {
+		startsAt = sql.NullTime{Time: *promo.StartsAt, Valid: true}
+	}
+	if promo.EndsAt != nil {
+		endsAt = sql.NullTime{Time: *promo.EndsAt, Valid: true}
+	}
+
+	row, err := scanPromotion(r.db.QueryRowContext(c, `
+		INSERT INTO promotions (id, code, name, kind, percent_off, amount_off, currency, min_order_amount,
+		max_uses, max_uses_per_user, artisan_id, category_id, starts_at, ends_at)
+		VALUES ($1, NULLIF($2, ''), $3, $4, NULLIF($5, '')::numeric, $6, $7, $8, $9, $10,
+		NULLIF($11, '')::uuid, NULLIF($12, '')::uuid, $13, $14)
+		RETURNING `+promotionColumns,
+		uuid.NewString(), promo.Code, promo.Name, promo.Kind, promo.PercentOff, promo.AmountOff, promo.Currency,
+		promo.MinOrderAmount, promo.MaxUses, promo.MaxUsesPerUser, promo.ArtisanId, promo.CategoryId, startsAt, endsAt))
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return nil, fmt.Errorf("a promotion with code %s already exists", promo.Code)
+		}
+		return nil, fmt.Errorf("error inserting promotion: %v", err)
+	}
+	return &pro.AddPromotionResponse{Promotion: row.toProto()}, nil
+}
+
+// GetPromotions lists promotions, newest first. activeOnly leaves out those
+// outside their validity window at now.
+func (r *productRepo) GetPromotions(c context.Context, request *pro.GetPromotionsRequest, now time.Time) (*pro.GetPromotionsResponse, error) {
+	query := `
+		SELECT ` + promotionColumns + `
+		FROM promotions
+		WHERE deleted_at IS NULL
+	`
+	var args []interface{}
+	if request.ActiveOnly {
+		query += " AND (starts_at IS NULL OR starts_at <= $1) AND (ends_at IS NULL OR ends_at > $1)"
+		args = append(args, now)
+	}
+	query += " ORDER BY created_at DESC"
+
+	rows, err := r.db.QueryContext(c, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var promos []*pro.Promotion
+	for rows.Next() {
+		promo, err := scanPromotion(rows)
+		if err != nil {
+			return nil, err
+		}
+		promos = append(promos, promo.toProto())
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &pro.GetPromotionsResponse{Promotions: promos}, nil
+}
+
+func (r *productRepo) DeletePromotion(c context.Context, request *pro.DeletePromotionRequest) (*pro.DeletePromotionResponse, error) {
+	res, err := r.db.ExecContext(c, `
+		UPDATE promotions SET deleted_at = now() WHERE id = $1 AND deleted_at IS NULL
+	`, request.Id)
+	if err != nil {
+		return nil, err
+	}
+	if n, err := res.RowsAffected(); n == 0 || err != nil {
+		return nil, fmt.Errorf("promotion not found")
+	}
+
+	return &pro.DeletePromotionResponse{}, nil
+}
@@ -0,0 +1,145 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pro "product-service/genproto/product_service"
+	"product-service/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var promotionRowColumns = []string{"id", "code", "name", "kind", "percent_off", "amount_off", "currency", "min_order_amount",
+	"max_uses", "max_uses_per_user", "used_count", "artisan_id", "category_id", "starts_at", "ends_at", "created_at"}
+
+func TestAddPromotion(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+	ctx := context.Background()
+	promo := &models.Promotion{Code: "SPRING10", Name: "Spring sale", Kind: models.PromotionPercentage, PercentOff: "10", Currency: "USD", MaxUsesPerUser: 1}
+
+	mock.ExpectQuery("INSERT INTO promotions").
+		WithArgs(sqlmock.AnyArg(), "SPRING10", "Spring sale", "percentage", "10", "0.00", "USD", "0.00", int32(0), int32(1), "", "", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows(promotionRowColumns).
+			AddRow("promo-1", "SPRING10", "Spring sale", "percentage", "10.00", 0, "USD", 0, 0, 1, 0, "", "", nil, time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), time.Now()))
+
+	response, err := repo.AddPromotion(ctx, promo)
+	assert.NoError(t, err)
+	assert.Equal(t, "promo-1", response.Promotion.Id)
+	assert.Equal(t, "10.00", response.Promotion.PercentOff)
+	assert.Equal(t, "", response.Promotion.StartsAt)
+	assert.Equal(t, "2024-06-01T00:00:00Z", response.Promotion.EndsAt)
+
+	mock.ExpectQuery("INSERT INTO promotions").
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	_, err = repo.AddPromotion(ctx, promo)
+	assert.EqualError(t, err, "a promotion with code SPRING10 already exists")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPlaceOrderWithCoupon(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+	ctx := context.Background()
+	order := &pro.PlaceOrderRequest{
+		UserId:     "user-1",
+		Status:     "pending",
+		Currency:   "USD",
+		CouponCode: "SPRING10",
+		Address:    &pro.Address{Street: "1 Main St", City: "Albany", Region: "NY", Country: "US", ZipCode: "12207"},
+		Items:      []*pro.Item{{ProductId: "prod-1", Quantity: 2}},
+	}
+	checkout := &models.Checkout{
+		Shipping: &models.ShippingQuote{Method: "pickup", Currency: "USD"},
+		Promotions: []*models.Promotion{
+			{Id: "promo-1", Code: "SPRING10", Name: "Spring sale", Kind: models.PromotionPercentage, PercentOff: "10", Currency: "USD"},
+		},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO orders").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("SELECT name, description, price, currency, quantity, (.+) FROM products").
+		WithArgs("prod-1").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "description", "price", "currency", "quantity", "category_id", "artisan_id"}).
+			AddRow("Vase", "Clay vase", "20.00", "USD", 5, "", "artisan-1"))
+	mock.ExpectExec("INSERT INTO order_items").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE products SET quantity = quantity -").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT used_count, max_uses, max_uses_per_user FROM promotions (.+) FOR UPDATE").
+		WithArgs("promo-1").
+		WillReturnRows(sqlmock.NewRows([]string{"used_count", "max_uses", "max_uses_per_user"}).AddRow(4, 100, 1))
+	mock.ExpectQuery("SELECT COUNT(.+) FROM order_discount_lines dl JOIN orders o").
+		WithArgs("promo-1", "user-1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectExec("UPDATE promotions SET used_count = used_count \\+ 1").
+		WithArgs("promo-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO order_discount_lines").
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "promo-1", "SPRING10", "Spring sale", "4.00").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("SELECT (.+) FROM tax_rules").
+		WithArgs("US").
+		WillReturnRows(sqlmock.NewRows(taxRuleRowColumns).
+			AddRow("rule-ny", "US", "NY", "", "NY sales tax", "0.050000", false, time.Now()))
+	mock.ExpectExec("INSERT INTO order_tax_lines").
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "rule-ny", "NY sales tax", "0.050000", false, "36.00", "1.80").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE orders SET total_amount").
+		WithArgs("37.80", "40.00", "1.80", "pickup", "0.00", "4.00", "SPRING10", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	response, err := repo.PlaceOrder(ctx, order, checkout)
+	assert.NoError(t, err)
+	assert.Equal(t, 40.0, response.SubtotalAmount)
+	assert.Equal(t, 4.0, response.DiscountAmount)
+	assert.Equal(t, 37.8, response.TotalPrice)
+	assert.Equal(t, 1, len(response.DiscountLines))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPlaceOrderCouponUsedUp(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+	ctx := context.Background()
+	order := &pro.PlaceOrderRequest{
+		UserId:     "user-1",
+		Currency:   "USD",
+		CouponCode: "ONCE",
+		Address:    &pro.Address{Street: "1 Main St", City: "Albany", Region: "NY", Country: "US", ZipCode: "12207"},
+		Items:      []*pro.Item{{ProductId: "prod-1", Quantity: 1}},
+	}
+	checkout := &models.Checkout{Promotions: []*models.Promotion{
+		{Id: "promo-1", Code: "ONCE", Kind: models.PromotionFixed, AmountOff: 500, Currency: "USD"},
+	}}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO orders").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("SELECT name, description, price, currency, quantity, (.+) FROM products").
+		WithArgs("prod-1").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "description", "price", "currency", "quantity", "category_id", "artisan_id"}).
+			AddRow("Vase", "Clay vase", "20.00", "USD", 5, "", "artisan-1"))
+	mock.ExpectExec("INSERT INTO order_items").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE products SET quantity = quantity -").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT used_count, max_uses, max_uses_per_user FROM promotions").
+		WithArgs("promo-1").
+		WillReturnRows(sqlmock.NewRows([]string{"used_count", "max_uses", "max_uses_per_user"}).AddRow(0, 0, 1))
+	mock.ExpectQuery("SELECT COUNT(.+) FROM order_discount_lines").
+		WithArgs("promo-1", "user-1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectRollback()
+
+	_, err := repo.PlaceOrder(ctx, order, checkout)
+	assert.EqualError(t, err, "coupon ONCE has reached its usage limit")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
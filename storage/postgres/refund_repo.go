@@ -0,0 +1,390 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	pro "product-service/genproto/product_service"
+	"product-service/models"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const refundColumns = `id, payment_id, order_id, COALESCE(return_id::text, ''), amount, status,
+		COALESCE(reason, ''), COALESCE(transaction_id, ''), created_at, updated_at`
+
+// reservedRefundStatuses are the statuses of a refund that counts against the
+// refundable amount of a payment.
+var reservedRefundStatuses = []string{
+	models.RefundPending,
+	models.RefundProcessing,
+	models.RefundCompleted,
+}
+
+func scanRefund(row rowScanner) (*pro.Refund, error) {
+	var refund pro.Refund
+	err := row.Scan(
+		&refund.Id,
+		&refund.PaymentId,
+		&refund.OrderId,
+		&refund.ReturnId,
+		&refund.Amount,
+		&refund.Status,
+		&refund.Reason,
+		&refund.TransactionId,
+		&refund.CreatedAt,
+		&refund.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &refund, nil
+}
+
+func insertRefundItems(c context.Context, tx *sqlx.Tx, refundId string, items []*models.RefundItem) error {
+	for _, item := range items {
+		_, err := tx.ExecContext(c, `
+			INSERT INTO refund_items (id, refund_id, order_item_id, quantity, amount)
+			VALUES ($1, $2, $3, $4, $5)
+		`, uuid.NewString(), refundId, item.OrderItemId, item.Quantity, item.Amount)
+		if err != nil {
+			return fmt.Errorf("error inserting refund item: %v", err)
+		}
+	}
+	return nil
+}
+
+// orderItemPaid is what the buyer paid for every unit of the order item oi:
+// its list price less the discounts applied to it, plus the taxes charged on
+// top of it. Inclusive taxes are already part of the price.
+const orderItemPaid = `oi.price * oi.quantity
+	- COALESCE((SELECT SUM(dl.amount) FROM order_discount_lines dl WHERE dl.order_item_id = oi.id), 0)
+	+ COALESCE((SELECT SUM(tl.amount) FROM order_tax_lines tl WHERE tl.order_item_id = oi.id AND NOT tl.inclusive), 0)`
+
+// refundShare is what refunding quantity more units of an order item is
+// worth when refunded of its ordered units were refunded before. The shares
+// of all units add up to exactly what was paid for the item.
+func refundShare(paid models.Money, ordered, refunded, quantity int32) models.Money {
+	return paid.Share(refunded+quantity, ordered) - paid.Share(refunded, ordered)
+}
+
+// priceRefundItems prices the line items of a refund from what was paid for
+// them, making sure no line is refunded more times than it was bought.
+func priceRefundItems(c context.Context, tx *sqlx.Tx, orderId string, items []*models.RefundItem) (models.Money, error) {
+	var total models.Money
+	for _, item := range items {
+		var paid models.Money
+		var ordered, refunded int32
+		err := tx.QueryRowContext(c, `
+			SELECT oi.quantity, `+orderItemPaid+`, COALESCE((
+				SELECT SUM(ri.quantity) FROM refund_items ri
+				JOIN refunds rf ON rf.id = ri.refund_id
+				WHERE ri.order_item_id = oi.id AND rf.status = ANY($3) AND rf.deleted_at IS NULL
+			), 0)
+			FROM order_items oi
+			WHERE oi.id = $1 AND oi.order_id = $2 AND oi.deleted_at IS NULL
+		`, item.OrderItemId, orderId, pq.Array(reservedRefundStatuses)).Scan(&ordered, &paid, &refunded)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return 0, fmt.Errorf("order item %s not found", item.OrderItemId)
+			}
+			return 0, err
+		}
+		if remaining := ordered - refunded; item.Quantity > remaining {
+			return 0, fmt.Errorf("only %d of order item %s can be refunded", remaining, item.OrderItemId)
+		}
+		item.Amount = refundShare(paid, ordered, refunded, item.Quantity)
+		total += item.Amount
+	}
+	return total, nil
+}
+
+// checkRefundArtisan makes sure an artisan only refunds their own products:
+// the refunded items, the item of the refunded return, or every item of the
+// order when an amount is refunded.
+func checkRefundArtisan(c context.Context, tx *sqlx.Tx, orderId string, refund *models.Refund) error {
+	where := `oi.order_id = $1 AND oi.deleted_at IS NULL`
+	args := []interface{}{orderId, refund.ArtisanId}
+	switch {
+	case refund.ReturnId != "":
+		args = append(args, refund.ReturnId)
+		where += ` AND oi.id IN (SELECT order_item_id FROM return_requests WHERE id = $3)`
+	case len(refund.Items) > 0:
+		ids := make([]string, 0, len(refund.Items))
+		for _, item := range refund.Items {
+			ids = append(ids, item.OrderItemId)
+		}
+		args = append(args, pq.Array(ids))
+		where += ` AND oi.id = ANY($3)`
+	}
+
+	var total, owned int
+	err := tx.QueryRowContext(c, `
+		SELECT count(*), count(*) FILTER (WHERE p.artisan_id = $2)
+		FROM order_items oi
+		JOIN products p ON p.id = oi.product_id
+		WHERE `+where, args...).Scan(&total, &owned)
+	if err != nil {
+		return err
+	}
+	if total == 0 || owned < total {
+		return fmt.Errorf("only the artisan of the refunded products can refund them")
+	}
+	return nil
+}
+
+// CreateRefund reserves a refund against a captured payment and marks it
+// processing, ready to be sent to the provider. An artisan can only refund
+// their own products. A refund for an approved
+// return takes over the pending refund recorded at approval; any other refund
+// must fit in the captured amount minus the refunds already reserved.
+func (r *productRepo) CreateRefund(c context.Context, refund *models.Refund) (*pro.Refund, *pro.Payment, error) {
+	tx, err := r.db.BeginTxx(c, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	payment, err := scanPayment(tx.QueryRowContext(c, `
+		SELECT `+paymentColumns+`
+		FROM payments
+		WHERE id = $1 AND deleted_at IS NULL
+		FOR UPDATE
+	`, refund.PaymentId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil, fmt.Errorf("payment not found")
+		}
+		return nil, nil, err
+	}
+	if refund.OrderId != "" && refund.OrderId != payment.OrderId {
+		return nil, nil, fmt.Errorf("payment not found")
+	}
+	if payment.Status != models.PaymentCaptured && payment.Status != models.PaymentPartiallyRefunded {
+		return nil, nil, fmt.Errorf("payment is %s and cannot be refunded", payment.Status)
+	}
+	if refund.ArtisanId != "" {
+		if err := checkRefundArtisan(c, tx, payment.OrderId, refund); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var created *pro.Refund
+	if refund.ReturnId != "" {
+		created, err = scanRefund(tx.QueryRowContext(c, `
+			UPDATE refunds SET status = $1, updated_at = now()
+			WHERE return_id = $2 AND payment_id = $3 AND status = $4 AND deleted_at IS NULL
+			RETURNING `+refundColumns,
+			models.RefundProcessing, refund.ReturnId, payment.Id, models.RefundPending))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil, nil, fmt.Errorf("no pending refund for return %s", refund.ReturnId)
+			}
+			return nil, nil, fmt.Errorf("error updating refund: %v", err)
+		}
+	} else {
+		amount := refund.Amount
+		if len(refund.Items) > 0 {
+			amount, err = priceRefundItems(c, tx, payment.OrderId, refund.Items)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		var reserved models.Money
+		err = tx.QueryRowContext(c, `
+			SELECT COALESCE(SUM(amount), 0) FROM refunds
+			WHERE payment_id = $1 AND status = ANY($2) AND deleted_at IS NULL
+		`, payment.Id, pq.Array(reservedRefundStatuses)).Scan(&reserved)
+		if err != nil {
+			return nil, nil, err
+		}
+		if refundable := models.MoneyFromFloat(payment.Amount) - reserved; amount > refundable {
+			return nil, nil, fmt.Errorf("refund amount %s exceeds refundable amount %s", amount, refundable)
+		}
+
+		newRefundId := uuid.NewString()
+		created, err = scanRefund(tx.QueryRowContext(c, `
+			INSERT INTO refunds (id, payment_id, order_id, amount, status, reason)
+			VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''))
+			RETURNING `+refundColumns,
+			newRefundId, payment.Id, payment.OrderId, amount, models.RefundProcessing, refund.Reason))
+		if err != nil {
+			return nil, nil, fmt.Errorf("error inserting refund: %v", err)
+		}
+		if err := insertRefundItems(c, tx, newRefundId, refund.Items); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("error committing transaction: %v", err)
+	}
+	return created, payment, nil
+}
+
+// SettleRefund records the provider's answer to a processing refund. An
+// approved refund is completed and moves the payment to partially_refunded or
+// refunded; a declined one fails, or goes back to pending when it belongs to a
+// return so it can be retried. res is nil when the provider could not be
+// reached.
+func (r *productRepo) SettleRefund(c context.Context, refundId string, res *models.ProviderResponse) (*pro.RefundPaymentResponse, error) {
+	tx, err := r.db.BeginTxx(c, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var paymentId, status string
+	err = tx.QueryRowContext(c, `
+		SELECT payment_id, status FROM refunds WHERE id = $1 AND deleted_at IS NULL FOR UPDATE
+	`, refundId).Scan(&paymentId, &status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("refund not found")
+		}
+		return nil, err
+	}
+	if status != models.RefundProcessing {
+		return nil, fmt.Errorf("refund is already %s", status)
+	}
+
+	var responses []*models.ProviderResponse
+	if res != nil {
+		responses = append(responses, res)
+	}
+
+	if res == nil || !res.Approved {
+		refund, err := scanRefund(tx.QueryRowContext(c, `
+			UPDATE refunds
+			SET status = CASE WHEN return_id IS NULL THEN $1 ELSE $2 END, updated_at = now()
+			WHERE id = $3
+			RETURNING `+refundColumns,
+			models.RefundFailed, models.RefundPending, refundId))
+		if err != nil {
+			return nil, fmt.Errorf("error updating refund: %v", err)
+		}
+		if err := insertProviderResponses(c, tx, paymentId, responses); err != nil {
+			return nil, err
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, fmt.Errorf("error committing transaction: %v", err)
+		}
+		if err := r.attachRefundItems(c, []*pro.Refund{refund}); err != nil {
+			return nil, err
+		}
+		return &pro.RefundPaymentResponse{Refund: refund}, nil
+	}
+
+	refund, err := scanRefund(tx.QueryRowContext(c, `
+		UPDATE refunds
+		SET status = $1, transaction_id = NULLIF($2, ''), updated_at = now()
+		WHERE id = $3
+		RETURNING `+refundColumns,
+		models.RefundCompleted, res.TransactionId, refundId))
+	if err != nil {
+		return nil, fmt.Errorf("error updating refund: %v", err)
+	}
+
+	var captured, refunded models.Money
+	err = tx.QueryRowContext(c, `
+		SELECT p.amount, COALESCE(SUM(rf.amount), 0)
+		FROM payments p
+		LEFT JOIN refunds rf ON rf.payment_id = p.id AND rf.status = $2 AND rf.deleted_at IS NULL
+		WHERE p.id = $1
+		GROUP BY p.amount
+	`, paymentId, models.RefundCompleted).Scan(&captured, &refunded)
+	if err != nil {
+		return nil, err
+	}
+	to := models.PaymentPartiallyRefunded
+	if refunded >= captured {
+		to = models.PaymentRefunded
+	}
+
+	payment, err := r.transitionPayment(c, tx, &models.PaymentTransition{
+		PaymentId: paymentId,
+		To:        to,
+		Reason:    refund.Reason,
+		Responses: responses,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %v", err)
+	}
+	if err := r.attachRefundItems(c, []*pro.Refund{refund}); err != nil {
+		return nil, err
+	}
+	return &pro.RefundPaymentResponse{Refund: refund, Payment: payment}, nil
+}
+
+// attachRefundItems loads the refunded line items of the given refunds.
+func (r *productRepo) attachRefundItems(c context.Context, refunds []*pro.Refund) error {
+	if len(refunds) == 0 {
+		return nil
+	}
+	byId := make(map[string]*pro.Refund, len(refunds))
+	refundIds := make([]string, 0, len(refunds))
+	for _, refund := range refunds {
+		byId[refund.Id] = refund
+		refundIds = append(refundIds, refund.Id)
+	}
+
+	rows, err := r.db.QueryContext(c, `
+		SELECT refund_id, order_item_id, quantity, amount
+		FROM refund_items
+		WHERE refund_id = ANY($1)
+		ORDER BY created_at
+	`, pq.Array(refundIds))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item pro.RefundItem
+		var refundId string
+		if err := rows.Scan(&refundId, &item.OrderItemId, &item.Quantity, &item.Amount); err != nil {
+			return err
+		}
+		if refund, ok := byId[refundId]; ok {
+			refund.Items = append(refund.Items, &item)
+		}
+	}
+	return rows.Err()
+}
+
+func (r *productRepo) getRefunds(c context.Context, paymentId string) ([]*pro.Refund, error) {
+	rows, err := r.db.QueryContext(c, `
+		SELECT `+refundColumns+`
+		FROM refunds
+		WHERE payment_id = $1 AND deleted_at IS NULL
+		ORDER BY created_at
+	`, paymentId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var refunds []*pro.Refund
+	for rows.Next() {
+		refund, err := scanRefund(rows)
+		if err != nil {
+			return nil, err
+		}
+		refunds = append(refunds, refund)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.attachRefundItems(c, refunds); err != nil {
+		return nil, err
+	}
+	return refunds, nil
+}
@@ -0,0 +1,160 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"product-service/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	paymentRowColumns = []string{"id", "order_id", "amount", "status", "transaction_id", "payment_method", "created_at", "provider", "updated_at", "currency"}
+	refundRowColumns  = []string{"id", "payment_id", "order_id", "return_id", "amount", "status", "reason", "transaction_id", "created_at", "updated_at"}
+)
+
+func TestCreateRefund(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+	ctx := context.Background()
+	now := time.Now().Format(time.RFC3339)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM payments").
+		WithArgs("pay-1").
+		WillReturnRows(sqlmock.NewRows(paymentRowColumns).
+			AddRow("pay-1", "order-1", 50.0, "partially_refunded", "fake_000001", "credit-card", now, "fake", now, "USD"))
+	mock.ExpectQuery("SELECT oi.quantity, oi.price \\* oi.quantity - (.+) FROM order_discount_lines dl (.+) FROM order_tax_lines tl (.+) NOT tl.inclusive(.+) FROM order_items oi").
+		WithArgs("item-1", "order-1", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"quantity", "paid", "refunded"}).AddRow(3, 37.5, 1))
+	mock.ExpectQuery("SELECT COALESCE\\(SUM\\(amount\\), 0\\) FROM refunds").
+		WithArgs("pay-1", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(20.0))
+	mock.ExpectQuery("INSERT INTO refunds").
+		WithArgs(sqlmock.AnyArg(), "pay-1", "order-1", "25.00", models.RefundProcessing, "damaged").
+		WillReturnRows(sqlmock.NewRows(refundRowColumns).
+			AddRow("ref-1", "pay-1", "order-1", "", 25.0, "processing", "damaged", "", now, now))
+	mock.ExpectExec("INSERT INTO refund_items").
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), "item-1", int32(2), "25.00").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	refund, payment, err := repo.CreateRefund(ctx, &models.Refund{
+		PaymentId: "pay-1",
+		Items:     []*models.RefundItem{{OrderItemId: "item-1", Quantity: 2}},
+		Reason:    "damaged",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 25.0, refund.Amount)
+	assert.Equal(t, "fake_000001", payment.TransactionId)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM payments").
+		WithArgs("pay-1").
+		WillReturnRows(sqlmock.NewRows(paymentRowColumns).
+			AddRow("pay-1", "order-1", 50.0, "partially_refunded", "fake_000001", "credit-card", now, "fake", now, "USD"))
+	mock.ExpectQuery("SELECT COALESCE\\(SUM\\(amount\\), 0\\) FROM refunds").
+		WithArgs("pay-1", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(45.0))
+	mock.ExpectRollback()
+
+	_, _, err = repo.CreateRefund(ctx, &models.Refund{PaymentId: "pay-1", Amount: 501})
+	assert.EqualError(t, err, "refund amount 5.01 exceeds refundable amount 5.00")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM payments").
+		WithArgs("pay-1").
+		WillReturnRows(sqlmock.NewRows(paymentRowColumns).
+			AddRow("pay-1", "order-1", 50.0, "authorized", "fake_000001", "credit-card", now, "fake", now, "USD"))
+	mock.ExpectRollback()
+
+	_, _, err = repo.CreateRefund(ctx, &models.Refund{PaymentId: "pay-1", Amount: 500})
+	assert.EqualError(t, err, "payment is authorized and cannot be refunded")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSettleRefund(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+	ctx := context.Background()
+	now := time.Now().Format(time.RFC3339)
+	res := &models.ProviderResponse{Provider: "fake", Operation: "refund", Approved: true, TransactionId: "fake_000001", Amount: 3000}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT payment_id, status FROM refunds").
+		WithArgs("ref-1").
+		WillReturnRows(sqlmock.NewRows([]string{"payment_id", "status"}).AddRow("pay-1", "processing"))
+	mock.ExpectQuery("UPDATE refunds").
+		WithArgs(models.RefundCompleted, "fake_000001", "ref-1").
+		WillReturnRows(sqlmock.NewRows(refundRowColumns).
+			AddRow("ref-1", "pay-1", "order-1", "", 30.0, "completed", "", "fake_000001", now, now))
+	mock.ExpectQuery("SELECT p.amount, COALESCE\\(SUM\\(rf.amount\\), 0\\) FROM payments p").
+		WithArgs("pay-1", models.RefundCompleted).
+		WillReturnRows(sqlmock.NewRows([]string{"amount", "refunded"}).AddRow(50.0, 30.0))
+	mock.ExpectQuery("SELECT status FROM payments").
+		WithArgs("pay-1").
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("captured"))
+	mock.ExpectQuery("UPDATE payments").
+		WithArgs(models.PaymentPartiallyRefunded, "", "pay-1").
+		WillReturnRows(sqlmock.NewRows(paymentRowColumns).
+			AddRow("pay-1", "order-1", 50.0, "partially_refunded", "fake_000001", "credit-card", now, "fake", now, "USD"))
+	mock.ExpectExec("INSERT INTO payment_status_history").
+		WithArgs(sqlmock.AnyArg(), "pay-1", "captured", "partially_refunded", "").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO payment_provider_responses").
+		WithArgs(sqlmock.AnyArg(), "pay-1", "fake", "refund", true, "fake_000001", "30.00", "", nil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery("SELECT (.+) FROM refund_items").
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"refund_id", "order_item_id", "quantity", "amount"}))
+
+	response, err := repo.SettleRefund(ctx, "ref-1", res)
+	assert.NoError(t, err)
+	assert.Equal(t, "completed", response.Refund.Status)
+	assert.Equal(t, "partially_refunded", response.Payment.Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateRefundByOtherArtisan(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+	now := time.Now().Format(time.RFC3339)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM payments").
+		WithArgs("pay-1").
+		WillReturnRows(sqlmock.NewRows(paymentRowColumns).
+			AddRow("pay-1", "order-1", 50.0, "captured", "fake_000001", "credit-card", now, "fake", now, "USD"))
+	mock.ExpectQuery("SELECT count\\(\\*\\), (.+) FROM order_items oi JOIN products p (.+) AND oi.id = ANY").
+		WithArgs("order-1", "artisan-2", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"total", "owned"}).AddRow(1, 0))
+	mock.ExpectRollback()
+
+	_, _, err := repo.CreateRefund(context.Background(), &models.Refund{
+		PaymentId: "pay-1",
+		Items:     []*models.RefundItem{{OrderItemId: "item-1", Quantity: 1}},
+		ArtisanId: "artisan-2",
+	})
+	assert.EqualError(t, err, "only the artisan of the refunded products can refund them")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRefundShare(t *testing.T) {
+	// Three units listed at 10.00 with a 0.01 coupon discount.
+	paid := models.Money(2999)
+	first := refundShare(paid, 3, 0, 1)
+	rest := refundShare(paid, 3, 1, 2)
+	assert.Equal(t, models.Money(999), first)
+	assert.Equal(t, models.Money(2000), rest)
+	assert.Equal(t, paid, first+rest)
+}
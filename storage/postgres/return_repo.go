@@ -0,0 +1,289 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	pro "product-service/genproto/product_service"
+	"product-service/models"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const returnColumns = `rr.id, rr.order_id, rr.order_item_id, oi.product_id, rr.user_id, rr.quantity, rr.reason,
+		rr.status, COALESCE(rr.note, ''), round((` + orderItemPaid + `) * rr.quantity / oi.quantity, 2),
+		rr.created_at, rr.updated_at`
+
+func scanReturn(row rowScanner) (*pro.ReturnRequest, error) {
+	var ret pro.ReturnRequest
+	err := row.Scan(
+		&ret.Id,
+		&ret.OrderId,
+		&ret.OrderItemId,
+		&ret.ProductId,
+		&ret.UserId,
+		&ret.Quantity,
+		&ret.Reason,
+		&ret.Status,
+		&ret.Note,
+		&ret.RefundAmount,
+		&ret.CreatedAt,
+		&ret.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// RequestReturn opens one return request per line item. The order must belong
+// to the user and have been delivered after deliveredAfter.
+func (r *productRepo) RequestReturn(c context.Context, request *pro.RequestReturnRequest, deliveredAfter time.Time) (*pro.RequestReturnResponse, error) {
+	tx, err := r.db.BeginTxx(c, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var userId, status string
+	var deliveredAt time.Time
+	err = tx.QueryRowContext(c, `
+		SELECT user_id, status, COALESCE(delivered_at, updated_at)
+		FROM orders
+		WHERE id = $1 AND deleted_at IS NULL
+		FOR UPDATE
+	`, request.OrderId).Scan(&userId, &status, &deliveredAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("order not found")
+		}
+		return nil, err
+	}
+	if userId != request.UserId {
+		return nil, fmt.Errorf("order not found")
+	}
+	if status != "delivered" {
+		return nil, fmt.Errorf("only delivered orders can be returned")
+	}
+	if deliveredAt.Before(deliveredAfter) {
+		return nil, fmt.Errorf("return window has expired")
+	}
+
+	var returns []*pro.ReturnRequest
+	for _, item := range request.Items {
+		var productId string
+		var ordered, returned int32
+		var paid models.Money
+		err := tx.QueryRowContext(c, `
+			SELECT oi.product_id, oi.quantity, `+orderItemPaid+`,
+			COALESCE(SUM(rr.quantity) FILTER (WHERE rr.status <> 'rejected'), 0)
+			FROM order_items oi
+			LEFT JOIN return_requests rr ON rr.order_item_id = oi.id AND rr.deleted_at IS NULL
+			WHERE oi.id = $1 AND oi.order_id = $2 AND oi.deleted_at IS NULL
+			GROUP BY oi.id
+		`, item.OrderItemId, request.OrderId).Scan(&productId, &ordered, &paid, &returned)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil, fmt.Errorf("order item %s not found", item.OrderItemId)
+			}
+			return nil, err
+		}
+		if returned+item.Quantity > ordered {
+			return nil, fmt.Errorf("cannot return %d of order item %s, only %d left", item.Quantity, item.OrderItemId, ordered-returned)
+		}
+
+		newId := uuid.NewString()
+		_, err = tx.ExecContext(c, `
+			INSERT INTO return_requests (id, order_id, order_item_id, user_id, quantity, reason)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, newId, request.OrderId, item.OrderItemId, request.UserId, item.Quantity, item.Reason)
+		if err != nil {
+			return nil, fmt.Errorf("error inserting return request: %v", err)
+		}
+
+		createdAt := time.Now().Format("2006-01-02 15:04:05")
+		returns = append(returns, &pro.ReturnRequest{
+			Id:           newId,
+			OrderId:      request.OrderId,
+			OrderItemId:  item.OrderItemId,
+			ProductId:    productId,
+			UserId:       request.UserId,
+			Quantity:     item.Quantity,
+			Reason:       item.Reason,
+			Status:       "requested",
+			RefundAmount: paid.Share(item.Quantity, ordered).Float64(),
+			CreatedAt:    createdAt,
+			UpdatedAt:    createdAt,
+		})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %v", err)
+	}
+	return &pro.RequestReturnResponse{Returns: returns}, nil
+}
+
+// ResolveReturn approves or rejects a pending return request of one of the
+// artisan's products. Approving puts the stock back, records a pending refund
+// against the order's payment and marks the order returned once every item
+// has been returned.
+func (r *productRepo) ResolveReturn(c context.Context, request *pro.ResolveReturnRequest, approve bool) (*pro.ResolveReturnResponse, error) {
+	tx, err := r.db.BeginTxx(c, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var status, orderId, orderItemId, productId, artisanId string
+	var quantity, ordered, refunded int32
+	var paid models.Money
+	err = tx.QueryRowContext(c, `
+		SELECT rr.status, rr.order_id, rr.order_item_id, oi.product_id, rr.quantity, oi.quantity, `+orderItemPaid+`,
+		COALESCE((
+			SELECT SUM(ri.quantity) FROM refund_items ri
+			JOIN refunds rf ON rf.id = ri.refund_id
+			WHERE ri.order_item_id = oi.id AND rf.status = ANY($2) AND rf.deleted_at IS NULL
+		), 0), p.artisan_id
+		FROM return_requests rr
+		JOIN order_items oi ON oi.id = rr.order_item_id
+		JOIN products p ON p.id = oi.product_id
+		WHERE rr.id = $1 AND rr.deleted_at IS NULL
+		FOR UPDATE OF rr
+	`, request.ReturnId, pq.Array(reservedRefundStatuses)).Scan(&status, &orderId, &orderItemId, &productId, &quantity, &ordered, &paid, &refunded, &artisanId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("return request not found")
+		}
+		return nil, err
+	}
+	if artisanId != request.ArtisanId {
+		return nil, fmt.Errorf("return request not found")
+	}
+	if status != "requested" {
+		return nil, fmt.Errorf("return request is already %s", status)
+	}
+
+	newStatus := "rejected"
+	if approve {
+		newStatus = "approved"
+	}
+	_, err = tx.ExecContext(c, `
+		UPDATE return_requests
+		SET status = $1, note = $2, resolved_by = $3, resolved_at = now(), updated_at = now()
+		WHERE id = $4
+	`, newStatus, request.Note, request.ArtisanId, request.ReturnId)
+	if err != nil {
+		return nil, fmt.Errorf("error updating return request: %v", err)
+	}
+
+	if approve {
+		_, err = tx.ExecContext(c, `
+			UPDATE products SET quantity = quantity + $1, updated_at = now() WHERE id = $2
+		`, quantity, productId)
+		if err != nil {
+			return nil, fmt.Errorf("error restocking product: %v", err)
+		}
+
+		var paymentId string
+		err = tx.QueryRowContext(c, `
+			SELECT id FROM payments
+			WHERE order_id = $1 AND status IN ('captured', 'partially_refunded') AND deleted_at IS NULL
+			ORDER BY created_at DESC
+			LIMIT 1
+		`, orderId).Scan(&paymentId)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, err
+		}
+		if err == nil {
+			refundId := uuid.NewString()
+			amount := refundShare(paid, ordered, refunded, quantity)
+			_, err = tx.ExecContext(c, `
+				INSERT INTO refunds (id, payment_id, order_id, return_id, amount, status, reason)
+				VALUES ($1, $2, $3, $4, $5, 'pending', 'return')
+			`, refundId, paymentId, orderId, request.ReturnId, amount)
+			if err != nil {
+				return nil, fmt.Errorf("error inserting refund: %v", err)
+			}
+			err = insertRefundItems(c, tx, refundId, []*models.RefundItem{{OrderItemId: orderItemId, Quantity: quantity, Amount: amount}})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		_, err = tx.ExecContext(c, `
+			UPDATE orders SET status = 'returned', updated_at = now()
+			WHERE id = $1 AND NOT EXISTS (
+				SELECT 1 FROM order_items oi
+				WHERE oi.order_id = $1 AND oi.deleted_at IS NULL AND oi.quantity > (
+					SELECT COALESCE(SUM(rr.quantity), 0) FROM return_requests rr
+					WHERE rr.order_item_id = oi.id AND rr.status = 'approved' AND rr.deleted_at IS NULL
+				)
+			)
+		`, orderId)
+		if err != nil {
+			return nil, fmt.Errorf("error updating order status: %v", err)
+		}
+	}
+
+	ret, err := scanReturn(tx.QueryRowContext(c, `
+		SELECT `+returnColumns+`
+		FROM return_requests rr
+		JOIN order_items oi ON oi.id = rr.order_item_id
+		WHERE rr.id = $1
+	`, request.ReturnId))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %v", err)
+	}
+	return &pro.ResolveReturnResponse{Return: ret}, nil
+}
+
+func (r *productRepo) GetReturns(c context.Context, request *pro.GetReturnsRequest) (*pro.GetReturnsResponse, error) {
+	query := `
+		SELECT ` + returnColumns + `
+		FROM return_requests rr
+		JOIN order_items oi ON oi.id = rr.order_item_id
+		JOIN products p ON p.id = oi.product_id
+		WHERE rr.deleted_at IS NULL
+	`
+	var args []interface{}
+	filters := []struct{ column, value string }{
+		{"rr.user_id", request.UserId},
+		{"p.artisan_id", request.ArtisanId},
+		{"rr.order_id", request.OrderId},
+		{"rr.status", request.Status},
+	}
+	for _, filter := range filters {
+		if filter.value == "" {
+			continue
+		}
+		args = append(args, filter.value)
+		query += fmt.Sprintf(" AND %s = $%d", filter.column, len(args))
+	}
+	query += " ORDER BY rr.created_at DESC"
+
+	rows, err := r.db.QueryContext(c, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var returns []*pro.ReturnRequest
+	for rows.Next() {
+		ret, err := scanReturn(rows)
+		if err != nil {
+			return nil, err
+		}
+		returns = append(returns, ret)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &pro.GetReturnsResponse{Returns: returns}, nil
+}
@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pro "product-service/genproto/product_service"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestReturnOutsideWindow(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+	ctx := context.Background()
+	request := &pro.RequestReturnRequest{
+		OrderId: "order-1",
+		UserId:  "user-1",
+		Items:   []*pro.ReturnItem{{OrderItemId: "item-1", Quantity: 1, Reason: "broken"}},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT user_id, status, (.+) FROM orders").
+		WithArgs(request.OrderId).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "status", "delivered_at"}).
+			AddRow("user-1", "delivered", time.Now().Add(-60*24*time.Hour)))
+	mock.ExpectRollback()
+
+	response, err := repo.RequestReturn(ctx, request, time.Now().Add(-30*24*time.Hour))
+	assert.Nil(t, response)
+	assert.EqualError(t, err, "return window has expired")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestApproveReturn(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+	ctx := context.Background()
+	request := &pro.ResolveReturnRequest{ReturnId: "ret-1", ArtisanId: "art-1", Note: "ok"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT rr.status, rr.order_id, rr.order_item_id, oi.product_id, rr.quantity, oi.quantity, (.+) FROM order_discount_lines dl (.+) FROM return_requests rr").
+		WithArgs(request.ReturnId, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"status", "order_id", "order_item_id", "product_id", "quantity", "ordered", "paid", "refunded", "artisan_id"}).
+			AddRow("requested", "order-1", "item-1", "prod-1", 2, 3, 45.0, 0, "art-1"))
+	mock.ExpectExec("UPDATE return_requests").
+		WithArgs("approved", request.Note, request.ArtisanId, request.ReturnId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE products SET quantity = quantity \\+ \\$1").
+		WithArgs(int32(2), "prod-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT id FROM payments").
+		WithArgs("order-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("pay-1"))
+	mock.ExpectExec("INSERT INTO refunds").
+		WithArgs(sqlmock.AnyArg(), "pay-1", "order-1", request.ReturnId, "30.00").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO refund_items").
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), "item-1", int32(2), "30.00").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE orders SET status = 'returned'").
+		WithArgs("order-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	now := time.Now().Format(time.RFC3339)
+	mock.ExpectQuery("SELECT (.+) FROM return_requests rr").
+		WithArgs(request.ReturnId).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "order_item_id", "product_id", "user_id", "quantity", "reason", "status", "note", "refund_amount", "created_at", "updated_at"}).
+			AddRow("ret-1", "order-1", "item-1", "prod-1", "user-1", 2, "broken", "approved", "ok", 30.0, now, now))
+	mock.ExpectCommit()
+
+	response, err := repo.ResolveReturn(ctx, request, true)
+	assert.NoError(t, err)
+	assert.NotNil(t, response)
+	assert.Equal(t, "approved", response.Return.Status)
+	assert.Equal(t, 30.0, response.Return.RefundAmount)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,574 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	pro "product-service/genproto/product_service"
+	"product-service/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const ratingColumns = `r.id, r.product_id, r.user_id, r.rating, COALESCE(r.comment, ''), r.verified_purchase, r.status,
+	r.helpful_count, r.unhelpful_count, r.created_at, r.updated_at`
+
+func scanRating(row rowScanner, extra ...interface{}) (*pro.Rating, error) {
+	var rating pro.Rating
+	dest := []interface{}{&rating.Id, &rating.ProductId, &rating.UserId, &rating.Rating, &rating.Comment,
+		&rating.VerifiedPurchase, &rating.Status, &rating.HelpfulCount, &rating.UnhelpfulCount, &rating.CreatedAt, &rating.UpdatedAt}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	return &rating, nil
+}
+
+// lockRatingSummary locks the rating summary of a product, creating it the
+// first time, so that changes to the product's ratings apply one at a time.
+func lockRatingSummary(c context.Context, tx *sql.Tx, productId string) error {
+	_, err := tx.ExecContext(c, `
+		INSERT INTO product_rating_summaries (product_id)
+		SELECT id FROM products WHERE id = $1
+		ON CONFLICT (product_id) DO NOTHING
+	`, productId)
+	if err != nil {
+		return fmt.Errorf("error creating rating summary: %v", err)
+	}
+	var locked string
+	err = tx.QueryRowContext(c, `
+		SELECT product_id FROM product_rating_summaries WHERE product_id = $1 FOR UPDATE
+	`, productId).Scan(&locked)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("product not found")
+	}
+	return err
+}
+
+// refreshRatingSummary recounts the visible ratings of a product locked with
+// lockRatingSummary.
+func refreshRatingSummary(c context.Context, tx *sql.Tx, productId string) error {
+	_, err := tx.ExecContext(c, `
+		UPDATE product_rating_summaries s SET
+		rating_count = a.rating_count,
+		rating_average = a.rating_average,
+		stars_1 = a.stars_1,
+		stars_2 = a.stars_2,
+		stars_3 = a.stars_3,
+		stars_4 = a.stars_4,
+		stars_5 = a.stars_5,
+		updated_at = now()
+		FROM (
+			SELECT count(*) AS rating_count, COALESCE(round(avg(rating), 2), 0) AS rating_average,
+			count(*) FILTER (WHERE rating = 1) AS stars_1,
+			count(*) FILTER (WHERE rating = 2) AS stars_2,
+			count(*) FILTER (WHERE rating = 3) AS stars_3,
+			count(*) FILTER (WHERE rating = 4) AS stars_4,
+			count(*) FILTER (WHERE rating = 5) AS stars_5
+			FROM ratings
+			WHERE product_id = $1 AND deleted_at IS NULL AND status = 'visible'
+		) a
+		WHERE s.product_id = $1
+	`, productId)
+	if err != nil {
+		return fmt.Errorf("error updating rating summary: %v", err)
+	}
+	return nil
+}
+
+// AddRating records the user's review of a product with the given status,
+// replacing the one they left before, and updates the product's rating
+// summary. A review hidden by a moderator stays hidden when edited. The
+// review is a verified purchase when the user has a delivered order
+// containing the product.
+func (r *productRepo) AddRating(c context.Context, rating *pro.AddRatingRequest, status string) (*pro.AddRatingResponse, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockRatingSummary(c, tx, rating.ProductId); err != nil {
+		return nil, err
+	}
+
+	res := pro.AddRatingResponse{ProductId: rating.ProductId, UserId: rating.UserId, Rating: rating.Rating, Comment: rating.Comment}
+	err = tx.QueryRowContext(c, `
+		INSERT INTO ratings (id, product_id, user_id, rating, comment, status, verified_purchase)
+		SELECT $1, p.id, $3, $4, $5, $6, EXISTS (
+			SELECT 1 FROM orders o
+			JOIN order_items oi ON oi.order_id = o.id
+			WHERE o.user_id = $3 AND oi.product_id = p.id
+			AND o.status = 'delivered' AND o.deleted_at IS NULL AND oi.deleted_at IS NULL
+		)
+		FROM products p
+		WHERE p.id = $2 AND p.deleted_at IS NULL
+		ON CONFLICT (product_id, user_id) WHERE deleted_at IS NULL DO UPDATE SET
+		rating = EXCLUDED.rating,
+		comment = EXCLUDED.comment,
+		status = CASE WHEN ratings.status = 'hidden' THEN ratings.status ELSE EXCLUDED.status END,
+		verified_purchase = EXCLUDED.verified_purchase,
+		updated_at = now()
+		RETURNING id, verified_purchase, status, created_at, updated_at
+	`, uuid.NewString(), rating.ProductId, rating.UserId, int32(rating.Rating), rating.Comment, status).
+		Scan(&res.Id, &res.VerifiedPurchase, &res.Status, &res.CreatedAt, &res.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("product not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error saving rating: %v", err)
+	}
+
+	if err := refreshRatingSummary(c, tx, rating.ProductId); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ratingSortOrders are the ORDER BY clauses of the sort_by options of
+// GetRatings. The most helpful reviews are those with the most net helpful
+// votes.
+var ratingSortOrders = map[string]string{
+	"":                       "r.created_at DESC, r.id",
+	models.RatingSortNewest:  "r.created_at DESC, r.id",
+	models.RatingSortHighest: "r.rating DESC, r.created_at DESC, r.id",
+	models.RatingSortLowest:  "r.rating, r.created_at DESC, r.id",
+	models.RatingSortHelpful: "r.helpful_count - r.unhelpful_count DESC, r.helpful_count DESC, r.created_at DESC, r.id",
+}
+
+// GetRatings returns a page of the visible reviews of a product with the
+// artisan's replies, and how many visible reviews there are in total.
+func (r *productRepo) GetRatings(c context.Context, request *pro.GetRatingsRequest) (*pro.GetRatingsResponse, error) {
+	order, ok := ratingSortOrders[request.SortBy]
+	if !ok {
+		return nil, fmt.Errorf("invalid sort: %s", request.SortBy)
+	}
+	rows, err := r.db.QueryContext(c, `
+		SELECT `+ratingColumns+`, count(*) OVER ()
+		FROM ratings r
+		WHERE r.product_id = $1 AND r.deleted_at IS NULL AND r.status = 'visible'
+		ORDER BY `+order+`
+		LIMIT $2 OFFSET $3
+	`, request.ProductId, request.Limit, request.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("error getting ratings: %v", err)
+	}
+	defer rows.Close()
+
+	res := &pro.GetRatingsResponse{Ratings: []*pro.Rating{}}
+	for rows.Next() {
+		rating, err := scanRating(rows, &res.Total)
+		if err != nil {
+			return nil, err
+		}
+		res.Ratings = append(res.Ratings, rating)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	if err := r.attachRatingReplies(c, res.Ratings); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// attachRatingReplies loads the artisan replies of the given reviews.
+func (r *productRepo) attachRatingReplies(c context.Context, ratings []*pro.Rating) error {
+	if len(ratings) == 0 {
+		return nil
+	}
+	byId := make(map[string]*pro.Rating, len(ratings))
+	ids := make([]string, 0, len(ratings))
+	for _, rating := range ratings {
+		byId[rating.Id] = rating
+		ids = append(ids, rating.Id)
+	}
+
+	rows, err := r.db.QueryContext(c, `
+		SELECT rating_id, artisan_id, body, created_at, updated_at
+		FROM rating_replies
+		WHERE rating_id = ANY($1)
+	`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("error getting rating replies: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var ratingId string
+		var reply pro.RatingReply
+		if err := rows.Scan(&ratingId, &reply.ArtisanId, &reply.Body, &reply.CreatedAt, &reply.UpdatedAt); err != nil {
+			return err
+		}
+		if rating, ok := byId[ratingId]; ok {
+			rating.Reply = &reply
+		}
+	}
+	return rows.Err()
+}
+
+// ReplyToRating posts the artisan's public reply to a review of one of their
+// products, replacing the reply they posted before.
+func (r *productRepo) ReplyToRating(c context.Context, request *pro.ReplyToRatingRequest) (*pro.RatingReply, error) {
+	var artisanId string
+	err := r.db.QueryRowContext(c, `
+		SELECT p.artisan_id FROM ratings r
+		JOIN products p ON p.id = r.product_id
+		WHERE r.id = $1 AND r.deleted_at IS NULL AND r.status = 'visible'
+	`, request.RatingId).Scan(&artisanId)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("rating not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if artisanId != request.ArtisanId {
+		return nil, fmt.Errorf("only the artisan of the product can reply to its reviews")
+	}
+
+	var reply pro.RatingReply
+	err = r.db.QueryRowContext(c, `
+		INSERT INTO rating_replies (rating_id, artisan_id, body)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (rating_id) DO UPDATE SET body = EXCLUDED.body, updated_at = now()
+		RETURNING artisan_id, body, created_at, updated_at
+	`, request.RatingId, request.ArtisanId, request.Body).Scan(&reply.ArtisanId, &reply.Body, &reply.CreatedAt, &reply.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("error saving rating reply: %v", err)
+	}
+	return &reply, nil
+}
+
+// VoteRating records whether the user found a review helpful, replacing
+// their earlier vote, and returns the review's vote counts. Users cannot
+// vote on their own reviews.
+func (r *productRepo) VoteRating(c context.Context, request *pro.VoteRatingRequest) (*pro.VoteRatingResponse, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var author string
+	err = tx.QueryRowContext(c, `
+		SELECT user_id FROM ratings WHERE id = $1 AND deleted_at IS NULL AND status = 'visible' FOR UPDATE
+	`, request.RatingId).Scan(&author)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("rating not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if author == request.UserId {
+		return nil, fmt.Errorf("you cannot vote on your own review")
+	}
+
+	_, err = tx.ExecContext(c, `
+		INSERT INTO rating_votes (rating_id, user_id, helpful)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (rating_id, user_id) DO UPDATE SET helpful = EXCLUDED.helpful, updated_at = now()
+	`, request.RatingId, request.UserId, request.Helpful)
+	if err != nil {
+		return nil, fmt.Errorf("error saving vote: %v", err)
+	}
+
+	res := pro.VoteRatingResponse{RatingId: request.RatingId}
+	err = tx.QueryRowContext(c, `
+		UPDATE ratings SET
+		helpful_count = (SELECT count(*) FROM rating_votes WHERE rating_id = $1 AND helpful),
+		unhelpful_count = (SELECT count(*) FROM rating_votes WHERE rating_id = $1 AND NOT helpful)
+		WHERE id = $1
+		RETURNING helpful_count, unhelpful_count
+	`, request.RatingId).Scan(&res.HelpfulCount, &res.UnhelpfulCount)
+	if err != nil {
+		return nil, fmt.Errorf("error counting votes: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ReportRating flags a visible review for a moderator. Users cannot report
+// their own reviews, nor report one again before it has been moderated.
+func (r *productRepo) ReportRating(c context.Context, request *pro.ReportRatingRequest) error {
+	var author string
+	err := r.db.QueryRowContext(c, `
+		SELECT user_id FROM ratings WHERE id = $1 AND deleted_at IS NULL AND status = 'visible'
+	`, request.RatingId).Scan(&author)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("rating not found")
+	}
+	if err != nil {
+		return err
+	}
+	if author == request.UserId {
+		return fmt.Errorf("you cannot report your own review")
+	}
+
+	res, err := r.db.ExecContext(c, `
+		INSERT INTO rating_reports (id, rating_id, user_id, reason)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (rating_id, user_id) WHERE resolved_at IS NULL DO NOTHING
+	`, uuid.NewString(), request.RatingId, request.UserId, request.Reason)
+	if err != nil {
+		return fmt.Errorf("error reporting rating: %v", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("you have already reported this review")
+	}
+	return nil
+}
+
+// GetModerationQueue returns the reviews waiting for a moderator: those held
+// back on submission and those with open reports, most reported first.
+func (r *productRepo) GetModerationQueue(c context.Context, limit, offset int32) ([]*pro.ModerationItem, error) {
+	rows, err := r.db.QueryContext(c, `
+		SELECT `+ratingColumns+`, count(rr.id),
+		COALESCE(array_agg(rr.reason ORDER BY rr.created_at) FILTER (WHERE rr.reason <> ''), '{}')
+		FROM ratings r
+		LEFT JOIN rating_reports rr ON rr.rating_id = r.id AND rr.resolved_at IS NULL
+		WHERE r.deleted_at IS NULL
+		AND (r.status = 'pending' OR (r.status = 'visible' AND rr.id IS NOT NULL))
+		GROUP BY r.id
+		ORDER BY count(rr.id) DESC, r.created_at, r.id
+		LIMIT $1 OFFSET $2
+	`, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("error getting moderation queue: %v", err)
+	}
+	defer rows.Close()
+
+	items := []*pro.ModerationItem{}
+	for rows.Next() {
+		var item pro.ModerationItem
+		var reasons []string
+		rating, err := scanRating(rows, &item.ReportCount, pq.Array(&reasons))
+		if err != nil {
+			return nil, err
+		}
+		item.Rating = rating
+		item.ReportReasons = reasons
+		items = append(items, &item)
+	}
+	return items, rows.Err()
+}
+
+// moderationStatuses are the statuses the moderation actions leave a review
+// in. Deleting keeps the status the review had.
+var moderationStatuses = map[string]string{
+	models.ModerationApprove: models.RatingVisible,
+	models.ModerationHide:    models.RatingHidden,
+	models.ModerationDelete:  "",
+}
+
+// ModerateRating applies a moderator's decision to a review, closes its open
+// reports and updates the product's rating summary. Hiding or deleting a
+// review removes its photos; the keys they were stored under are returned so
+// the files can be deleted too.
+func (r *productRepo) ModerateRating(c context.Context, request *pro.ModerateRatingRequest) (*pro.Rating, []string, error) {
+	status, ok := moderationStatuses[request.Action]
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid moderation action: %s", request.Action)
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	var productId string
+	err = tx.QueryRowContext(c, `
+		SELECT product_id FROM ratings WHERE id = $1 AND deleted_at IS NULL
+	`, request.RatingId).Scan(&productId)
+	if err == sql.ErrNoRows {
+		return nil, nil, fmt.Errorf("rating not found")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := lockRatingSummary(c, tx, productId); err != nil {
+		return nil, nil, err
+	}
+
+	rating, err := scanRating(tx.QueryRowContext(c, `
+		UPDATE ratings r SET
+		status = COALESCE(NULLIF($2, ''), r.status),
+		deleted_at = CASE WHEN $3 THEN now() ELSE NULL END,
+		moderation_reason = $4,
+		moderated_by = $5,
+		moderated_at = now()
+		WHERE r.id = $1 AND r.deleted_at IS NULL
+		RETURNING `+ratingColumns+`
+	`, request.RatingId, status, request.Action == models.ModerationDelete, request.Reason, request.ModeratorId))
+	if err == sql.ErrNoRows {
+		return nil, nil, fmt.Errorf("rating not found")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("error moderating rating: %v", err)
+	}
+
+	_, err = tx.ExecContext(c, `
+		UPDATE rating_reports SET resolved_at = now() WHERE rating_id = $1 AND resolved_at IS NULL
+	`, request.RatingId)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error resolving reports: %v", err)
+	}
+	if err := refreshRatingSummary(c, tx, productId); err != nil {
+		return nil, nil, err
+	}
+
+	var photoKeys []string
+	if request.Action != models.ModerationApprove {
+		photoKeys, err = deleteRatingPhotos(c, tx, request.RatingId)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, nil, err
+	}
+	return rating, photoKeys, nil
+}
+
+// deleteRatingPhotos removes every photo of a review and returns the keys
+// they were stored under.
+func deleteRatingPhotos(c context.Context, tx *sql.Tx, ratingId string) ([]string, error) {
+	rows, err := tx.QueryContext(c, `
+		DELETE FROM rating_photos WHERE rating_id = $1 RETURNING storage_key
+	`, ratingId)
+	if err != nil {
+		return nil, fmt.Errorf("error deleting rating photos: %v", err)
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+type rowQueryer interface {
+	QueryRowContext(c context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// checkRatingPhoto checks that userId can add another photo to a review:
+// the review must be theirs, visible, and have fewer than maxPhotos photos.
+// lock is appended to the query of the review.
+func checkRatingPhoto(c context.Context, q rowQueryer, ratingId, userId string, maxPhotos int, lock string) error {
+	var author, status string
+	err := q.QueryRowContext(c, `
+		SELECT user_id, status FROM ratings WHERE id = $1 AND deleted_at IS NULL
+	`+lock, ratingId).Scan(&author, &status)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("rating not found")
+	}
+	if err != nil {
+		return err
+	}
+	if author != userId {
+		return fmt.Errorf("you can only add photos to your own review")
+	}
+	if status != models.RatingVisible {
+		return fmt.Errorf("photos can only be added to published reviews")
+	}
+
+	var count int
+	if err := q.QueryRowContext(c, `SELECT count(*) FROM rating_photos WHERE rating_id = $1`, ratingId).Scan(&count); err != nil {
+		return err
+	}
+	if count >= maxPhotos {
+		return fmt.Errorf("a review can have at most %d photos", maxPhotos)
+	}
+	return nil
+}
+
+// CanAddRatingPhoto checks whether userId can add a photo to a review, so the
+// photo is only stored for uploads AddRatingPhoto is expected to accept.
+func (r *productRepo) CanAddRatingPhoto(c context.Context, ratingId, userId string, maxPhotos int) error {
+	return checkRatingPhoto(c, r.db, ratingId, userId, maxPhotos, "")
+}
+
+// AddRatingPhoto attaches a stored photo to a review. Only the author of a
+// visible review can add photos, and at most maxPhotos of them.
+func (r *productRepo) AddRatingPhoto(c context.Context, photo *models.RatingPhoto, userId string, maxPhotos int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := checkRatingPhoto(c, tx, photo.RatingId, userId, maxPhotos, " FOR UPDATE"); err != nil {
+		return err
+	}
+
+	err = tx.QueryRowContext(c, `
+		INSERT INTO rating_photos (id, rating_id, storage_key, content_type, size_bytes)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at
+	`, photo.Id, photo.RatingId, photo.Key, photo.ContentType, photo.SizeBytes).Scan(&photo.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("error saving rating photo: %v", err)
+	}
+	return tx.Commit()
+}
+
+// DeleteRatingPhoto removes a photo from the user's review and returns the
+// key it is stored under.
+func (r *productRepo) DeleteRatingPhoto(c context.Context, photoId, userId string) (string, error) {
+	var key string
+	err := r.db.QueryRowContext(c, `
+		DELETE FROM rating_photos rp
+		USING ratings r
+		WHERE rp.id = $1 AND r.id = rp.rating_id AND r.user_id = $2
+		RETURNING rp.storage_key
+	`, photoId, userId).Scan(&key)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("photo not found")
+	}
+	if err != nil {
+		return "", fmt.Errorf("error deleting rating photo: %v", err)
+	}
+	return key, nil
+}
+
+// GetRatingPhotos returns the photos of the given reviews by review id, in
+// the order they were added.
+func (r *productRepo) GetRatingPhotos(c context.Context, ratingIds []string) (map[string][]*models.RatingPhoto, error) {
+	photos := make(map[string][]*models.RatingPhoto)
+	if len(ratingIds) == 0 {
+		return photos, nil
+	}
+	rows, err := r.db.QueryContext(c, `
+		SELECT id, rating_id, storage_key, content_type, size_bytes, created_at
+		FROM rating_photos
+		WHERE rating_id = ANY($1)
+		ORDER BY created_at, id
+	`, pq.Array(ratingIds))
+	if err != nil {
+		return nil, fmt.Errorf("error getting rating photos: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var photo models.RatingPhoto
+		if err := rows.Scan(&photo.Id, &photo.RatingId, &photo.Key, &photo.ContentType, &photo.SizeBytes, &photo.CreatedAt); err != nil {
+			return nil, err
+		}
+		photos[photo.RatingId] = append(photos[photo.RatingId], &photo)
+	}
+	return photos, rows.Err()
+}
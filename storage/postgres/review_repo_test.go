@@ -0,0 +1,354 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pro "product-service/genproto/product_service"
+	"product-service/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var ratingRowColumns = []string{"id", "product_id", "user_id", "rating", "comment", "verified_purchase", "status", "helpful_count", "unhelpful_count", "created_at", "updated_at"}
+
+func TestAddRatingVerifiedPurchase(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+	now := time.Now().Format(time.RFC3339)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO product_rating_summaries (.+) ON CONFLICT \\(product_id\\) DO NOTHING").
+		WithArgs("prod-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT product_id FROM product_rating_summaries (.+) FOR UPDATE").
+		WithArgs("prod-1").
+		WillReturnRows(sqlmock.NewRows([]string{"product_id"}).AddRow("prod-1"))
+	mock.ExpectQuery("INSERT INTO ratings (.+) o.status = 'delivered' (.+) ON CONFLICT \\(product_id, user_id\\) WHERE deleted_at IS NULL DO UPDATE").
+		WithArgs(sqlmock.AnyArg(), "prod-1", "user-1", int32(4), "Lovely glaze", models.RatingVisible).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "verified_purchase", "status", "created_at", "updated_at"}).AddRow("rating-1", true, models.RatingVisible, now, now))
+	mock.ExpectExec("UPDATE product_rating_summaries s SET (.+) FROM ratings WHERE product_id = \\$1 AND deleted_at IS NULL").
+		WithArgs("prod-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	request := &pro.AddRatingRequest{ProductId: "prod-1", UserId: "user-1", Rating: 4, Comment: "Lovely glaze"}
+	res, err := repo.AddRating(context.Background(), request, models.RatingVisible)
+	assert.NoError(t, err)
+	assert.Equal(t, "rating-1", res.Id)
+	assert.True(t, res.VerifiedPurchase)
+	assert.Equal(t, 4.0, res.Rating)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddRatingProductNotFound(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO product_rating_summaries").
+		WithArgs("prod-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT product_id FROM product_rating_summaries").
+		WithArgs("prod-1").
+		WillReturnRows(sqlmock.NewRows([]string{"product_id"}))
+	mock.ExpectRollback()
+
+	_, err := repo.AddRating(context.Background(), &pro.AddRatingRequest{ProductId: "prod-1", UserId: "user-1", Rating: 5}, models.RatingVisible)
+	assert.EqualError(t, err, "product not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRatings(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+	now := time.Now().Format(time.RFC3339)
+
+	mock.ExpectQuery("SELECT (.+) FROM ratings r WHERE r.product_id = \\$1 AND r.deleted_at IS NULL AND r.status = 'visible' ORDER BY r.created_at DESC, r.id LIMIT \\$2 OFFSET \\$3").
+		WithArgs("prod-1", int32(20), int32(0)).
+		WillReturnRows(sqlmock.NewRows(append(ratingRowColumns, "total")).
+			AddRow("rating-1", "prod-1", "user-1", 5, "Great", true, "visible", 0, 0, now, now, 2).
+			AddRow("rating-2", "prod-1", "user-2", 2, "", false, "visible", 0, 0, now, now, 2))
+	mock.ExpectQuery("SELECT rating_id, artisan_id, body, (.+) FROM rating_replies").
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"rating_id", "artisan_id", "body", "created_at", "updated_at"}))
+
+	res, err := repo.GetRatings(context.Background(), &pro.GetRatingsRequest{ProductId: "prod-1", Limit: 20})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(res.Ratings))
+	assert.True(t, res.Ratings[0].VerifiedPurchase)
+	assert.Equal(t, 2.0, res.Ratings[1].Rating)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRatingsHelpfulPage(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+	now := time.Now().Format(time.RFC3339)
+
+	mock.ExpectQuery("SELECT (.+) FROM ratings r WHERE r.product_id = (.+) AND r.deleted_at IS NULL AND r.status = 'visible' ORDER BY r.helpful_count - r.unhelpful_count DESC").
+		WithArgs("prod-1", int32(2), int32(2)).
+		WillReturnRows(sqlmock.NewRows(append(ratingRowColumns, "total")).
+			AddRow("rating-3", "prod-1", "user-3", 4, "Nice", true, "visible", 7, 1, now, now, 5).
+			AddRow("rating-4", "prod-1", "user-4", 2, "", false, "visible", 2, 0, now, now, 5))
+	mock.ExpectQuery("SELECT rating_id, artisan_id, body, (.+) FROM rating_replies").
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"rating_id", "artisan_id", "body", "created_at", "updated_at"}).
+			AddRow("rating-4", "art-1", "Sorry to hear that", now, now))
+
+	request := &pro.GetRatingsRequest{ProductId: "prod-1", Limit: 2, Offset: 2, SortBy: models.RatingSortHelpful}
+	res, err := repo.GetRatings(context.Background(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(5), res.Total)
+	assert.Equal(t, 2, len(res.Ratings))
+	assert.Equal(t, int32(7), res.Ratings[0].HelpfulCount)
+	assert.Nil(t, res.Ratings[0].Reply)
+	assert.Equal(t, "Sorry to hear that", res.Ratings[1].Reply.Body)
+
+	_, err = repo.GetRatings(context.Background(), &pro.GetRatingsRequest{ProductId: "prod-1", SortBy: "oldest"})
+	assert.EqualError(t, err, "invalid sort: oldest")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReplyToRatingNotOwner(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+
+	mock.ExpectQuery("SELECT p.artisan_id FROM ratings r JOIN products p").
+		WithArgs("rating-1").
+		WillReturnRows(sqlmock.NewRows([]string{"artisan_id"}).AddRow("art-1"))
+
+	_, err := repo.ReplyToRating(context.Background(), &pro.ReplyToRatingRequest{RatingId: "rating-1", ArtisanId: "art-2", Body: "Thanks"})
+	assert.EqualError(t, err, "only the artisan of the product can reply to its reviews")
+}
+
+func TestVoteRating(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT user_id FROM ratings WHERE id = (.+) FOR UPDATE").
+		WithArgs("rating-1").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow("user-1"))
+	mock.ExpectExec("INSERT INTO rating_votes (.+) ON CONFLICT \\(rating_id, user_id\\) DO UPDATE").
+		WithArgs("rating-1", "user-2", true).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("UPDATE ratings SET helpful_count (.+) RETURNING helpful_count, unhelpful_count").
+		WithArgs("rating-1").
+		WillReturnRows(sqlmock.NewRows([]string{"helpful_count", "unhelpful_count"}).AddRow(4, 1))
+	mock.ExpectCommit()
+
+	res, err := repo.VoteRating(context.Background(), &pro.VoteRatingRequest{RatingId: "rating-1", UserId: "user-2", Helpful: true})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(4), res.HelpfulCount)
+	assert.Equal(t, int32(1), res.UnhelpfulCount)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReportOwnRating(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+
+	mock.ExpectQuery("SELECT user_id FROM ratings WHERE id = (.+) AND status = 'visible'").
+		WithArgs("rating-1").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow("user-1"))
+
+	err := repo.ReportRating(context.Background(), &pro.ReportRatingRequest{RatingId: "rating-1", UserId: "user-1"})
+	assert.EqualError(t, err, "you cannot report your own review")
+}
+
+func TestModerateRatingHide(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+	now := time.Now().Format(time.RFC3339)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT product_id FROM ratings WHERE id").
+		WithArgs("rating-1").
+		WillReturnRows(sqlmock.NewRows([]string{"product_id"}).AddRow("prod-1"))
+	mock.ExpectExec("INSERT INTO product_rating_summaries").
+		WithArgs("prod-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT product_id FROM product_rating_summaries").
+		WithArgs("prod-1").
+		WillReturnRows(sqlmock.NewRows([]string{"product_id"}).AddRow("prod-1"))
+	mock.ExpectQuery("UPDATE ratings r SET (.+) RETURNING").
+		WithArgs("rating-1", models.RatingHidden, false, "Spam", "admin-1").
+		WillReturnRows(sqlmock.NewRows(ratingRowColumns).AddRow("rating-1", "prod-1", "user-1", 1, "Buy now", false, "hidden", 0, 0, now, now))
+	mock.ExpectExec("UPDATE rating_reports SET resolved_at").
+		WithArgs("rating-1").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("UPDATE product_rating_summaries s SET (.+) status = 'visible'").
+		WithArgs("prod-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("DELETE FROM rating_photos WHERE rating_id = (.+) RETURNING storage_key").
+		WithArgs("rating-1").
+		WillReturnRows(sqlmock.NewRows([]string{"storage_key"}).AddRow("ratings/rating-1/photo-1.jpg"))
+	mock.ExpectCommit()
+
+	request := &pro.ModerateRatingRequest{RatingId: "rating-1", ModeratorId: "admin-1", Action: models.ModerationHide, Reason: "Spam"}
+	rating, photoKeys, err := repo.ModerateRating(context.Background(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, models.RatingHidden, rating.Status)
+	assert.Equal(t, []string{"ratings/rating-1/photo-1.jpg"}, photoKeys)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetModerationQueue(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+	now := time.Now().Format(time.RFC3339)
+
+	mock.ExpectQuery("SELECT (.+) FROM ratings r LEFT JOIN rating_reports rr (.+) GROUP BY r.id").
+		WithArgs(int32(20), int32(0)).
+		WillReturnRows(sqlmock.NewRows(append(ratingRowColumns, "report_count", "report_reasons")).
+			AddRow("rating-1", "prod-1", "user-1", 1, "Rude", false, "visible", 0, 3, now, now, 2, "{Offensive,Spam}").
+			AddRow("rating-2", "prod-2", "user-2", 5, "Held", false, "pending", 0, 0, now, now, 0, "{}"))
+
+	items, err := repo.GetModerationQueue(context.Background(), 20, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(items))
+	assert.Equal(t, int32(2), items[0].ReportCount)
+	assert.Equal(t, []string{"Offensive", "Spam"}, items[0].ReportReasons)
+	assert.Equal(t, models.RatingPending, items[1].Rating.Status)
+}
+
+func TestAddRatingPhoto(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+	photo := &models.RatingPhoto{Id: "photo-1", RatingId: "rating-1", Key: "ratings/rating-1/photo-1.jpg", ContentType: "image/jpeg", SizeBytes: 1024}
+
+	now := time.Now().Format(time.RFC3339)
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT user_id, status FROM ratings WHERE id = (.+) FOR UPDATE").
+		WithArgs("rating-1").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "status"}).AddRow("user-1", "visible"))
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM rating_photos").
+		WithArgs("rating-1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery("INSERT INTO rating_photos (.+) RETURNING created_at").
+		WithArgs("photo-1", "rating-1", photo.Key, "image/jpeg", int64(1024)).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(now))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.AddRatingPhoto(context.Background(), photo, "user-1", 5))
+	assert.Equal(t, now, photo.CreatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddRatingPhotoLimits(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+	photo := &models.RatingPhoto{Id: "photo-1", RatingId: "rating-1", Key: "ratings/rating-1/photo-1.jpg", ContentType: "image/jpeg", SizeBytes: 1024}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT user_id, status FROM ratings WHERE id = (.+) FOR UPDATE").
+		WithArgs("rating-1").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "status"}).AddRow("user-1", "visible"))
+	mock.ExpectRollback()
+	err := repo.AddRatingPhoto(context.Background(), photo, "user-2", 5)
+	assert.EqualError(t, err, "you can only add photos to your own review")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT user_id, status FROM ratings WHERE id = (.+) FOR UPDATE").
+		WithArgs("rating-1").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "status"}).AddRow("user-1", "visible"))
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM rating_photos").
+		WithArgs("rating-1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+	mock.ExpectRollback()
+	err = repo.AddRatingPhoto(context.Background(), photo, "user-1", 5)
+	assert.EqualError(t, err, "a review can have at most 5 photos")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT user_id, status FROM ratings WHERE id = (.+) FOR UPDATE").
+		WithArgs("rating-1").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "status"}).AddRow("user-1", "hidden"))
+	mock.ExpectRollback()
+	err = repo.AddRatingPhoto(context.Background(), photo, "user-1", 5)
+	assert.EqualError(t, err, "photos can only be added to published reviews")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCanAddRatingPhoto(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+
+	mock.ExpectQuery("SELECT user_id, status FROM ratings WHERE id = \\$1 AND deleted_at IS NULL$").
+		WithArgs("rating-1").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "status"}).AddRow("user-1", "pending"))
+	err := repo.CanAddRatingPhoto(context.Background(), "rating-1", "user-1", 5)
+	assert.EqualError(t, err, "photos can only be added to published reviews")
+
+	mock.ExpectQuery("SELECT user_id, status FROM ratings WHERE id = \\$1 AND deleted_at IS NULL$").
+		WithArgs("rating-1").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "status"}).AddRow("user-1", "visible"))
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM rating_photos").
+		WithArgs("rating-1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	assert.NoError(t, repo.CanAddRatingPhoto(context.Background(), "rating-1", "user-1", 5))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRatingPhotos(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+
+	now := time.Now().Format(time.RFC3339)
+	mock.ExpectQuery("SELECT id, rating_id, storage_key, (.+) FROM rating_photos WHERE rating_id = ANY").
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "rating_id", "storage_key", "content_type", "size_bytes", "created_at"}).
+			AddRow("photo-1", "rating-1", "ratings/rating-1/photo-1.jpg", "image/jpeg", 1024, now).
+			AddRow("photo-2", "rating-1", "ratings/rating-1/photo-2.png", "image/png", 2048, now))
+
+	photos, err := repo.GetRatingPhotos(context.Background(), []string{"rating-1", "rating-2"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(photos["rating-1"]))
+	assert.Equal(t, "ratings/rating-1/photo-2.png", photos["rating-1"][1].Key)
+	assert.Equal(t, int64(2048), photos["rating-1"][1].SizeBytes)
+	assert.Equal(t, 0, len(photos["rating-2"]))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteRatingPhotoNotFound(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+
+	mock.ExpectQuery("DELETE FROM rating_photos rp USING ratings r (.+) RETURNING rp.storage_key").
+		WithArgs("photo-1", "user-2").
+		WillReturnRows(sqlmock.NewRows([]string{"storage_key"}))
+
+	_, err := repo.DeleteRatingPhoto(context.Background(), "photo-1", "user-2")
+	assert.EqualError(t, err, "photo not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
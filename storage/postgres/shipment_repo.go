@@ -0,0 +1,337 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	pro "product-service/genproto/product_service"
+	"product-service/models"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// UpdateShippingInfo records the carrier and tracking number an order ships
+// with on its shipment, creating the shipment the first time. An order that
+// is being processed moves to shipped.
+func (r *productRepo) UpdateShippingInfo(c context.Context, request *pro.UpdateShippingInfoRequest, estimatedDelivery *time.Time) (*pro.UpdateShippingInfoResponse, error) {
+	return r.saveShipment(c, request, estimatedDelivery, false)
+}
+
+// AddShipment records the first shipment of an order, as UpdateShippingInfo
+// does, but fails instead of replacing a shipment the order already has.
+func (r *productRepo) AddShipment(c context.Context, request *pro.UpdateShippingInfoRequest, estimatedDelivery *time.Time) (*pro.UpdateShippingInfoResponse, error) {
+	return r.saveShipment(c, request, estimatedDelivery, true)
+}
+
+// saveShipment records an order's shipment while holding a lock on the
+// order. firstOnly rejects orders that already have a shipment.
+func (r *productRepo) saveShipment(c context.Context, request *pro.UpdateShippingInfoRequest, estimatedDelivery *time.Time, firstOnly bool) (*pro.UpdateShippingInfoResponse, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var status string
+	err = tx.QueryRowContext(c, `
+		SELECT status FROM orders WHERE id = $1 AND deleted_at IS NULL FOR UPDATE
+	`, request.OrderId).Scan(&status)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("order not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if status == "canceled" || status == "returned" {
+		return nil, fmt.Errorf("cannot ship a %s order", status)
+	}
+
+	var eta sql.NullTime
+	if estimatedDelivery != nil {
+		eta = sql.NullTime{Time: *estimatedDelivery, Valid: true}
+	}
+	var shipmentId, carrier, trackingNumber string
+	err = tx.QueryRowContext(c, `
+		SELECT id, carrier, tracking_number FROM shipments WHERE order_id = $1 ORDER BY created_at LIMIT 1
+	`, request.OrderId).Scan(&shipmentId, &carrier, &trackingNumber)
+	if err == nil && firstOnly {
+		return nil, fmt.Errorf("order already ships with %s under tracking number %s", carrier, trackingNumber)
+	}
+	switch {
+	case err == sql.ErrNoRows:
+		shipmentId = uuid.NewString()
+		_, err = tx.ExecContext(c, `
+			INSERT INTO shipments (id, order_id, carrier, tracking_number, estimated_delivery_date)
+			VALUES ($1, $2, $3, $4, $5)
+		`, shipmentId, request.OrderId, request.Carrier, request.TrackingNumber, eta)
+	case err == nil:
+		_, err = tx.ExecContext(c, `
+			UPDATE shipments SET carrier = $1, tracking_number = $2, estimated_delivery_date = $3, updated_at = now()
+			WHERE id = $4
+		`, request.Carrier, request.TrackingNumber, eta, shipmentId)
+	}
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return nil, fmt.Errorf("tracking number %s is already used by another shipment of this order", request.TrackingNumber)
+		}
+		return nil, fmt.Errorf("error saving shipment: %v", err)
+	}
+
+	updatedAt := time.Now()
+	err = tx.QueryRowContext(c, `
+		UPDATE orders SET
+		tracking_number = $1,
+		carrier = $2,
+		estimated_delivery_date = $3,
+		status = CASE WHEN status = 'processing' THEN 'shipped' ELSE status END,
+		updated_at = $4
+		WHERE id = $5
+		RETURNING status
+	`, request.TrackingNumber, request.Carrier, eta, updatedAt, request.OrderId).Scan(&status)
+	if err != nil {
+		return nil, fmt.Errorf("error updating order: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &pro.UpdateShippingInfoResponse{
+		OrderId:               request.OrderId,
+		ShipmentId:            shipmentId,
+		TrackingNumber:        request.TrackingNumber,
+		Carrier:               request.Carrier,
+		EstimatedDeliveryDate: request.EstimatedDeliveryDate,
+		Status:                status,
+		UpdatedAt:             updatedAt.Format("2006-01-02 15:04:05"),
+	}, nil
+}
+
+// AddTrackingEvent adds an event to a shipment's timeline. The shipment takes
+// the status of its latest event. Once every shipment of an order is
+// delivered the order is delivered too, and an order being processed is
+// shipped once a parcel is on its way. A repeated event is ignored. Orders
+// sent in one parcel share its tracking number, so an event looked up by
+// carrier and tracking number applies to each of their shipments and the
+// first of them is returned.
+func (r *productRepo) AddTrackingEvent(c context.Context, event *models.TrackingEvent) (*pro.AddTrackingEventResponse, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var rows *sql.Rows
+	if event.ShipmentId != "" {
+		rows, err = tx.QueryContext(c, `
+			SELECT id, order_id FROM shipments WHERE id = $1 FOR UPDATE
+		`, event.ShipmentId)
+	} else {
+		rows, err = tx.QueryContext(c, `
+			SELECT id, order_id FROM shipments WHERE lower(carrier) = lower($1) AND tracking_number = $2
+			ORDER BY created_at, id FOR UPDATE
+		`, event.Carrier, event.TrackingNumber)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var shipmentIds, orderIds []string
+	for rows.Next() {
+		var shipmentId, orderId string
+		if err := rows.Scan(&shipmentId, &orderId); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		shipmentIds = append(shipmentIds, shipmentId)
+		orderIds = append(orderIds, orderId)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(shipmentIds) == 0 {
+		return nil, fmt.Errorf("shipment not found")
+	}
+
+	for i := range shipmentIds {
+		if err := applyTrackingEvent(c, tx, shipmentIds[i], orderIds[i], event); err != nil {
+			return nil, err
+		}
+	}
+
+	var orderStatus string
+	if err := tx.QueryRowContext(c, `SELECT status FROM orders WHERE id = $1`, orderIds[0]).Scan(&orderStatus); err != nil {
+		return nil, err
+	}
+	shipments, err := loadShipments(c, tx, `WHERE id = $1`, shipmentIds[0])
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &pro.AddTrackingEventResponse{Shipment: shipments[0], OrderStatus: orderStatus}, nil
+}
+
+// applyTrackingEvent records the event on one shipment and moves the
+// shipment and its order along.
+func applyTrackingEvent(c context.Context, tx *sql.Tx, shipmentId, orderId string, event *models.TrackingEvent) error {
+	_, err := tx.ExecContext(c, `
+		INSERT INTO tracking_events (id, shipment_id, status, location, description, occurred_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (shipment_id, status, occurred_at) DO NOTHING
+	`, uuid.NewString(), shipmentId, event.Status, event.Location, event.Description, event.OccurredAt)
+	if err != nil {
+		return fmt.Errorf("error inserting tracking event: %v", err)
+	}
+
+	var status string
+	err = tx.QueryRowContext(c, `
+		UPDATE shipments SET
+		status = (SELECT status FROM tracking_events WHERE shipment_id = $1 ORDER BY occurred_at DESC, created_at DESC LIMIT 1),
+		delivered_at = CASE WHEN $2 = 'delivered' THEN COALESCE(delivered_at, $3) ELSE delivered_at END,
+		updated_at = now()
+		WHERE id = $1
+		RETURNING status
+	`, shipmentId, event.Status, event.OccurredAt).Scan(&status)
+	if err != nil {
+		return fmt.Errorf("error updating shipment: %v", err)
+	}
+
+	switch status {
+	case models.TrackingInTransit, models.TrackingOutForDelivery:
+		_, err = tx.ExecContext(c, `
+			UPDATE orders SET status = 'shipped', updated_at = now() WHERE id = $1 AND status = 'processing'
+		`, orderId)
+	case models.TrackingDelivered:
+		_, err = tx.ExecContext(c, `
+			UPDATE orders SET status = 'delivered', delivered_at = COALESCE(delivered_at, $2), updated_at = now()
+			WHERE id = $1 AND status IN ('processing', 'shipped')
+			AND NOT EXISTS (SELECT 1 FROM shipments WHERE order_id = $1 AND status <> 'delivered')
+		`, orderId, event.OccurredAt)
+	}
+	if err != nil {
+		return fmt.Errorf("error updating order status: %v", err)
+	}
+	return nil
+}
+
+// GetShipments returns the shipments of an order with their timelines. A
+// user id limits them to that buyer's order.
+func (r *productRepo) GetShipments(c context.Context, request *pro.GetShipmentsRequest) (*pro.GetShipmentsResponse, error) {
+	where := `WHERE order_id = $1`
+	args := []interface{}{request.OrderId}
+	if request.UserId != "" {
+		where += ` AND order_id IN (SELECT id FROM orders WHERE id = $1 AND user_id = $2)`
+		args = append(args, request.UserId)
+	}
+	shipments, err := loadShipments(c, r.db, where, args...)
+	if err != nil {
+		return nil, err
+	}
+	return &pro.GetShipmentsResponse{Shipments: shipments}, nil
+}
+
+func (r *productRepo) GetShipment(c context.Context, id string) (*pro.Shipment, error) {
+	shipments, err := loadShipments(c, r.db, `WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(shipments) == 0 {
+		return nil, fmt.Errorf("shipment not found")
+	}
+	return shipments[0], nil
+}
+
+// attachOrderShipments loads the shipments of the given orders.
+func (r *productRepo) attachOrderShipments(c context.Context, orders []*pro.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+	byId := make(map[string]*pro.Order, len(orders))
+	orderIds := make([]string, 0, len(orders))
+	for _, order := range orders {
+		byId[order.Id] = order
+		orderIds = append(orderIds, order.Id)
+	}
+
+	shipments, err := loadShipments(c, r.db, `WHERE order_id = ANY($1)`, pq.Array(orderIds))
+	if err != nil {
+		return err
+	}
+	for _, shipment := range shipments {
+		if order, ok := byId[shipment.OrderId]; ok {
+			order.Shipments = append(order.Shipments, shipment)
+		}
+	}
+	return nil
+}
+
+// loadShipments returns the shipments matching where, oldest first, with
+// their tracking events in the order they happened.
+func loadShipments(c context.Context, q queryer, where string, args ...interface{}) ([]*pro.Shipment, error) {
+	rows, err := q.QueryContext(c, `
+		SELECT id, order_id, carrier, tracking_number, status,
+		COALESCE(estimated_delivery_date::text, ''), delivered_at, created_at, updated_at
+		FROM shipments
+		`+where+`
+		ORDER BY created_at, id
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error loading shipments: %v", err)
+	}
+
+	var shipments []*pro.Shipment
+	byId := make(map[string]*pro.Shipment)
+	var ids []string
+	for rows.Next() {
+		shipment := pro.Shipment{Events: []*pro.TrackingEvent{}}
+		var deliveredAt sql.NullTime
+		err := rows.Scan(&shipment.Id, &shipment.OrderId, &shipment.Carrier, &shipment.TrackingNumber, &shipment.Status,
+			&shipment.EstimatedDeliveryDate, &deliveredAt, &shipment.CreatedAt, &shipment.UpdatedAt)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if deliveredAt.Valid {
+			shipment.DeliveredAt = deliveredAt.Time.Format(time.RFC3339)
+		}
+		shipments = append(shipments, &shipment)
+		byId[shipment.Id] = &shipment
+		ids = append(ids, shipment.Id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(shipments) == 0 {
+		return shipments, nil
+	}
+
+	events, err := q.QueryContext(c, `
+		SELECT id, shipment_id, status, location, description, occurred_at, created_at
+		FROM tracking_events
+		WHERE shipment_id = ANY($1)
+		ORDER BY occurred_at, created_at
+	`, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("error loading tracking events: %v", err)
+	}
+	defer events.Close()
+
+	for events.Next() {
+		var event pro.TrackingEvent
+		var occurredAt time.Time
+		err := events.Scan(&event.Id, &event.ShipmentId, &event.Status, &event.Location, &event.Description, &occurredAt, &event.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		event.OccurredAt = occurredAt.Format(time.RFC3339)
+		if shipment, ok := byId[event.ShipmentId]; ok {
+			shipment.Events = append(shipment.Events, &event)
+		}
+	}
+	return shipments, events.Err()
+}
@@ -0,0 +1,186 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pro "product-service/genproto/product_service"
+	"product-service/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateShippingInfoOrderNotFound(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT status FROM orders WHERE id = (.+) FOR UPDATE").
+		WithArgs("order-1").
+		WillReturnRows(sqlmock.NewRows([]string{"status"}))
+	mock.ExpectRollback()
+
+	request := &pro.UpdateShippingInfoRequest{OrderId: "order-1", TrackingNumber: "TRK-1", Carrier: "dhl"}
+	_, err := repo.UpdateShippingInfo(context.Background(), request, nil)
+	assert.EqualError(t, err, "order not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateShippingInfoCreatesShipment(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+	eta := time.Date(2024, 7, 5, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT status FROM orders WHERE id = (.+) FOR UPDATE").
+		WithArgs("order-1").
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("processing"))
+	mock.ExpectQuery("SELECT id, carrier, tracking_number FROM shipments WHERE order_id").
+		WithArgs("order-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "carrier", "tracking_number"}))
+	mock.ExpectExec("INSERT INTO shipments").
+		WithArgs(sqlmock.AnyArg(), "order-1", "dhl", "TRK-1", eta).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("UPDATE orders SET (.+) RETURNING status").
+		WithArgs("TRK-1", "dhl", eta, sqlmock.AnyArg(), "order-1").
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("shipped"))
+	mock.ExpectCommit()
+
+	request := &pro.UpdateShippingInfoRequest{OrderId: "order-1", TrackingNumber: "TRK-1", Carrier: "dhl", EstimatedDeliveryDate: "2024-07-05"}
+	res, err := repo.UpdateShippingInfo(context.Background(), request, &eta)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, res.ShipmentId)
+	assert.Equal(t, "shipped", res.Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddShipmentAlreadyShips(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT status FROM orders WHERE id = (.+) FOR UPDATE").
+		WithArgs("order-1").
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("shipped"))
+	mock.ExpectQuery("SELECT id, carrier, tracking_number FROM shipments WHERE order_id").
+		WithArgs("order-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "carrier", "tracking_number"}).AddRow("shipment-1", "mock", "TRK-1"))
+	mock.ExpectRollback()
+
+	request := &pro.UpdateShippingInfoRequest{OrderId: "order-1", TrackingNumber: "TRK-2", Carrier: "mock"}
+	_, err := repo.AddShipment(context.Background(), request, nil)
+	assert.EqualError(t, err, "order already ships with mock under tracking number TRK-1")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddTrackingEventDelivered(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+	occurredAt := time.Date(2024, 7, 4, 14, 30, 0, 0, time.UTC)
+	now := time.Now().Format(time.RFC3339)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id, order_id FROM shipments WHERE lower\\(carrier\\) = lower\\(\\$1\\) AND tracking_number = \\$2 (.+) FOR UPDATE").
+		WithArgs("DHL", "TRK-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "order_id"}).AddRow("ship-1", "order-1"))
+	mock.ExpectExec("INSERT INTO tracking_events (.+) ON CONFLICT").
+		WithArgs(sqlmock.AnyArg(), "ship-1", models.TrackingDelivered, "Berlin", "", occurredAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("UPDATE shipments SET (.+) RETURNING status").
+		WithArgs("ship-1", models.TrackingDelivered, occurredAt).
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(models.TrackingDelivered))
+	mock.ExpectExec("UPDATE orders SET status = 'delivered'").
+		WithArgs("order-1", occurredAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT status FROM orders WHERE id").
+		WithArgs("order-1").
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("delivered"))
+	mock.ExpectQuery("SELECT id, order_id, carrier, (.+) FROM shipments").
+		WithArgs("ship-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "carrier", "tracking_number", "status", "estimated_delivery_date", "delivered_at", "created_at", "updated_at"}).
+			AddRow("ship-1", "order-1", "dhl", "TRK-1", "delivered", "", occurredAt, now, now))
+	mock.ExpectQuery("SELECT id, shipment_id, status, (.+) FROM tracking_events").
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "shipment_id", "status", "location", "description", "occurred_at", "created_at"}).
+			AddRow("event-1", "ship-1", "delivered", "Berlin", "", occurredAt, now))
+	mock.ExpectCommit()
+
+	event := &models.TrackingEvent{Carrier: "DHL", TrackingNumber: "TRK-1", Status: models.TrackingDelivered, Location: "Berlin", OccurredAt: occurredAt}
+	res, err := repo.AddTrackingEvent(context.Background(), event)
+	assert.NoError(t, err)
+	assert.Equal(t, "delivered", res.OrderStatus)
+	assert.Equal(t, "2024-07-04T14:30:00Z", res.Shipment.DeliveredAt)
+	assert.Equal(t, 1, len(res.Shipment.Events))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddTrackingEventSharedParcel(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+	occurredAt := time.Date(2024, 7, 3, 9, 0, 0, 0, time.UTC)
+	now := time.Now().Format(time.RFC3339)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id, order_id FROM shipments WHERE lower\\(carrier\\) = lower\\(\\$1\\) AND tracking_number = \\$2 (.+) FOR UPDATE").
+		WithArgs("DHL", "TRK-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "order_id"}).AddRow("ship-1", "order-1").AddRow("ship-2", "order-2"))
+	for _, ids := range [][2]string{{"ship-1", "order-1"}, {"ship-2", "order-2"}} {
+		mock.ExpectExec("INSERT INTO tracking_events (.+) ON CONFLICT").
+			WithArgs(sqlmock.AnyArg(), ids[0], models.TrackingInTransit, "Leipzig", "", occurredAt).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("UPDATE shipments SET (.+) RETURNING status").
+			WithArgs(ids[0], models.TrackingInTransit, occurredAt).
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(models.TrackingInTransit))
+		mock.ExpectExec("UPDATE orders SET status = 'shipped'").
+			WithArgs(ids[1]).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectQuery("SELECT status FROM orders WHERE id").
+		WithArgs("order-1").
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("shipped"))
+	mock.ExpectQuery("SELECT id, order_id, carrier, (.+) FROM shipments").
+		WithArgs("ship-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "carrier", "tracking_number", "status", "estimated_delivery_date", "delivered_at", "created_at", "updated_at"}).
+			AddRow("ship-1", "order-1", "dhl", "TRK-1", "in_transit", "", nil, now, now))
+	mock.ExpectQuery("SELECT id, shipment_id, status, (.+) FROM tracking_events").
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "shipment_id", "status", "location", "description", "occurred_at", "created_at"}).
+			AddRow("event-1", "ship-1", "in_transit", "Leipzig", "", occurredAt, now))
+	mock.ExpectCommit()
+
+	event := &models.TrackingEvent{Carrier: "DHL", TrackingNumber: "TRK-1", Status: models.TrackingInTransit, Location: "Leipzig", OccurredAt: occurredAt}
+	res, err := repo.AddTrackingEvent(context.Background(), event)
+	assert.NoError(t, err)
+	assert.Equal(t, "shipped", res.OrderStatus)
+	assert.Equal(t, "ship-1", res.Shipment.Id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddTrackingEventShipmentNotFound(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id, order_id FROM shipments WHERE id = (.+) FOR UPDATE").
+		WithArgs("ship-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "order_id"}))
+	mock.ExpectRollback()
+
+	event := &models.TrackingEvent{ShipmentId: "ship-1", Status: models.TrackingInTransit, OccurredAt: time.Now()}
+	_, err := repo.AddTrackingEvent(context.Background(), event)
+	assert.EqualError(t, err, "shipment not found")
+}
@@ -0,0 +1,211 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	pro "product-service/genproto/product_service"
+	"product-service/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const taxRuleColumns = `id, country, region, COALESCE(category_id::text, ''), name, rate::text, inclusive, created_at`
+
+type queryer interface {
+	QueryContext(c context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+type taxRuleRow struct {
+	models.TaxRule
+	CreatedAt string
+}
+
+func scanTaxRule(row rowScanner) (*taxRuleRow, error) {
+	var rule taxRuleRow
+	err := row.Scan(
+		&rule.Id,
+		&rule.Country,
+		&rule.Region,
+		&rule.CategoryId,
+		&rule.Name,
+		&rule.Rate,
+		&rule.Inclusive,
+		&rule.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (rule *taxRuleRow) toProto() *pro.TaxRule {
+	return &pro.TaxRule{
+		Id:         rule.Id,
+		Country:    rule.Country,
+		Region:     rule.Region,
+		CategoryId: rule.CategoryId,
+		Name:       rule.Name,
+		Rate:       rule.Rate,
+		Inclusive:  rule.Inclusive,
+		CreatedAt:  rule.CreatedAt,
+	}
+}
+
+func taxLinesToProto(lines []*models.TaxLine) []*pro.TaxLine {
+	res := make([]*pro.TaxLine, 0, len(lines))
+	for _, line := range lines {
+		res = append(res, &pro.TaxLine{
+			OrderItemId:   line.OrderItemId,
+			ProductId:     line.ProductId,
+			RuleId:        line.RuleId,
+			Name:          line.Name,
+			Rate:          line.Rate,
+			Inclusive:     line.Inclusive,
+			TaxableAmount: line.TaxableAmount.Float64(),
+			Amount:        line.Amount.Float64(),
+		})
+	}
+	return res
+}
+
+// loadTaxRules returns the tax rules of a destination country.
+func loadTaxRules(c context.Context, q queryer, country string) ([]*models.TaxRule, error) {
+	rows, err := q.QueryContext(c, `
+		SELECT `+taxRuleColumns+`
+		FROM tax_rules
+		WHERE country = $1 AND deleted_at IS NULL
+	`, country)
+	if err != nil {
+		return nil, fmt.Errorf("error loading tax rules: %v", err)
+	}
+	defer rows.Close()
+
+	var rules []*models.TaxRule
+	for rows.Next() {
+		rule, err := scanTaxRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, &rule.TaxRule)
+	}
+	return rules, rows.Err()
+}
+
+// insertOrderTaxLines stores the tax breakdown of an order for invoicing.
+func insertOrderTaxLines(c context.Context, tx *sql.Tx, orderId string, lines []*models.TaxLine) error {
+	for _, line := range lines {
+		_, err := tx.ExecContext(c, `
+			INSERT INTO order_tax_lines (id, order_id, order_item_id, rule_id, name, rate, inclusive, taxable_amount, amount)
+			VALUES ($1, $2, $3, NULLIF($4, '')::uuid, $5, $6, $7, $8, $9)
+		`, uuid.NewString(), orderId, line.OrderItemId, line.RuleId, line.Name, line.Rate, line.Inclusive, line.TaxableAmount, line.Amount)
+		if err != nil {
+			return fmt.Errorf("error inserting tax line: %v", err)
+		}
+	}
+	return nil
+}
+
+// attachOrderTaxLines loads the tax lines recorded for the given orders.
+func (r *productRepo) attachOrderTaxLines(c context.Context, orders []*pro.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+	byId := make(map[string]*pro.Order, len(orders))
+	orderIds := make([]string, 0, len(orders))
+	for _, order := range orders {
+		byId[order.Id] = order
+		orderIds = append(orderIds, order.Id)
+	}
+
+	rows, err := r.db.QueryContext(c, `
+		SELECT tl.order_id, tl.order_item_id, COALESCE(oi.product_id::text, ''), COALESCE(tl.rule_id::text, ''),
+		tl.name, tl.rate::text, tl.inclusive, tl.taxable_amount, tl.amount
+		FROM order_tax_lines tl
+		LEFT JOIN order_items oi ON oi.id = tl.order_item_id
+		WHERE tl.order_id = ANY($1)
+		ORDER BY tl.created_at
+	`, pq.Array(orderIds))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var orderId string
+		var line models.TaxLine
+		err := rows.Scan(&orderId, &line.OrderItemId, &line.ProductId, &line.RuleId,
+			&line.Name, &line.Rate, &line.Inclusive, &line.TaxableAmount, &line.Amount)
+		if err != nil {
+			return err
+		}
+		if order, ok := byId[orderId]; ok {
+			order.TaxLines = append(order.TaxLines, taxLinesToProto([]*models.TaxLine{&line})...)
+		}
+	}
+	return rows.Err()
+}
+
+func (r *productRepo) AddTaxRule(c context.Context, request *pro.AddTaxRuleRequest) (*pro.AddTaxRuleResponse, error) {
+	rule, err := scanTaxRule(r.db.QueryRowContext(c, `
+		INSERT INTO tax_rules (id, country, region, category_id, name, rate, inclusive)
+		VALUES ($1, $2, $3, NULLIF($4, '')::uuid, $5, $6, $7)
+		RETURNING `+taxRuleColumns,
+		uuid.NewString(), request.Country, request.Region, request.CategoryId, request.Name, request.Rate, request.Inclusive))
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return nil, fmt.Errorf("a tax rule for this country, region and category already exists")
+		}
+		return nil, fmt.Errorf("error inserting tax rule: %v", err)
+	}
+	return &pro.AddTaxRuleResponse{TaxRule: rule.toProto()}, nil
+}
+
+func (r *productRepo) GetTaxRules(c context.Context, request *pro.GetTaxRulesRequest) (*pro.GetTaxRulesResponse, error) {
+	query := `
+		SELECT ` + taxRuleColumns + `
+		FROM tax_rules
+		WHERE deleted_at IS NULL
+	`
+	var args []interface{}
+	if request.Country != "" {
+		query += " AND country = $1"
+		args = append(args, request.Country)
+	}
+	query += " ORDER BY country, region, category_id NULLS FIRST"
+
+	rows, err := r.db.QueryContext(c, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []*pro.TaxRule
+	for rows.Next() {
+		rule, err := scanTaxRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule.toProto())
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &pro.GetTaxRulesResponse{TaxRules: rules}, nil
+}
+
+func (r *productRepo) DeleteTaxRule(c context.Context, request *pro.DeleteTaxRuleRequest) (*pro.DeleteTaxRuleResponse, error) {
+	res, err := r.db.ExecContext(c, `
+		UPDATE tax_rules SET deleted_at = now() WHERE id = $1 AND deleted_at IS NULL
+	`, request.Id)
+	if err != nil {
+		return nil, err
+	}
+	if n, err := res.RowsAffected(); n == 0 || err != nil {
+		return nil, fmt.Errorf("tax rule not found")
+	}
+
+	return &pro.DeleteTaxRuleResponse{}, nil
+}
@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pro "product-service/genproto/product_service"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var taxRuleRowColumns = []string{"id", "country", "region", "category_id", "name", "rate", "inclusive", "created_at"}
+
+func TestAddTaxRule(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+	ctx := context.Background()
+	request := &pro.AddTaxRuleRequest{Country: "DE", CategoryId: "cat-books", Name: "MwSt ermäßigt", Rate: "0.07", Inclusive: true}
+
+	mock.ExpectQuery("INSERT INTO tax_rules").
+		WithArgs(sqlmock.AnyArg(), "DE", "", "cat-books", request.Name, "0.07", true).
+		WillReturnRows(sqlmock.NewRows(taxRuleRowColumns).
+			AddRow("rule-1", "DE", "", "cat-books", request.Name, "0.070000", true, time.Now()))
+
+	response, err := repo.AddTaxRule(ctx, request)
+	assert.NoError(t, err)
+	assert.Equal(t, "rule-1", response.TaxRule.Id)
+	assert.Equal(t, "0.070000", response.TaxRule.Rate)
+	assert.True(t, response.TaxRule.Inclusive)
+
+	mock.ExpectQuery("INSERT INTO tax_rules").
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	_, err = repo.AddTaxRule(ctx, request)
+	assert.EqualError(t, err, "a tax rule for this country, region and category already exists")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTaxRules(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+	ctx := context.Background()
+
+	mock.ExpectQuery("SELECT (.+) FROM tax_rules WHERE deleted_at IS NULL AND country = (.+) ORDER BY").
+		WithArgs("US").
+		WillReturnRows(sqlmock.NewRows(taxRuleRowColumns).
+			AddRow("rule-1", "US", "NY", "", "NY sales tax", "0.040000", false, time.Now()))
+
+	response, err := repo.GetTaxRules(ctx, &pro.GetTaxRulesRequest{Country: "US"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(response.TaxRules))
+	assert.Equal(t, "NY", response.TaxRules[0].Region)
+
+	mock.ExpectExec("UPDATE tax_rules SET deleted_at").
+		WithArgs("rule-2").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	_, err = repo.DeleteTaxRule(ctx, &pro.DeleteTaxRuleRequest{Id: "rule-2"})
+	assert.EqualError(t, err, "tax rule not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,113 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"product-service/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func finishWebhookEvent(c context.Context, tx *sqlx.Tx, event *models.PaymentEvent, outcome, note string) error {
+	_, err := tx.ExecContext(c, `
+		UPDATE payment_webhook_events
+		SET outcome = $1, note = NULLIF($2, ''), processed_at = now()
+		WHERE provider = $3 AND event_id = $4
+	`, outcome, note, event.Provider, event.EventId)
+	if err != nil {
+		return fmt.Errorf("error updating webhook event: %v", err)
+	}
+	return nil
+}
+
+// ApplyPaymentEvent records a provider webhook event and moves the payment it
+// refers to, all in one transaction. An event seen before is reported as a
+// duplicate without touching anything. Events that no longer apply, because
+// the payment already reached the status or cannot move to it, or its order
+// is no longer pending, are recorded as ignored so the provider stops
+// retrying them.
+func (r *productRepo) ApplyPaymentEvent(c context.Context, event *models.PaymentEvent) (string, error) {
+	tx, err := r.db.BeginTxx(c, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(c, `
+		INSERT INTO payment_webhook_events (provider, event_id, type, transaction_id, payload)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (provider, event_id) DO NOTHING
+	`, event.Provider, event.EventId, event.Type, event.TransactionId, event.Payload)
+	if err != nil {
+		return "", fmt.Errorf("error inserting webhook event: %v", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return "", err
+	} else if n == 0 {
+		return models.PaymentEventDuplicate, nil
+	}
+
+	outcome, note := models.PaymentEventProcessed, ""
+	var paymentId, status string
+	err = tx.QueryRowContext(c, `
+		SELECT id, status FROM payments
+		WHERE provider = $1 AND transaction_id = $2 AND deleted_at IS NULL
+		FOR UPDATE
+	`, event.Provider, event.TransactionId).Scan(&paymentId, &status)
+	switch {
+	case err == sql.ErrNoRows:
+		outcome, note = models.PaymentEventIgnored, "unknown transaction"
+	case err != nil:
+		return "", err
+	case event.To == "":
+		outcome, note = models.PaymentEventIgnored, "unhandled event type"
+	case status == event.To:
+		outcome, note = models.PaymentEventIgnored, "payment is already "+status
+	default:
+		if err := models.ValidatePaymentTransition(status, event.To); err != nil {
+			outcome, note = models.PaymentEventIgnored, err.Error()
+			break
+		}
+		if event.OrderStatus != "" {
+			// Deliveries come out of order: the order may have been canceled
+			// or paid otherwise since.
+			var orderStatus string
+			err := tx.QueryRowContext(c, `
+				SELECT o.status FROM orders o
+				JOIN payments p ON p.order_id = o.id
+				WHERE p.id = $1
+				FOR UPDATE OF o
+			`, paymentId).Scan(&orderStatus)
+			if err != nil && err != sql.ErrNoRows {
+				return "", err
+			}
+			if orderStatus != "pending" {
+				outcome, note = models.PaymentEventIgnored, "stale event: order is "+orderStatus
+				break
+			}
+		}
+		var responses []*models.ProviderResponse
+		if event.Response != nil {
+			responses = append(responses, event.Response)
+		}
+		_, err := r.transitionPayment(c, tx, &models.PaymentTransition{
+			PaymentId:   paymentId,
+			To:          event.To,
+			Reason:      event.Reason,
+			Responses:   responses,
+			OrderStatus: event.OrderStatus,
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if err := finishWebhookEvent(c, tx, event, outcome, note); err != nil {
+		return "", err
+	}
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("error committing transaction: %v", err)
+	}
+	return outcome, nil
+}
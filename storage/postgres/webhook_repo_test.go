@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"product-service/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyPaymentEvent(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+	ctx := context.Background()
+	now := time.Now().Format(time.RFC3339)
+	payload := []byte(`{"id":"evt_1","type":"payment.captured","data":{"transaction_id":"fake_000001","amount":50}}`)
+	event := &models.PaymentEvent{
+		Provider:      "fake",
+		EventId:       "evt_1",
+		Type:          "payment.captured",
+		TransactionId: "fake_000001",
+		Payload:       payload,
+		To:            models.PaymentCaptured,
+		OrderStatus:   "processing",
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO payment_webhook_events").
+		WithArgs("fake", "evt_1", "payment.captured", "fake_000001", payload).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT id, status FROM payments").
+		WithArgs("fake", "fake_000001").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow("pay-1", "authorized"))
+	mock.ExpectQuery("SELECT o.status FROM orders o").
+		WithArgs("pay-1").
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("pending"))
+	mock.ExpectQuery("SELECT status FROM payments").
+		WithArgs("pay-1").
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("authorized"))
+	mock.ExpectQuery("UPDATE payments").
+		WithArgs(models.PaymentCaptured, "", "pay-1").
+		WillReturnRows(sqlmock.NewRows(paymentRowColumns).
+			AddRow("pay-1", "order-1", 50.0, "captured", "fake_000001", "credit-card", now, "fake", now, "USD"))
+	mock.ExpectExec("INSERT INTO payment_status_history").
+		WithArgs(sqlmock.AnyArg(), "pay-1", "authorized", "captured", "").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE orders SET status").
+		WithArgs("processing", "order-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE payment_webhook_events").
+		WithArgs(models.PaymentEventProcessed, "", "fake", "evt_1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	outcome, err := repo.ApplyPaymentEvent(ctx, event)
+	assert.NoError(t, err)
+	assert.Equal(t, models.PaymentEventProcessed, outcome)
+
+	// The same event delivered again is acknowledged without side effects.
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO payment_webhook_events").
+		WithArgs("fake", "evt_1", "payment.captured", "fake_000001", payload).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	outcome, err = repo.ApplyPaymentEvent(ctx, event)
+	assert.NoError(t, err)
+	assert.Equal(t, models.PaymentEventDuplicate, outcome)
+
+	// A capture confirmed after the synchronous flow already recorded it.
+	event.EventId = "evt_2"
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO payment_webhook_events").
+		WithArgs("fake", "evt_2", "payment.captured", "fake_000001", payload).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT id, status FROM payments").
+		WithArgs("fake", "fake_000001").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow("pay-1", "captured"))
+	mock.ExpectExec("UPDATE payment_webhook_events").
+		WithArgs(models.PaymentEventIgnored, "payment is already captured", "fake", "evt_2").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	outcome, err = repo.ApplyPaymentEvent(ctx, event)
+	assert.NoError(t, err)
+	assert.Equal(t, models.PaymentEventIgnored, outcome)
+
+	// A capture arriving after the order was canceled is acknowledged and
+	// left alone.
+	event.EventId = "evt_3"
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO payment_webhook_events").
+		WithArgs("fake", "evt_3", "payment.captured", "fake_000001", payload).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT id, status FROM payments").
+		WithArgs("fake", "fake_000001").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow("pay-1", "authorized"))
+	mock.ExpectQuery("SELECT o.status FROM orders o").
+		WithArgs("pay-1").
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("canceled"))
+	mock.ExpectExec("UPDATE payment_webhook_events").
+		WithArgs(models.PaymentEventIgnored, "stale event: order is canceled", "fake", "evt_3").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	outcome, err = repo.ApplyPaymentEvent(ctx, event)
+	assert.NoError(t, err)
+	assert.Equal(t, models.PaymentEventIgnored, outcome)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,271 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	pro "product-service/genproto/product_service"
+	"product-service/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+func (r *productRepo) CreateWishlist(c context.Context, request *pro.CreateWishlistRequest) (*pro.Wishlist, error) {
+	wishlist := pro.Wishlist{Items: []*pro.WishlistItem{}}
+	err := r.db.QueryRowContext(c, `
+		INSERT INTO wishlists (id, user_id, name)
+		VALUES ($1, $2, $3)
+		RETURNING id, user_id, name, created_at, updated_at
+	`, uuid.NewString(), request.UserId, request.Name).
+		Scan(&wishlist.Id, &wishlist.UserId, &wishlist.Name, &wishlist.CreatedAt, &wishlist.UpdatedAt)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return nil, fmt.Errorf("a wishlist named %s already exists", request.Name)
+		}
+		return nil, fmt.Errorf("error inserting wishlist: %v", err)
+	}
+	return &wishlist, nil
+}
+
+// GetWishlists returns the user's wishlists, oldest first, with the current
+// price and stock of every product on them.
+func (r *productRepo) GetWishlists(c context.Context, userId string) ([]*pro.Wishlist, error) {
+	return r.wishlists(c, `WHERE w.user_id = $1 AND w.deleted_at IS NULL`, userId)
+}
+
+// GetWishlist returns one of the user's wishlists.
+func (r *productRepo) GetWishlist(c context.Context, id, userId string) (*pro.Wishlist, error) {
+	wishlists, err := r.wishlists(c, `WHERE w.id = $1 AND w.user_id = $2 AND w.deleted_at IS NULL`, id, userId)
+	if err != nil {
+		return nil, err
+	}
+	if len(wishlists) == 0 {
+		return nil, fmt.Errorf("wishlist not found")
+	}
+	return wishlists[0], nil
+}
+
+func (r *productRepo) wishlists(c context.Context, where string, args ...interface{}) ([]*pro.Wishlist, error) {
+	rows, err := r.db.QueryContext(c, `
+		SELECT w.id, w.user_id, w.name, w.created_at, w.updated_at
+		FROM wishlists w
+		`+where+`
+		ORDER BY w.created_at, w.id
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting wishlists: %v", err)
+	}
+	defer rows.Close()
+
+	var wishlists []*pro.Wishlist
+	byId := make(map[string]*pro.Wishlist)
+	var ids []string
+	for rows.Next() {
+		wishlist := pro.Wishlist{Items: []*pro.WishlistItem{}}
+		if err := rows.Scan(&wishlist.Id, &wishlist.UserId, &wishlist.Name, &wishlist.CreatedAt, &wishlist.UpdatedAt); err != nil {
+			return nil, err
+		}
+		wishlists = append(wishlists, &wishlist)
+		byId[wishlist.Id] = &wishlist
+		ids = append(ids, wishlist.Id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(wishlists) == 0 {
+		return wishlists, nil
+	}
+
+	items, err := r.db.QueryContext(c, `
+		SELECT wi.wishlist_id, wi.product_id, p.name, p.price, p.currency, p.quantity,
+		p.deleted_at IS NOT NULL, wi.added_price, wi.created_at
+		FROM wishlist_items wi
+		JOIN products p ON p.id = wi.product_id
+		WHERE wi.wishlist_id = ANY($1)
+		ORDER BY wi.created_at, wi.product_id
+	`, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("error getting wishlist items: %v", err)
+	}
+	defer items.Close()
+
+	for items.Next() {
+		var wishlistId string
+		var item pro.WishlistItem
+		var price, addedPrice models.Money
+		err := items.Scan(&wishlistId, &item.ProductId, &item.ProductName, &price, &item.Currency,
+			&item.AvailableQuantity, &item.Unavailable, &addedPrice, &item.AddedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning wishlist item: %v", err)
+		}
+		item.Price = price.Float64()
+		item.AddedPrice = addedPrice.Float64()
+		item.InStock = !item.Unavailable && item.AvailableQuantity > 0
+		if wishlist, ok := byId[wishlistId]; ok {
+			wishlist.Items = append(wishlist.Items, &item)
+		}
+	}
+	return wishlists, items.Err()
+}
+
+func (r *productRepo) DeleteWishlist(c context.Context, request *pro.DeleteWishlistRequest) (*pro.DeleteWishlistResponse, error) {
+	res, err := r.db.ExecContext(c, `
+		UPDATE wishlists SET deleted_at = now() WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+	`, request.Id, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if n, err := res.RowsAffected(); n == 0 || err != nil {
+		return nil, fmt.Errorf("wishlist not found")
+	}
+
+	return &pro.DeleteWishlistResponse{}, nil
+}
+
+// AddWishlistItem puts a product on one of the user's wishlists, remembering
+// its current price and stock to alert on later changes. Adding a product
+// that is already on the list does nothing.
+func (r *productRepo) AddWishlistItem(c context.Context, request *pro.AddWishlistItemRequest) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var owner string
+	err = tx.QueryRowContext(c, `
+		SELECT user_id FROM wishlists WHERE id = $1 AND deleted_at IS NULL FOR UPDATE
+	`, request.WishlistId).Scan(&owner)
+	if err == sql.ErrNoRows || (err == nil && owner != request.UserId) {
+		return fmt.Errorf("wishlist not found")
+	}
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(c, `
+		INSERT INTO wishlist_items (wishlist_id, product_id, added_price, last_price, last_currency, last_in_stock)
+		SELECT $1, id, price, price, currency, quantity > 0 FROM products WHERE id = $2 AND deleted_at IS NULL
+		ON CONFLICT (wishlist_id, product_id) DO NOTHING
+	`, request.WishlistId, request.ProductId)
+	if err != nil {
+		return fmt.Errorf("error adding wishlist item: %v", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("error adding wishlist item: %v", err)
+	} else if n == 0 {
+		var exists bool
+		err := tx.QueryRowContext(c, `SELECT EXISTS (SELECT 1 FROM products WHERE id = $1 AND deleted_at IS NULL)`, request.ProductId).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("product not found")
+		}
+	}
+
+	if _, err := tx.ExecContext(c, `UPDATE wishlists SET updated_at = now() WHERE id = $1`, request.WishlistId); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *productRepo) RemoveWishlistItem(c context.Context, request *pro.RemoveWishlistItemRequest) error {
+	res, err := r.db.ExecContext(c, `
+		DELETE FROM wishlist_items wi
+		USING wishlists w
+		WHERE w.id = wi.wishlist_id AND wi.wishlist_id = $1 AND wi.product_id = $2
+		AND w.user_id = $3 AND w.deleted_at IS NULL
+	`, request.WishlistId, request.ProductId, request.UserId)
+	if err != nil {
+		return fmt.Errorf("error removing wishlist item: %v", err)
+	}
+	if n, err := res.RowsAffected(); n == 0 || err != nil {
+		return fmt.Errorf("product is not on the wishlist")
+	}
+	return nil
+}
+
+// WishlistAlerts compares a product with what the buyers who wishlisted it
+// were last told, returns an alert for every price drop and restock, and
+// records the product's current state so each change is alerted once. A
+// change of currency is recorded without an alert, and a price drop while out
+// of stock is only reported with the restock.
+func (r *productRepo) WishlistAlerts(c context.Context, productId string) ([]*models.WishlistAlert, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var name, currency string
+	var price models.Money
+	var quantity int32
+	err = tx.QueryRowContext(c, `
+		SELECT name, price, currency, quantity FROM products WHERE id = $1 AND deleted_at IS NULL
+	`, productId).Scan(&name, &price, &currency, &quantity)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	inStock := quantity > 0
+
+	rows, err := tx.QueryContext(c, `
+		SELECT wi.wishlist_id, w.user_id, w.name, wi.last_price, wi.last_currency, wi.last_in_stock
+		FROM wishlist_items wi
+		JOIN wishlists w ON w.id = wi.wishlist_id
+		WHERE wi.product_id = $1 AND w.deleted_at IS NULL
+		AND (wi.last_price <> $2 OR wi.last_currency <> $3 OR wi.last_in_stock <> $4)
+		ORDER BY wi.created_at
+		FOR UPDATE OF wi
+	`, productId, price, currency, inStock)
+	if err != nil {
+		return nil, fmt.Errorf("error loading wishlist items: %v", err)
+	}
+
+	var alerts []*models.WishlistAlert
+	for rows.Next() {
+		alert := models.WishlistAlert{
+			ProductId:   productId,
+			ProductName: name,
+			Price:       price,
+			Currency:    currency,
+			Quantity:    quantity,
+		}
+		var lastCurrency string
+		var lastInStock bool
+		err := rows.Scan(&alert.WishlistId, &alert.UserId, &alert.WishlistName, &alert.OldPrice, &lastCurrency, &lastInStock)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		switch {
+		case inStock && !lastInStock:
+			alert.Kind = models.WishlistBackInStock
+		case inStock && lastCurrency == currency && price < alert.OldPrice:
+			alert.Kind = models.WishlistPriceDrop
+		default:
+			continue
+		}
+		alerts = append(alerts, &alert)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(c, `
+		UPDATE wishlist_items SET last_price = $2, last_currency = $3, last_in_stock = $4
+		WHERE product_id = $1
+	`, productId, price, currency, inStock)
+	if err != nil {
+		return nil, fmt.Errorf("error updating wishlist items: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
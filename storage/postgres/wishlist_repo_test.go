@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	pro "product-service/genproto/product_service"
+	"product-service/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWishlistAlerts(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+	ctx := context.Background()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT name, price, currency, quantity FROM products").
+		WithArgs("prod-1").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "price", "currency", "quantity"}).AddRow("Vase", "19.99", "USD", 3))
+	mock.ExpectQuery("SELECT (.+) FROM wishlist_items wi JOIN wishlists w (.+) FOR UPDATE").
+		WithArgs("prod-1", "19.99", "USD", true).
+		WillReturnRows(sqlmock.NewRows([]string{"wishlist_id", "user_id", "name", "last_price", "last_currency", "last_in_stock"}).
+			AddRow("list-1", "user-1", "Birthday", "25.00", "USD", true).
+			AddRow("list-2", "user-2", "Later", "19.99", "USD", false).
+			AddRow("list-3", "user-3", "Gifts", "15.00", "USD", true).
+			AddRow("list-4", "user-4", "Home", "30.00", "EUR", true))
+	mock.ExpectExec("UPDATE wishlist_items SET last_price").
+		WithArgs("prod-1", "19.99", "USD", true).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectCommit()
+
+	alerts, err := repo.WishlistAlerts(ctx, "prod-1")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(alerts))
+	assert.Equal(t, models.WishlistPriceDrop, alerts[0].Kind)
+	assert.Equal(t, "user-1", alerts[0].UserId)
+	assert.Equal(t, models.Money(2500), alerts[0].OldPrice)
+	assert.Equal(t, models.Money(1999), alerts[0].Price)
+	assert.Equal(t, models.WishlistBackInStock, alerts[1].Kind)
+	assert.Equal(t, "list-2", alerts[1].WishlistId)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddWishlistItem(t *testing.T) {
+	db, mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	repo := NewProductRepo(db)
+	ctx := context.Background()
+	request := &pro.AddWishlistItemRequest{UserId: "user-1", WishlistId: "list-1", ProductId: "prod-1"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT user_id FROM wishlists").
+		WithArgs("list-1").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow("user-1"))
+	mock.ExpectExec("INSERT INTO wishlist_items (.+) ON CONFLICT").
+		WithArgs("list-1", "prod-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE wishlists SET updated_at").
+		WithArgs("list-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	assert.NoError(t, repo.AddWishlistItem(ctx, request))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT user_id FROM wishlists").
+		WithArgs("list-1").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow("user-2"))
+	mock.ExpectRollback()
+	assert.EqualError(t, repo.AddWishlistItem(ctx, request), "wishlist not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
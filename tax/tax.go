@@ -0,0 +1,115 @@
+package tax
+
+import (
+	"fmt"
+	"math/big"
+	"product-service/models"
+	"strconv"
+	"strings"
+)
+
+// Line is an order item to tax. Amount is the listed price times quantity.
+type Line struct {
+	OrderItemId string
+	ProductId   string
+	CategoryId  string
+	Amount      models.Money
+}
+
+// Result is the tax breakdown of an order. Subtotal is the sum of the listed
+// line amounts; Total adds the exclusive taxes on top of it, while inclusive
+// taxes are already part of the subtotal.
+type Result struct {
+	Lines    []*models.TaxLine
+	Subtotal models.Money
+	Tax      models.Money
+	Total    models.Money
+}
+
+// ParseRate reads a rule rate, which must be a fraction in [0, 1).
+func ParseRate(rate string) (*big.Rat, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(rate))
+	if !ok || r.Sign() < 0 || r.Cmp(big.NewRat(1, 1)) >= 0 {
+		return nil, fmt.Errorf("invalid tax rate %q, expected a fraction such as 0.2", rate)
+	}
+	return r, nil
+}
+
+// Match returns the most specific rule for a line shipped to region: a rule
+// for the region and category beats one for the region, which beats one for
+// the category, which beats the country-wide rule. Rules are expected to be
+// for the destination country already.
+func Match(rules []*models.TaxRule, region, categoryId string) *models.TaxRule {
+	var best *models.TaxRule
+	bestScore := -1
+	for _, rule := range rules {
+		score := 0
+		if rule.Region != "" {
+			if !strings.EqualFold(rule.Region, region) {
+				continue
+			}
+			score += 2
+		}
+		if rule.CategoryId != "" {
+			if rule.CategoryId != categoryId {
+				continue
+			}
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = rule, score
+		}
+	}
+	return best
+}
+
+// Calculate taxes every line with its matching rule. Lines no rule matches
+// are not taxed. Each tax amount is rounded half away from zero to the cent.
+func Calculate(rules []*models.TaxRule, region string, lines []Line) (*Result, error) {
+	res := &Result{}
+	for _, line := range lines {
+		res.Subtotal += line.Amount
+
+		rule := Match(rules, region, line.CategoryId)
+		if rule == nil {
+			continue
+		}
+		rate, err := ParseRate(rule.Rate)
+		if err != nil {
+			return nil, fmt.Errorf("tax rule %s: %v", rule.Id, err)
+		}
+
+		gross := new(big.Rat).SetInt64(int64(line.Amount))
+		tax := new(big.Rat).Mul(gross, rate)
+		if rule.Inclusive {
+			// The listed price is net * (1 + rate), so the tax in it is
+			// price * rate / (1 + rate).
+			tax.Quo(tax, new(big.Rat).Add(big.NewRat(1, 1), rate))
+		}
+		cents, err := strconv.ParseInt(tax.FloatString(0), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("tax amount out of range: %v", err)
+		}
+		amount := models.Money(cents)
+
+		taxable := line.Amount
+		if rule.Inclusive {
+			taxable -= amount
+		} else {
+			res.Total += amount
+		}
+		res.Tax += amount
+		res.Lines = append(res.Lines, &models.TaxLine{
+			OrderItemId:   line.OrderItemId,
+			ProductId:     line.ProductId,
+			RuleId:        rule.Id,
+			Name:          rule.Name,
+			Rate:          rule.Rate,
+			Inclusive:     rule.Inclusive,
+			TaxableAmount: taxable,
+			Amount:        amount,
+		})
+	}
+	res.Total += res.Subtotal
+	return res, nil
+}
@@ -0,0 +1,69 @@
+package tax
+
+import (
+	"testing"
+
+	"product-service/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var rules = []*models.TaxRule{
+	{Id: "de", Country: "DE", Name: "MwSt", Rate: "0.19", Inclusive: true},
+	{Id: "de-books", Country: "DE", CategoryId: "books", Name: "MwSt ermäßigt", Rate: "0.07", Inclusive: true},
+	{Id: "us-ny", Country: "US", Region: "NY", Name: "NY sales tax", Rate: "0.04"},
+	{Id: "us-ny-food", Country: "US", Region: "NY", CategoryId: "food", Name: "NY food", Rate: "0"},
+	{Id: "us-food", Country: "US", CategoryId: "food", Name: "Food", Rate: "0.01"},
+}
+
+func TestMatch(t *testing.T) {
+	assert.Equal(t, "de", Match(rules[:2], "", "pottery").Id)
+	assert.Equal(t, "de-books", Match(rules[:2], "Berlin", "books").Id)
+	assert.Equal(t, "us-ny", Match(rules[2:], "ny", "pottery").Id)
+	assert.Equal(t, "us-ny-food", Match(rules[2:], "NY", "food").Id)
+	assert.Equal(t, "us-food", Match(rules[2:], "CA", "food").Id)
+	assert.Nil(t, Match(rules[2:], "CA", "pottery"))
+}
+
+func TestCalculateInclusive(t *testing.T) {
+	res, err := Calculate(rules[:2], "", []Line{
+		{OrderItemId: "item-1", CategoryId: "pottery", Amount: 11900},
+		{OrderItemId: "item-2", CategoryId: "books", Amount: 1070},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, models.Money(12970), res.Subtotal)
+	assert.Equal(t, models.Money(1970), res.Tax)
+	// Inclusive taxes do not change what the buyer pays.
+	assert.Equal(t, models.Money(12970), res.Total)
+	assert.Equal(t, 2, len(res.Lines))
+	assert.Equal(t, models.Money(10000), res.Lines[0].TaxableAmount)
+	assert.Equal(t, models.Money(1900), res.Lines[0].Amount)
+	assert.Equal(t, models.Money(70), res.Lines[1].Amount)
+}
+
+func TestCalculateExclusive(t *testing.T) {
+	res, err := Calculate(rules[2:], "NY", []Line{
+		{OrderItemId: "item-1", CategoryId: "pottery", Amount: 1999},
+		{OrderItemId: "item-2", CategoryId: "food", Amount: 500},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, models.Money(2499), res.Subtotal)
+	// 19.99 * 4% = 0.7996, rounded to 0.80.
+	assert.Equal(t, models.Money(80), res.Tax)
+	assert.Equal(t, models.Money(2579), res.Total)
+	assert.Equal(t, models.Money(0), res.Lines[1].Amount)
+
+	res, err = Calculate(nil, "", []Line{{Amount: 100}})
+	assert.NoError(t, err)
+	assert.Empty(t, res.Lines)
+	assert.Equal(t, models.Money(100), res.Total)
+}
+
+func TestParseRate(t *testing.T) {
+	_, err := ParseRate("0.2")
+	assert.NoError(t, err)
+	for _, rate := range []string{"", "-0.1", "1", "20%"} {
+		_, err := ParseRate(rate)
+		assert.Error(t, err, rate)
+	}
+}